@@ -19,6 +19,7 @@ package v1alpha1
 import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // VolumeResizeTarget specifies a volume to resize
@@ -35,6 +36,289 @@ type VolumeResizeTarget struct {
 	// StorageClass is the storage class to use for the new PVC. Defaults to the original PVC's storage class.
 	// +optional
 	StorageClass *string `json:"storageClass,omitempty"`
+
+	// Block configures how the data is copied when the volume uses volumeMode Block.
+	// Required for block-mode volumes, ignored otherwise.
+	// +optional
+	Block *BlockCopySpec `json:"block,omitempty"`
+}
+
+// BlockCopySpec configures how a raw block volume is copied onto the smaller device
+type BlockCopySpec struct {
+	// Mode selects the copy strategy. Filesystem shrinks the ext2/3/4 filesystem found on the
+	// source device to the new size and copies its blocks. Length copies the first Length bytes.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=Filesystem;Length
+	Mode string `json:"mode"`
+
+	// Length is the number of bytes at the start of the device holding application data.
+	// Required when Mode is Length, must not exceed NewSize.
+	// +optional
+	Length *resource.Quantity `json:"length,omitempty"`
+}
+
+// ParentPauseHook stops the controller owning the StatefulSet from recreating or reverting it while it is deleted
+type ParentPauseHook struct {
+	// Type selects how the owner is paused. Annotation sets an annotation on the owning object, ScaleDown
+	// scales the Deployment of the operator managing the owner to zero, None migrates without pausing anything.
+	// +kubebuilder:validation:Enum=Annotation;ScaleDown;None
+	Type string `json:"type"`
+
+	// AnnotationKey is set on the owner by the Annotation type, e.g. strimzi.io/pause-reconciliation
+	// +optional
+	AnnotationKey string `json:"annotationKey,omitempty"`
+
+	// AnnotationValue is the value of annotationKey while the migration runs
+	// +optional
+	AnnotationValue string `json:"annotationValue,omitempty"`
+
+	// Deployment is the operator Deployment scaled to zero by the ScaleDown type
+	// +optional
+	Deployment *DeploymentReference `json:"deployment,omitempty"`
+}
+
+// GitOpsSpec controls the integration with the GitOps tool deploying the StatefulSet
+type GitOpsSpec struct {
+	// Suspend pauses the auto-sync of the Argo CD Application, or suspends the Flux Kustomization or
+	// HelmRelease, tracking the StatefulSet while the migration runs, so they do not recreate it from Git
+	// with the old sizes while it is deleted
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// ArgoCDNamespace is the namespace of the Argo CD Applications. Defaults to argocd.
+	// +optional
+	ArgoCDNamespace string `json:"argoCDNamespace,omitempty"`
+}
+
+// ScheduleSpec restricts when replicas may be taken down
+type ScheduleSpec struct {
+	// NotBefore holds the migration until this time
+	// +optional
+	NotBefore *metav1.Time `json:"notBefore,omitempty"`
+
+	// Windows are the recurring periods in which replicas may start migrating. Replicas started in a
+	// window are finished even if it closes. Without windows, replicas start at any time.
+	// +optional
+	Windows []MaintenanceWindow `json:"windows,omitempty"`
+
+	// TimeZone is the IANA name of the time zone of the windows, e.g. Europe/Paris. Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// MaintenanceWindow is a recurring period opening on a cron schedule
+type MaintenanceWindow struct {
+	// Start is a five-field cron expression (minute hour day-of-month month day-of-week) of when the window opens
+	// +kubebuilder:validation:MinLength=1
+	Start string `json:"start"`
+
+	// Duration is how long the window stays open, e.g. 4h
+	Duration metav1.Duration `json:"duration"`
+}
+
+// CanarySpec migrates one replica first and watches it before the rest of the StatefulSet follows
+type CanarySpec struct {
+	// Replica is the ordinal migrated first. Defaults to the lowest replica.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Replica *int32 `json:"replica,omitempty"`
+
+	// SoakDuration is how long the canary pod must stay ready once it came back on its new volumes.
+	// It must also become ready within that time.
+	// +kubebuilder:default="30m"
+	// +optional
+	SoakDuration metav1.Duration `json:"soakDuration,omitempty"`
+
+	// MaxRestarts is the number of container restarts of the canary pod tolerated during the soak
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxRestarts int32 `json:"maxRestarts,omitempty"`
+
+	// OnFailure decides what happens when the canary restarts too often or goes unready. Abort stops the
+	// migration and keeps the canary on its new volumes, Rollback also moves it back to its old volumes.
+	// +kubebuilder:validation:Enum=Abort;Rollback
+	// +kubebuilder:default=Abort
+	// +optional
+	OnFailure string `json:"onFailure,omitempty"`
+}
+
+// HealthGate is a check that must pass once migrated replicas are ready, before the next ones are taken down.
+// Exactly one of httpGet, exec or job is set.
+type HealthGate struct {
+	// Name identifies the gate in the status and events. It is a DNS label, gate Jobs are named after it.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// HTTPGet requests a path on every migrated pod, an answer between 200 and 399 passes
+	// +optional
+	HTTPGet *HTTPGetHealthGate `json:"httpGet,omitempty"`
+
+	// Exec runs a command in every migrated pod, exit code 0 passes
+	// +optional
+	Exec *ExecHealthGate `json:"exec,omitempty"`
+
+	// Job runs a Job once per batch, its completion passes. A failed Job is run again.
+	// +optional
+	Job *JobHealthGate `json:"job,omitempty"`
+}
+
+// HTTPGetHealthGate requests a path on the pod IP, like an httpGet probe
+type HTTPGetHealthGate struct {
+	// Path to request, defaults to /
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Port is the number or the name of a container port of the pod
+	// +kubebuilder:validation:XIntOrString
+	Port intstr.IntOrString `json:"port"`
+
+	// Scheme is HTTP or HTTPS. The certificate is not verified.
+	// +kubebuilder:validation:Enum=HTTP;HTTPS
+	// +kubebuilder:default=HTTP
+	// +optional
+	Scheme string `json:"scheme,omitempty"`
+}
+
+// ExecHealthGate runs a command in a container of the pod
+type ExecHealthGate struct {
+	// Container to run the command in, defaults to the first container of the pod
+	// +optional
+	Container string `json:"container,omitempty"`
+
+	// Command is run without a shell, wrap it in sh -c for one
+	// +kubebuilder:validation:MinItems=1
+	Command []string `json:"command"`
+}
+
+// JobHealthGate runs a single container Job in the namespace of the VolumeResize
+type JobHealthGate struct {
+	// Image of the container
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+
+	// Command of the container, defaults to the entrypoint of the image
+	// +optional
+	Command []string `json:"command,omitempty"`
+
+	// ServiceAccountName the Job runs as, defaults to the default service account
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+}
+
+// MigrationHooks are run around the migration of every replica, e.g. to drain a node of the application
+// before its pod is deleted and to rejoin it once it is back
+type MigrationHooks struct {
+	// PreStop hooks run against every replica of a batch before its pod is deleted
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	PreStop []ReplicaHook `json:"preStop,omitempty"`
+
+	// PostCopy hooks run once the volumes of every replica of a batch were copied, while its pod is still
+	// down. Only job hooks can run then.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	PostCopy []ReplicaHook `json:"postCopy,omitempty"`
+
+	// PostStart hooks run against every replica of a batch once its pod is ready again, before the health gates
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	PostStart []ReplicaHook `json:"postStart,omitempty"`
+}
+
+// ReplicaHook runs an action for one replica. Exactly one of exec or job is set. $(REPLICA) and $(POD_NAME)
+// in the command are replaced with the ordinal and the pod name of the replica.
+type ReplicaHook struct {
+	// Name identifies the hook in the status and events. It is a DNS label, hook Jobs are named after it.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// Exec runs a command in the pod of the replica, exit code 0 succeeds
+	// +optional
+	Exec *ExecHealthGate `json:"exec,omitempty"`
+
+	// Job runs a Job for the replica, its completion succeeds. REPLICA and POD_NAME are set in its environment.
+	// +optional
+	Job *JobHealthGate `json:"job,omitempty"`
+
+	// Timeout is how long the hook may run before it counts as failed. Exec hooks run inside the
+	// reconcile loop and are capped at 30s, use a Job for longer work.
+	// +kubebuilder:default="5m"
+	// +optional
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+
+	// FailurePolicy decides what a failed hook does. Fail stops the migration, once the batch is back online
+	// for postCopy hooks. Ignore records the failure and carries on.
+	// +kubebuilder:validation:Enum=Fail;Ignore
+	// +kubebuilder:default=Fail
+	// +optional
+	FailurePolicy string `json:"failurePolicy,omitempty"`
+}
+
+// DeploymentReference points at a Deployment
+type DeploymentReference struct {
+	// Name of the Deployment
+	Name string `json:"name"`
+
+	// Namespace of the Deployment, defaults to the namespace of the VolumeResize
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// MigrationStrategy controls how many replicas are migrated at the same time
+type MigrationStrategy struct {
+	// Type selects how replicas are taken down. Rolling migrates maxUnavailable replicas at a time
+	// while the others keep serving. Offline takes the whole StatefulSet down once, migrates every
+	// replica in parallel and brings it back with the new volumeClaimTemplates. Offline also works
+	// for StatefulSets scaled to zero.
+	// +kubebuilder:validation:Enum=Rolling;Offline
+	// +kubebuilder:default=Rolling
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// MaxUnavailable is the number of replicas migrated at once, as an absolute number or a
+	// percentage of the StatefulSet replicas (rounded down, at least 1). Defaults to 1.
+	// The controller never takes down more replicas than the matching PodDisruptionBudget allows.
+	// Ignored by the Offline strategy.
+	// +kubebuilder:validation:XIntOrString
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// ReplicaOrder decides which pending replicas are migrated first. It is applied again before every batch.
+type ReplicaOrder struct {
+	// Type is Ascending (0, 1, 2...), Descending like StatefulSet rolling updates, Explicit to follow
+	// replicas, or LeaderLast to migrate the followers first and the current leader last
+	// +kubebuilder:validation:Enum=Ascending;Descending;Explicit;LeaderLast
+	// +kubebuilder:default=Ascending
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// Replicas are the ordinals in the order they are migrated, for the Explicit order.
+	// Replicas left out follow in ascending order.
+	// +listType=set
+	// +optional
+	Replicas []int32 `json:"replicas,omitempty"`
+
+	// Leader finds the leader among the pending replicas, for the LeaderLast order.
+	// Without a leader, replicas are migrated in ascending order.
+	// +optional
+	Leader *LeaderSelector `json:"leader,omitempty"`
+}
+
+// LeaderSelector identifies the leader pod. Exactly one of selector or exec is set.
+type LeaderSelector struct {
+	// Selector matches the labels of the leader pod, e.g. role=master
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// Exec runs a command in the pods, the first one where it exits 0 is the leader
+	// +optional
+	Exec *ExecHealthGate `json:"exec,omitempty"`
 }
 
 // VolumeResizeSpec defines the desired state of VolumeResize
@@ -48,6 +332,102 @@ type VolumeResizeSpec struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MinItems=1
 	Volumes []VolumeResizeTarget `json:"volumes"`
+
+	// TransferMode selects how data moves between the old and the new volume.
+	// Local runs one pod mounting both volumes. Network runs a sender pod on the old volume's node
+	// and a receiver pod on the new volume, streaming over an in-cluster TCP connection. Use it when
+	// no single node can attach both, e.g. when moving to another zone or off node-local storage.
+	// +kubebuilder:validation:Enum=Local;Network
+	// +kubebuilder:default=Local
+	// +optional
+	TransferMode string `json:"transferMode,omitempty"`
+
+	// Strategy controls how many replicas are migrated in parallel
+	// +optional
+	Strategy *MigrationStrategy `json:"strategy,omitempty"`
+
+	// Order decides which replicas are migrated first, defaults to ascending ordinals
+	// +optional
+	Order *ReplicaOrder `json:"order,omitempty"`
+
+	// Replicas limits the migration to some ordinals, each entry an ordinal like "2" or an inclusive range
+	// like "3-5", up to ordinal 9999. Defaults to every replica. The volumeClaimTemplates keep their old size
+	// as long as a replica left out still has a bigger volume.
+	// +kubebuilder:validation:items:Pattern=`^[0-9]+(-[0-9]+)?$`
+	// +listType=atomic
+	// +optional
+	Replicas []string `json:"replicas,omitempty"`
+
+	// Canary migrates one replica on its own and soaks it before the others are started.
+	// Not supported by the Offline strategy.
+	// +optional
+	Canary *CanarySpec `json:"canary,omitempty"`
+
+	// ParentPause pauses the controller owning the StatefulSet while the migration runs. Without it, a
+	// StatefulSet with a controller ownerReference is only migrated if its owner kind has a built-in hook.
+	// +optional
+	ParentPause *ParentPauseHook `json:"parentPause,omitempty"`
+
+	// GitOps suspends the Argo CD or Flux object tracking the StatefulSet while the migration runs
+	// +optional
+	GitOps *GitOpsSpec `json:"gitOps,omitempty"`
+
+	// DriftPolicy decides what happens when the StatefulSet was changed since it was backed up, checked
+	// before every deletion. Merge folds pod template changes into the backup and fails on other changes,
+	// Fail fails on any change, Ignore recreates the StatefulSet from the backup as it was taken.
+	// +kubebuilder:validation:Enum=Merge;Fail;Ignore
+	// +kubebuilder:default=Merge
+	// +optional
+	DriftPolicy string `json:"driftPolicy,omitempty"`
+
+	// Schedule delays the migration and restricts the start of every replica to maintenance windows
+	// +optional
+	Schedule *ScheduleSpec `json:"schedule,omitempty"`
+
+	// Approval holds the migration until a human approves the next replica. PerReplica waits before every
+	// replica after the first one and migrates them one at a time, ignoring maxUnavailable. AfterFirst waits
+	// once after the first batch, None never waits.
+	// A replica is approved by setting the storage.maurice.fr/approved-replica annotation to its ordinal.
+	// +kubebuilder:validation:Enum=PerReplica;AfterFirst;None
+	// +kubebuilder:default=None
+	// +optional
+	Approval string `json:"approval,omitempty"`
+
+	// MinReadySeconds is how long migrated pods must have been ready before the health gates are checked.
+	// Defaults to the minReadySeconds of the StatefulSet.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinReadySeconds *int32 `json:"minReadySeconds,omitempty"`
+
+	// HealthGates must all pass once the in-flight replicas are ready, before the next ones are taken down.
+	// They are retried until they pass, and may be changed while the migration waits for them.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	HealthGates []HealthGate `json:"healthGates,omitempty"`
+
+	// Hooks run around the migration of every replica. They may be changed during the migration.
+	// +optional
+	Hooks *MigrationHooks `json:"hooks,omitempty"`
+
+	// Paused holds the migration once the in-flight replicas are back online. Set it back to false to resume.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// Abort stops the migration: in-flight copies are dropped, the old volumes of replicas that were not
+	// migrated yet are kept and the StatefulSet is recreated. Replicas already migrated keep their new volumes.
+	// +optional
+	Abort bool `json:"abort,omitempty"`
+
+	// OverrideRetentionPolicy allows migrating a StatefulSet whose persistentVolumeClaimRetentionPolicy
+	// deletes its PVCs with it. The policy is switched to Retain while the StatefulSet is down and restored after.
+	// +optional
+	OverrideRetentionPolicy bool `json:"overrideRetentionPolicy,omitempty"`
+
+	// RetryGeneration moves a Failed migration back into Syncing whenever it is changed.
+	// The migration resumes from the replicas that were not migrated yet.
+	// +optional
+	RetryGeneration int64 `json:"retryGeneration,omitempty"`
 }
 
 // VolumeStatus tracks the migration status for a specific volume on a specific replica
@@ -74,15 +454,70 @@ type VolumeStatus struct {
 	// +optional
 	OldPVName string `json:"oldPVName,omitempty"`
 
+	// NewPVName is the name of the PV holding the copied data, recorded before the PVCs are swapped
+	// +optional
+	NewPVName string `json:"newPVName,omitempty"`
+
 	// Message provides additional details about the current phase
 	// +optional
 	Message string `json:"message,omitempty"`
 }
 
+// CanaryStatus tracks the canary replica and its soak
+type CanaryStatus struct {
+	// Replica is the ordinal of the canary
+	Replica int32 `json:"replica"`
+
+	// Phase is where the canary is: Migrating, Soaking, Passed or Failed
+	// +kubebuilder:validation:Enum=Migrating;Soaking;Passed;Failed
+	Phase string `json:"phase"`
+
+	// SoakStartTime is when the canary pod came back on its new volumes
+	// +optional
+	SoakStartTime *metav1.Time `json:"soakStartTime,omitempty"`
+
+	// ReadyTime is when the canary pod became ready during the soak
+	// +optional
+	ReadyTime *metav1.Time `json:"readyTime,omitempty"`
+
+	// Restarts is the number of container restarts of the canary pod seen during the soak
+	// +optional
+	Restarts int32 `json:"restarts,omitempty"`
+
+	// Message explains why the canary failed
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// HookStatus tracks a hook run for one replica
+type HookStatus struct {
+	// Name of the hook
+	Name string `json:"name"`
+
+	// Stage the hook belongs to: PreStop, PostCopy or PostStart
+	// +kubebuilder:validation:Enum=PreStop;PostCopy;PostStart
+	Stage string `json:"stage"`
+
+	// Replica the hook ran for
+	Replica int32 `json:"replica"`
+
+	// Phase is Running, Succeeded, Failed, or Ignored for a failure allowed by the failure policy
+	// +kubebuilder:validation:Enum=Running;Succeeded;Failed;Ignored
+	Phase string `json:"phase"`
+
+	// StartTime is when the hook started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// Message explains why the hook failed
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 // VolumeResizeStatus defines the observed state of VolumeResize.
 type VolumeResizeStatus struct {
 	// Phase is the current phase of the migration
-	// +kubebuilder:validation:Enum=Pending;Validating;Syncing;Replacing;Completed;Failed
+	// +kubebuilder:validation:Enum=Pending;Validating;Syncing;Replacing;Paused;Completed;Failed;Aborted
 	// +optional
 	Phase string `json:"phase,omitempty"`
 
@@ -96,7 +531,16 @@ type VolumeResizeStatus struct {
 	// +optional
 	VolumeStatuses []VolumeStatus `json:"volumeStatuses,omitempty"`
 
-	// CurrentReplica is the replica index currently being processed
+	// InFlightReplicas are the replica indexes currently being migrated
+	// +listType=set
+	// +optional
+	InFlightReplicas []int32 `json:"inFlightReplicas,omitempty"`
+
+	// StatefulSetReplicas is the StatefulSet replica count at validation, the base of a percentage maxUnavailable
+	// +optional
+	StatefulSetReplicas int32 `json:"statefulSetReplicas,omitempty"`
+
+	// CurrentReplica is the lowest replica index currently being processed
 	// +optional
 	CurrentReplica *int32 `json:"currentReplica,omitempty"`
 
@@ -119,6 +563,84 @@ type VolumeResizeStatus struct {
 	// BackupConfigMapName is the name of the ConfigMap containing the StatefulSet backup
 	// +optional
 	BackupConfigMapName string `json:"backupConfigMapName,omitempty"`
+
+	// ObservedRetryGeneration is the last spec.retryGeneration the controller acted on
+	// +optional
+	ObservedRetryGeneration int64 `json:"observedRetryGeneration,omitempty"`
+
+	// PausedParent records the owner paused by the parentPause hook, so it can be resumed
+	// +optional
+	PausedParent *PausedParent `json:"pausedParent,omitempty"`
+
+	// SuspendedGitOps records the Argo CD or Flux object suspended by spec.gitOps, so it can be resumed
+	// +optional
+	SuspendedGitOps *SuspendedGitOps `json:"suspendedGitOps,omitempty"`
+
+	// PendingApproval is the replica waiting for spec.approval to let it start
+	// +optional
+	PendingApproval *int32 `json:"pendingApproval,omitempty"`
+
+	// ApprovedReplicas are the replicas that were started after being approved
+	// +listType=set
+	// +optional
+	ApprovedReplicas []int32 `json:"approvedReplicas,omitempty"`
+
+	// Canary tracks the replica migrated first when spec.canary is set
+	// +optional
+	Canary *CanaryStatus `json:"canary,omitempty"`
+
+	// Hooks tracks the hooks run for the replicas of the current and past batches
+	// +optional
+	Hooks []HookStatus `json:"hooks,omitempty"`
+}
+
+// SuspendedGitOps is an Argo CD Application or Flux object suspended for the duration of the migration
+type SuspendedGitOps struct {
+	// APIVersion of the suspended object
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the suspended object
+	Kind string `json:"kind"`
+
+	// Namespace of the suspended object
+	Namespace string `json:"namespace"`
+
+	// Name of the suspended object
+	Name string `json:"name"`
+
+	// PreviousAutomated is the automated sync policy removed from an Argo CD Application, as JSON
+	// +optional
+	PreviousAutomated string `json:"previousAutomated,omitempty"`
+}
+
+// PausedParent is an object paused for the duration of the migration and how to resume it
+type PausedParent struct {
+	// Type is the parentPause type that was applied
+	Type string `json:"type"`
+
+	// APIVersion of the paused object
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the paused object
+	Kind string `json:"kind"`
+
+	// Namespace of the paused object
+	Namespace string `json:"namespace"`
+
+	// Name of the paused object
+	Name string `json:"name"`
+
+	// AnnotationKey is the annotation set by the Annotation type
+	// +optional
+	AnnotationKey string `json:"annotationKey,omitempty"`
+
+	// PreviousAnnotationValue is the value the annotation had before, unset if it was absent
+	// +optional
+	PreviousAnnotationValue *string `json:"previousAnnotationValue,omitempty"`
+
+	// PreviousReplicas is the replica count of the Deployment before the ScaleDown type scaled it to zero
+	// +optional
+	PreviousReplicas *int32 `json:"previousReplicas,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,149 @@
+#!/usr/bin/env bash
+set -euo pipefail
+
+# Migration script for volumeMode: Block volumes
+# Expected environment variables:
+#   SOURCE_DEVICE     - Path to the source block device (e.g., /dev/source)
+#   DEST_DEVICE       - Path to the destination block device (e.g., /dev/dest)
+#   BLOCK_COPY_MODE   - Filesystem (shrink a copy of an ext2/3/4 filesystem, then copy it)
+#                       or Length (copy the first BLOCK_COPY_LENGTH bytes)
+#   BLOCK_COPY_LENGTH - Number of bytes to copy when BLOCK_COPY_MODE is Length
+#   SCRATCH_DIR       - Directory holding the sparse image shrunk in Filesystem mode (e.g., /scratch)
+#
+# The source device is the old PV kept for rollback, it is only ever read.
+
+SOURCE_DEVICE="${SOURCE_DEVICE:-/dev/source}"
+DEST_DEVICE="${DEST_DEVICE:-/dev/dest}"
+BLOCK_COPY_MODE="${BLOCK_COPY_MODE:-}"
+BLOCK_COPY_LENGTH="${BLOCK_COPY_LENGTH:-}"
+SCRATCH_DIR="${SCRATCH_DIR:-/scratch}"
+
+# require_positive exits unless the value parsed from a tool's output is a positive integer
+require_positive() {
+    local name="$1" value="$2"
+    if ! [[ "${value}" =~ ^[0-9]+$ ]] || [ "${value}" -eq 0 ]; then
+        echo "ERROR: Could not determine the ${name}, got: '${value}'"
+        exit 1
+    fi
+}
+
+echo "Starting block volume migration"
+echo "  Source: ${SOURCE_DEVICE}"
+echo "  Destination: ${DEST_DEVICE}"
+echo "  Mode: ${BLOCK_COPY_MODE}"
+echo ""
+
+# Verify both devices exist
+if [ ! -b "${SOURCE_DEVICE}" ]; then
+    echo "ERROR: Source device does not exist: ${SOURCE_DEVICE}"
+    exit 1
+fi
+
+if [ ! -b "${DEST_DEVICE}" ]; then
+    echo "ERROR: Destination device does not exist: ${DEST_DEVICE}"
+    exit 1
+fi
+
+SOURCE_SIZE=$(blockdev --getsize64 "${SOURCE_DEVICE}")
+DEST_SIZE=$(blockdev --getsize64 "${DEST_DEVICE}")
+echo "Source size: ${SOURCE_SIZE} bytes"
+echo "Destination size: ${DEST_SIZE} bytes"
+
+case "${BLOCK_COPY_MODE}" in
+    Filesystem)
+        FS_TYPE=$(blkid -o value -s TYPE "${SOURCE_DEVICE}" || true)
+        case "${FS_TYPE}" in
+            ext2|ext3|ext4) ;;
+            *)
+                echo "ERROR: Filesystem mode needs an ext2/3/4 filesystem on the source device, found: '${FS_TYPE}'"
+                exit 1
+                ;;
+        esac
+
+        # Read-only check, errors must be repaired on the workload's side before migrating
+        FSCK_EXIT=0
+        e2fsck -f -n "${SOURCE_DEVICE}" || FSCK_EXIT=$?
+        if [ ${FSCK_EXIT} -ne 0 ]; then
+            echo "ERROR: e2fsck found errors on the source filesystem (exit code ${FSCK_EXIT}), repair it before migrating"
+            exit 1
+        fi
+
+        BLOCK_SIZE=$(dumpe2fs -h "${SOURCE_DEVICE}" 2>/dev/null | awk -F: '/^Block size/ {gsub(/ /, "", $2); print $2}')
+        require_positive "filesystem block size" "${BLOCK_SIZE}"
+        MIN_BLOCKS=$(resize2fs -P "${SOURCE_DEVICE}" 2>/dev/null | awk -F: '/minimum size/ {gsub(/ /, "", $2); print $2}')
+        require_positive "minimum filesystem size" "${MIN_BLOCKS}"
+        MIN_BYTES=$((MIN_BLOCKS * BLOCK_SIZE))
+        echo "Filesystem needs at least ${MIN_BYTES} bytes"
+
+        if [ ${MIN_BYTES} -gt ${DEST_SIZE} ]; then
+            echo "ERROR: Filesystem data (${MIN_BYTES} bytes) does not fit on destination (${DEST_SIZE} bytes)"
+            exit 1
+        fi
+
+        # Copy the used blocks into a sparse image and shrink that copy, so the source stays untouched
+        IMAGE="${SCRATCH_DIR}/source.img"
+        rm -f "${IMAGE}"
+        echo "Imaging source filesystem to ${IMAGE}"
+        e2image -ra -p "${SOURCE_DEVICE}" "${IMAGE}"
+
+        # e2fsck exits 1 when it fixed errors, anything above is fatal
+        FSCK_EXIT=0
+        e2fsck -f -y "${IMAGE}" || FSCK_EXIT=$?
+        if [ ${FSCK_EXIT} -gt 1 ]; then
+            echo "ERROR: e2fsck of the image failed with exit code: ${FSCK_EXIT}"
+            exit 1
+        fi
+
+        TARGET_BLOCKS=$((DEST_SIZE / BLOCK_SIZE))
+        echo "Shrinking the image to ${TARGET_BLOCKS} blocks of ${BLOCK_SIZE} bytes"
+        resize2fs "${IMAGE}" "${TARGET_BLOCKS}"
+        COPY_SOURCE="${IMAGE}"
+        COPY_BYTES=$((TARGET_BLOCKS * BLOCK_SIZE))
+        ;;
+    Length)
+        if [ -z "${BLOCK_COPY_LENGTH}" ]; then
+            echo "ERROR: BLOCK_COPY_LENGTH is required in Length mode"
+            exit 1
+        fi
+        if [ "${BLOCK_COPY_LENGTH}" -gt "${SOURCE_SIZE}" ]; then
+            echo "ERROR: Requested length (${BLOCK_COPY_LENGTH} bytes) exceeds source size (${SOURCE_SIZE} bytes)"
+            exit 1
+        fi
+        if [ "${BLOCK_COPY_LENGTH}" -gt "${DEST_SIZE}" ]; then
+            echo "ERROR: Requested length (${BLOCK_COPY_LENGTH} bytes) does not fit on destination (${DEST_SIZE} bytes)"
+            exit 1
+        fi
+        COPY_SOURCE="${SOURCE_DEVICE}"
+        COPY_BYTES=${BLOCK_COPY_LENGTH}
+        ;;
+    *)
+        echo "ERROR: Unsupported BLOCK_COPY_MODE: '${BLOCK_COPY_MODE}'"
+        exit 1
+        ;;
+esac
+
+echo ""
+echo "Copying ${COPY_BYTES} bytes..."
+echo ""
+
+# Every block is written, zeroes included: a thick-provisioned LUN or a reused disk does not read back
+# as zeroes, and skipping them would fail the comparison below
+dd if="${COPY_SOURCE}" of="${DEST_DEVICE}" \
+    bs=4M \
+    iflag=count_bytes \
+    count="${COPY_BYTES}" \
+    conv=fsync \
+    status=progress
+
+echo ""
+echo "Verifying copied data..."
+if ! cmp -n "${COPY_BYTES}" "${COPY_SOURCE}" "${DEST_DEVICE}"; then
+    echo "Migration failed: destination does not match source"
+    exit 1
+fi
+
+if [ "${COPY_SOURCE}" != "${SOURCE_DEVICE}" ]; then
+    rm -f "${COPY_SOURCE}"
+fi
+
+echo "Migration completed successfully"
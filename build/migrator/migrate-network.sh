@@ -0,0 +1,113 @@
+#!/usr/bin/env bash
+set -euo pipefail
+
+# Migration script for network transfers between two pods
+# Expected environment variables:
+#   MIGRATION_MODE - send (stream SOURCE_PATH) or receive (extract into DEST_PATH)
+#   SOURCE_PATH    - Path to source volume on the sender (e.g., /source)
+#   DEST_PATH      - Path to destination volume on the receiver (e.g., /dest)
+#   TRANSFER_HOST  - Service the sender connects to
+#   TRANSFER_PORT  - TCP port the receiver listens on
+#   TRANSFER_TOKEN - Per-migration token the sender presents before the data
+
+SOURCE_PATH="${SOURCE_PATH:-/source}"
+DEST_PATH="${DEST_PATH:-/dest}"
+TRANSFER_PORT="${TRANSFER_PORT:-9000}"
+MANIFEST=".volmig-manifest.sha256"
+
+if [ -z "${TRANSFER_TOKEN:-}" ]; then
+    echo "ERROR: TRANSFER_TOKEN is not set"
+    exit 1
+fi
+
+# receive_stream reads the token line, then extracts the tar stream. Exits 2 on a bad token.
+receive_stream() {
+    local token
+    IFS= read -r token || exit 2
+    if [ "${token}" != "${TRANSFER_TOKEN}" ]; then
+        echo "Rejected connection with an invalid token"
+        cat > /dev/null
+        exit 2
+    fi
+    tar -x -p --numeric-owner -C "${DEST_PATH}"
+}
+
+send() {
+    echo "Starting network transfer (sender)"
+    echo "  Source: ${SOURCE_PATH}"
+    echo "  Receiver: ${TRANSFER_HOST}:${TRANSFER_PORT}"
+    echo ""
+
+    if [ ! -d "${SOURCE_PATH}" ]; then
+        echo "ERROR: Source path does not exist: ${SOURCE_PATH}"
+        exit 1
+    fi
+
+    # The source is mounted read-only, so the manifest is built aside and appended to the stream
+    echo "Computing checksums..."
+    (cd "${SOURCE_PATH}" && find . -type f ! -name "${MANIFEST}" -print0 | xargs -0 -r sha256sum) > "/tmp/${MANIFEST}"
+    echo "Checksummed $(wc -l < "/tmp/${MANIFEST}") files"
+
+    # The controller starts the sender once the receiver is Ready, the retries only cover the Service
+    # picking up its endpoint
+    echo "Streaming data..."
+    {
+        printf '%s\n' "${TRANSFER_TOKEN}"
+        tar -c -p --numeric-owner -C "${SOURCE_PATH}" . -C /tmp "${MANIFEST}"
+    } | socat -u STDIN "TCP:${TRANSFER_HOST}:${TRANSFER_PORT},retry=60,interval=5"
+
+    echo ""
+    echo "Data sent successfully"
+}
+
+receive() {
+    echo "Starting network transfer (receiver)"
+    echo "  Destination: ${DEST_PATH}"
+    echo "  Port: ${TRANSFER_PORT}"
+    echo ""
+
+    if [ ! -d "${DEST_PATH}" ]; then
+        echo "ERROR: Destination path does not exist: ${DEST_PATH}"
+        exit 1
+    fi
+
+    # Keep listening until a connection presents the right token
+    while true; do
+        set +e
+        socat -u "TCP-LISTEN:${TRANSFER_PORT},reuseaddr" STDOUT | receive_stream
+        STATUS=$?
+        set -e
+        if [ ${STATUS} -eq 2 ]; then
+            continue
+        fi
+        if [ ${STATUS} -ne 0 ]; then
+            echo "ERROR: Receiving data failed with exit code: ${STATUS}"
+            exit ${STATUS}
+        fi
+        break
+    done
+
+    echo "Verifying checksums..."
+    if [ ! -f "${DEST_PATH}/${MANIFEST}" ]; then
+        echo "ERROR: Checksum manifest missing from the stream"
+        exit 1
+    fi
+    (cd "${DEST_PATH}" && sha256sum -c --quiet "${MANIFEST}")
+    rm -f "${DEST_PATH}/${MANIFEST}"
+
+    echo ""
+    echo "Migration completed successfully"
+}
+
+case "${MIGRATION_MODE:-}" in
+    send)
+        send
+        ;;
+    receive)
+        receive
+        ;;
+    *)
+        echo "ERROR: Unknown network migration mode: ${MIGRATION_MODE:-}"
+        exit 1
+        ;;
+esac
@@ -3,12 +3,24 @@ set -euo pipefail
 
 # Migration script for volume data sync using rclone
 # Expected environment variables:
-#   SOURCE_PATH - Path to source volume (e.g., /source)
-#   DEST_PATH   - Path to destination volume (e.g., /dest)
+#   MIGRATION_MODE - filesystem (default), block, send or receive
+#   SOURCE_PATH    - Path to source volume (e.g., /source)
+#   DEST_PATH      - Path to destination volume (e.g., /dest)
 
+MIGRATION_MODE="${MIGRATION_MODE:-filesystem}"
 SOURCE_PATH="${SOURCE_PATH:-/source}"
 DEST_PATH="${DEST_PATH:-/dest}"
 
+# Raw block volumes are handled by a dedicated mover
+if [ "${MIGRATION_MODE}" = "block" ]; then
+    exec /usr/local/bin/migrate-block.sh
+fi
+
+# Network transfers run as a sender and a receiver pod
+if [ "${MIGRATION_MODE}" = "send" ] || [ "${MIGRATION_MODE}" = "receive" ]; then
+    exec /usr/local/bin/migrate-network.sh
+fi
+
 echo "Starting volume migration"
 echo "  Source: ${SOURCE_PATH}"
 echo "  Destination: ${DEST_PATH}"
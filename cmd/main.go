@@ -19,7 +19,11 @@ package main
 import (
 	"crypto/tls"
 	"flag"
+	"fmt"
 	"os"
+	"time"
+	// Embed the time zone database for spec.schedule.timeZone, the base image may not ship one
+	_ "time/tzdata"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -37,6 +41,8 @@ import (
 
 	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
 	"github.com/thomas-maurice/migcontroller/internal/controller"
+	webhookv1 "github.com/thomas-maurice/migcontroller/internal/webhook/v1"
+	webhookv1alpha1 "github.com/thomas-maurice/migcontroller/internal/webhook/v1alpha1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -61,6 +67,9 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var orphanScanInterval time.Duration
+	var recreateOrphanedSTS bool
+	var enableSTSLock bool
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -79,6 +88,13 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.DurationVar(&orphanScanInterval, "orphan-scan-interval", 10*time.Minute,
+		"How often to look for artifacts of deleted migrations after the startup scan. Use 0 to only scan at startup.")
+	flag.BoolVar(&recreateOrphanedSTS, "recreate-orphaned-statefulsets", false,
+		"If set, a StatefulSet that is missing but still has an orphaned backup ConfigMap is recreated from it.")
+	flag.BoolVar(&enableSTSLock, "enable-statefulset-lock", false,
+		"If set, serve the webhook denying changes to StatefulSets being migrated. "+
+			"Requires the POD_NAMESPACE and SERVICE_ACCOUNT_NAME environment variables so the controller exempts itself.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -178,13 +194,49 @@ func main() {
 		os.Exit(1)
 	}
 
+	executor, err := controller.NewPodExecutor(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create pod executor")
+		os.Exit(1)
+	}
 	if err := (&controller.VolumeResizeReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorder("volumeresize-controller"),
+		Executor: executor,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "VolumeResize")
 		os.Exit(1)
 	}
+	if err := (&controller.OrphanScanner{
+		Client:               mgr.GetClient(),
+		APIReader:            mgr.GetAPIReader(),
+		Recorder:             mgr.GetEventRecorder("volumeresize-orphan-scanner"),
+		Interval:             orphanScanInterval,
+		RecreateStatefulSets: recreateOrphanedSTS,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to set up orphan scanner")
+		os.Exit(1)
+	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err := webhookv1alpha1.SetupVolumeResizeWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "VolumeResize")
+			os.Exit(1)
+		}
+		if enableSTSLock {
+			podNamespace, serviceAccount := os.Getenv("POD_NAMESPACE"), os.Getenv("SERVICE_ACCOUNT_NAME")
+			if podNamespace == "" || serviceAccount == "" {
+				setupLog.Error(nil, "POD_NAMESPACE and SERVICE_ACCOUNT_NAME must be set to enable the StatefulSet lock")
+				os.Exit(1)
+			}
+			exempt := []string{fmt.Sprintf("system:serviceaccount:%s:%s", podNamespace, serviceAccount)}
+			if err := webhookv1.SetupStatefulSetLockWebhookWithManager(mgr, exempt); err != nil {
+				setupLog.Error(err, "unable to create webhook", "webhook", "StatefulSetLock")
+				os.Exit(1)
+			}
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
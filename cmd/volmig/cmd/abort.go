@@ -0,0 +1,56 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+var abortCmd = &cobra.Command{
+	Use:   "abort <name>",
+	Short: "Abort a VolumeResize and restore the StatefulSet",
+	Long: `Abort a VolumeResize. In-flight copies are stopped and their temp PVCs deleted,
+the StatefulSet is recreated and replicas that were not migrated yet keep their
+original volumes. Replicas already migrated keep their new volumes.
+
+Examples:
+  # Abort a migration
+  volmig abort resize-weaviate`,
+	Args: cobra.ExactArgs(1),
+	Run:  runAbort,
+}
+
+func init() {
+	rootCmd.AddCommand(abortCmd)
+}
+
+func runAbort(cmd *cobra.Command, args []string) {
+	name := args[0]
+
+	patchVolumeResize(context.Background(), name, func(vr *storagev1alpha1.VolumeResize) {
+		vr.Spec.Abort = true
+	})
+
+	fmt.Printf("VolumeResize '%s' is being aborted\n", name)
+	fmt.Printf("Follow with:\n")
+	fmt.Printf("  volmig watch %s -n %s\n", name, namespace)
+}
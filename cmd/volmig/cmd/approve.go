@@ -0,0 +1,71 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+var approveReplica int32
+
+var approveCmd = &cobra.Command{
+	Use:   "approve <name>",
+	Short: "Let the next replica of a VolumeResize waiting for approval start",
+	Long: `Approve the next replica of a VolumeResize created with spec.approval. Without
+--replica, the replica it is waiting for is approved. A replica can be approved
+ahead of time, it starts once the previous batch is done.
+
+Examples:
+  # Approve the replica the migration waits for
+  volmig approve resize-postgres
+
+  # Approve replica 2 explicitly
+  volmig approve resize-postgres --replica 2`,
+	Args: cobra.ExactArgs(1),
+	Run:  runApprove,
+}
+
+func init() {
+	rootCmd.AddCommand(approveCmd)
+	approveCmd.Flags().Int32Var(&approveReplica, "replica", -1, "ordinal of the replica to approve (optional, defaults to the one waiting)")
+}
+
+func runApprove(cmd *cobra.Command, args []string) {
+	name := args[0]
+	replica := approveReplica
+
+	patchVolumeResize(context.Background(), name, func(vr *storagev1alpha1.VolumeResize) {
+		if replica < 0 {
+			if vr.Status.PendingApproval == nil {
+				exitWithError("nothing to approve", fmt.Errorf("volumeresize %s is not waiting for approval, use --replica", name))
+			}
+			replica = *vr.Status.PendingApproval
+		}
+		if vr.Annotations == nil {
+			vr.Annotations = map[string]string{}
+		}
+		vr.Annotations[annotationApprovedReplica] = strconv.Itoa(int(replica))
+	})
+
+	fmt.Printf("Replica %d of VolumeResize '%s' approved\n", replica, name)
+}
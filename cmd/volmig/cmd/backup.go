@@ -0,0 +1,328 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+	"github.com/thomas-maurice/migcontroller/internal/stsbackup"
+)
+
+var (
+	backupRevision int
+	backupOutput   string
+	backupFile     string
+	backupForce    bool
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Export, import and inspect StatefulSet backups",
+	Long: `Work with the StatefulSet backup the operator takes before deleting a
+StatefulSet. Backups are read in both the revisioned and the single-key format.
+
+Examples:
+  # Print the backup of a migration
+  volmig backup show resize-weaviate
+
+  # Save it as YAML that can be applied with kubectl
+  volmig backup export resize-weaviate -o weaviate-sts.yaml
+
+  # Put a saved StatefulSet back as the backup of a migration
+  volmig backup import resize-weaviate -f weaviate-sts.yaml`,
+}
+
+var backupExportCmd = &cobra.Command{
+	Use:   "export <volumeresize>",
+	Short: "Write a StatefulSet backup as apply-ready YAML",
+	Long: `Write the StatefulSet stored in the backup of a migration as YAML, without
+status and server-set fields. Owner references are dropped as their UIDs are
+only valid in the cluster they come from.`,
+	Args: cobra.ExactArgs(1),
+	Run:  runBackupExport,
+}
+
+var backupImportCmd = &cobra.Command{
+	Use:   "import <volumeresize>",
+	Short: "Restore a StatefulSet into the backup of a migration",
+	Long: `Read a StatefulSet from a YAML or JSON file and store it as a new revision of
+the backup of the migration, in the namespace given by --namespace. The backup
+is created if it does not exist, then the StatefulSet can be recreated with
+volmig recover recreate-sts.`,
+	Args: cobra.ExactArgs(1),
+	Run:  runBackupImport,
+}
+
+var backupShowCmd = &cobra.Command{
+	Use:   "show <volumeresize>",
+	Short: "Print a StatefulSet backup and its revisions",
+	Args:  cobra.ExactArgs(1),
+	Run:   runBackupShow,
+}
+
+func init() {
+	rootCmd.AddCommand(backupCmd)
+	backupCmd.AddCommand(backupExportCmd)
+	backupCmd.AddCommand(backupImportCmd)
+	backupCmd.AddCommand(backupShowCmd)
+
+	for _, c := range []*cobra.Command{backupExportCmd, backupShowCmd} {
+		c.Flags().IntVar(&backupRevision, "revision", 0, "revision to read, the latest if unset")
+	}
+	backupExportCmd.Flags().StringVarP(&backupOutput, "output", "o", "", "file to write, stdout if unset")
+	backupImportCmd.Flags().StringVarP(&backupFile, "file", "f", "", "file to read the StatefulSet from, - for stdin (required)")
+	_ = backupImportCmd.MarkFlagRequired("file")
+	backupImportCmd.Flags().BoolVar(&backupForce, "force", false,
+		"import even though the migration is in progress, the controller restores the imported StatefulSet")
+}
+
+// backupConfigMapName returns the name of the backup of a migration, even if its VolumeResize is gone
+func backupConfigMapName(vr *storagev1alpha1.VolumeResize, migration string) string {
+	if vr != nil && vr.Status.BackupConfigMapName != "" {
+		return vr.Status.BackupConfigMapName
+	}
+	return migration + backupConfigMapSuffix
+}
+
+// getVolumeResizeIfExists returns the VolumeResize, or nil if it does not exist
+func getVolumeResizeIfExists(ctx context.Context, c client.Client, name string) (*storagev1alpha1.VolumeResize, error) {
+	vr := &storagev1alpha1.VolumeResize{}
+	err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, vr)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return vr, nil
+}
+
+// getBackup returns the backup ConfigMap of a migration and the requested revision of its StatefulSet
+func getBackup(ctx context.Context, c client.Client, migration string) (*corev1.ConfigMap, *appsv1.StatefulSet) {
+	vr, err := getVolumeResizeIfExists(ctx, c, migration)
+	if err != nil {
+		exitWithError("failed to get volumeresize", err)
+	}
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: backupConfigMapName(vr, migration)}, cm); err != nil {
+		exitWithError("failed to get backup configmap", err)
+	}
+	sts, err := stsbackup.ReadRevision(ctx, c, cm, backupRevision)
+	if err != nil {
+		exitWithError("failed to read backup", err)
+	}
+	return cm, sts
+}
+
+// normalizeSTS returns the StatefulSet as a map without the empty status blocks the API types always
+// serialize and without server-set fields
+func normalizeSTS(sts *appsv1.StatefulSet) (map[string]any, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(stsbackup.Clean(sts))
+	if err != nil {
+		return nil, err
+	}
+	unstructured.RemoveNestedField(obj, "status")
+	unstructured.RemoveNestedField(obj, "metadata", "creationTimestamp")
+	if vcts, found, _ := unstructured.NestedSlice(obj, "spec", "volumeClaimTemplates"); found {
+		for _, vct := range vcts {
+			if m, ok := vct.(map[string]any); ok {
+				delete(m, "status")
+				unstructured.RemoveNestedField(m, "metadata", "creationTimestamp")
+			}
+		}
+		if err := unstructured.SetNestedSlice(obj, vcts, "spec", "volumeClaimTemplates"); err != nil {
+			return nil, err
+		}
+	}
+	return obj, nil
+}
+
+// exportYAML renders a StatefulSet that can be applied in any cluster
+func exportYAML(sts *appsv1.StatefulSet) ([]byte, error) {
+	obj, err := normalizeSTS(sts)
+	if err != nil {
+		return nil, err
+	}
+	unstructured.RemoveNestedField(obj, "metadata", "ownerReferences")
+	return yaml.Marshal(obj)
+}
+
+func runBackupExport(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	c, err := getClient()
+	if err != nil {
+		exitWithError("failed to create kubernetes client", err)
+	}
+
+	_, sts := getBackup(ctx, c, args[0])
+	if len(sts.OwnerReferences) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: dropped the owner references of StatefulSet %s, re-adopt it with its controller\n", sts.Name)
+	}
+	data, err := exportYAML(sts)
+	if err != nil {
+		exitWithError("failed to render backup", err)
+	}
+
+	if backupOutput == "" {
+		_, _ = os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(backupOutput, data, 0o600); err != nil {
+		exitWithError("failed to write backup", err)
+	}
+	fmt.Printf("StatefulSet '%s' written to %s\n", sts.Name, backupOutput)
+}
+
+func runBackupImport(cmd *cobra.Command, args []string) {
+	migration := args[0]
+	ctx := context.Background()
+
+	var data []byte
+	var err error
+	if backupFile == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(backupFile)
+	}
+	if err != nil {
+		exitWithError("failed to read file", err)
+	}
+	sts := &appsv1.StatefulSet{}
+	if err := yaml.UnmarshalStrict(data, sts); err != nil {
+		exitWithError("failed to parse statefulset", err)
+	}
+	if sts.Kind != "StatefulSet" || sts.Name == "" {
+		exitWithError("failed to parse statefulset", fmt.Errorf("%s does not contain a named StatefulSet", backupFile))
+	}
+	sts.Namespace = namespace
+
+	c, err := getClient()
+	if err != nil {
+		exitWithError("failed to create kubernetes client", err)
+	}
+
+	vr, err := getVolumeResizeIfExists(ctx, c, migration)
+	if err != nil {
+		exitWithError("failed to get volumeresize", err)
+	}
+	if vr != nil {
+		if vr.Spec.StatefulSetName != sts.Name {
+			exitWithError("refusing to import", fmt.Errorf("volumeresize %s migrates statefulset %s, not %s",
+				migration, vr.Spec.StatefulSetName, sts.Name))
+		}
+		switch vr.Status.Phase {
+		case "", phasePending, phaseCompleted, phaseAborted:
+		default:
+			if !backupForce {
+				exitWithError("refusing to import", fmt.Errorf("volumeresize %s is %s, use --force to replace the StatefulSet it restores",
+					migration, vr.Status.Phase))
+			}
+		}
+	}
+
+	cmName := backupConfigMapName(vr, migration)
+	revision, err := stsbackup.Write(ctx, c, metav1.ObjectMeta{
+		Name:        cmName,
+		Namespace:   namespace,
+		Labels:      map[string]string{labelMigrationName: migration},
+		Annotations: map[string]string{annotationManagedBy: managedByOperator},
+	}, sts)
+	if err != nil {
+		exitWithError("failed to import backup", err)
+	}
+
+	fmt.Printf("StatefulSet '%s' imported into '%s' as revision %d\n", sts.Name, cmName, revision)
+	fmt.Printf("Recreate it with: volmig recover recreate-sts %s -n %s\n", cmName, namespace)
+}
+
+func runBackupShow(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	c, err := getClient()
+	if err != nil {
+		exitWithError("failed to create kubernetes client", err)
+	}
+
+	cm, sts := getBackup(ctx, c, args[0])
+	revisions, err := stsbackup.Revisions(cm)
+	if err != nil {
+		exitWithError("failed to read backup revisions", err)
+	}
+
+	fmt.Printf("ConfigMap:    %s\n", cm.Name)
+	fmt.Printf("StatefulSet:  %s\n", sts.Name)
+	if sts.Spec.Replicas != nil {
+		fmt.Printf("Replicas:     %d\n", *sts.Spec.Replicas)
+	}
+	if sts.Spec.ServiceName != "" {
+		fmt.Printf("Service:      %s\n", sts.Spec.ServiceName)
+	}
+	if len(sts.OwnerReferences) > 0 {
+		owner := sts.OwnerReferences[0]
+		fmt.Printf("Owner:        %s %s\n", owner.Kind, owner.Name)
+	}
+	fmt.Println()
+
+	fmt.Println("Volume Claim Templates:")
+	for _, vct := range sts.Spec.VolumeClaimTemplates {
+		fmt.Printf("  - Name:     %s\n", vct.Name)
+		fmt.Printf("    Size:     %s\n", vct.Spec.Resources.Requests.Storage().String())
+		if vct.Spec.StorageClassName != nil {
+			fmt.Printf("    StorageClass: %s\n", *vct.Spec.StorageClassName)
+		}
+	}
+	fmt.Println()
+
+	fmt.Println("Containers:")
+	for _, container := range sts.Spec.Template.Spec.Containers {
+		fmt.Printf("  - %s: %s\n", container.Name, container.Image)
+	}
+	fmt.Println()
+
+	fmt.Println("Revisions:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "  REVISION\tCREATED\tSIZE\tCHUNKS\tSHA256")
+	for _, rev := range revisions {
+		sum := rev.SHA256
+		if sum == "" {
+			// Backups taken before revisions have no checksum
+			sum = "-"
+		} else if len(sum) > 12 {
+			sum = sum[:12]
+		}
+		_, _ = fmt.Fprintf(w, "  %d\t%s\t%d\t%d\t%s\n",
+			rev.Revision, rev.CreatedAt.Format("2006-01-02 15:04:05"), rev.Size, rev.Chunks, sum)
+	}
+	_ = w.Flush()
+}
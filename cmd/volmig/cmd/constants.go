@@ -21,9 +21,41 @@ const (
 	phasePending   = "Pending"
 	phaseCompleted = "Completed"
 	phaseFailed    = "Failed"
+	phaseAborted   = "Aborted"
 )
 
 // Output format constants
 const (
 	outputFormatWide = "wide"
 )
+
+// Migration strategy types
+const (
+	strategyTypeOffline = "Offline"
+)
+
+// Replica orders
+const (
+	orderLeaderLast = "LeaderLast"
+)
+
+// Canary failure actions and phases
+const (
+	canaryOnFailureRollback = "Rollback"
+	canaryPhasePassed       = "Passed"
+)
+
+// Annotation keys understood by the operator
+const (
+	annotationForceDelete = "storage.maurice.fr/force-delete"
+	annotationManagedBy   = "storage.maurice.fr/managed-by"
+	// annotationApprovedReplica lets the replica with this ordinal start when spec.approval waits for it
+	annotationApprovedReplica = "storage.maurice.fr/approved-replica"
+	managedByOperator         = "volume-resize-operator"
+)
+
+// Label and key names of the artifacts the operator leaves around during a migration
+const (
+	labelMigrationName    = "storage.maurice.fr/migration-name"
+	backupConfigMapSuffix = "-sts-backup"
+)
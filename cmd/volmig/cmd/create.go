@@ -19,11 +19,14 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
 )
@@ -33,6 +36,26 @@ var (
 	volumeName      string
 	newSize         string
 	storageClass    string
+	maxUnavailable  string
+	offline         bool
+	overridePolicy  bool
+	suspendGitOps   bool
+	driftPolicy     string
+	notBefore       string
+	windows         []string
+	windowDuration  time.Duration
+	timeZone        string
+	approval        string
+	canary          bool
+	canaryReplica   int32
+	canarySoak      time.Duration
+	canaryRestarts  int32
+	canaryRollback  bool
+	minReadySeconds int32
+	order           string
+	orderReplicas   []int32
+	leaderSelector  string
+	replicaSubset   []string
 	watch           bool
 )
 
@@ -48,6 +71,22 @@ Examples:
   # Resize with a different storage class
   volmig create resize-db --statefulset postgres --volume data --size 10Gi --storage-class fast-ssd
 
+  # Migrate a quarter of the replicas at a time
+  volmig create resize-cache --statefulset redis --volume data --size 5Gi --max-unavailable 25%
+
+  # Take the StatefulSet down once and migrate every replica in parallel
+  volmig create resize-batch --statefulset ingest --volume data --size 20Gi --offline
+
+  # Only take replicas down between 01:00 and 05:00 Paris time, starting next Monday
+  volmig create resize-db --statefulset postgres --volume data --size 10Gi \
+    --window "0 1 * * *" --window-duration 4h --timezone Europe/Paris --not-before 2026-10-26T00:00:00+01:00
+
+  # Migrate replica 0 first and watch it for an hour, putting it back on its old volume if it restarts
+  volmig create resize-db --statefulset postgres --volume data --size 10Gi --canary --canary-soak 1h --canary-rollback
+
+  # Only migrate replicas 0 and 2 to 3, the template keeps the old size until the others are resized
+  volmig create resize-es --statefulset elasticsearch --volume data --size 50Gi --replicas 0,2-3
+
   # Create and watch progress
   volmig create resize-weaviate --statefulset weaviate --volume weaviate-data --size 500Mi --watch`,
 	Args: cobra.ExactArgs(1),
@@ -62,6 +101,38 @@ func init() {
 	createCmd.Flags().StringVar(&newSize, "size", "", "target size for the volume (required, e.g., 500Mi, 10Gi)")
 	createCmd.Flags().StringVar(&storageClass, "storage-class", "",
 		"storage class for the new PVC (optional, defaults to original)")
+	createCmd.Flags().StringVar(&maxUnavailable, "max-unavailable", "",
+		"number or percentage of replicas migrated at once (optional, defaults to 1)")
+	createCmd.Flags().BoolVar(&offline, "offline", false,
+		"take the StatefulSet down once and migrate every replica in parallel")
+	createCmd.Flags().BoolVar(&overridePolicy, "override-retention-policy", false,
+		"switch a whenDeleted=Delete PVC retention policy to Retain while the StatefulSet is down")
+	createCmd.Flags().BoolVar(&suspendGitOps, "suspend-gitops", false,
+		"pause Argo CD auto-sync or the Flux reconciliation deploying the StatefulSet while it is down")
+	createCmd.Flags().StringVar(&driftPolicy, "drift-policy", "",
+		"what to do with StatefulSet changes made during the migration: Merge, Fail or Ignore (optional, defaults to Merge)")
+	createCmd.Flags().StringVar(&notBefore, "not-before", "",
+		"RFC 3339 time before which no replica is taken down (optional)")
+	createCmd.Flags().StringArrayVar(&windows, "window", nil,
+		"cron expression of when a maintenance window opens, replicas only start inside windows (repeatable)")
+	createCmd.Flags().DurationVar(&windowDuration, "window-duration", 4*time.Hour, "how long each maintenance window stays open")
+	createCmd.Flags().StringVar(&timeZone, "timezone", "", "IANA time zone of the maintenance windows (optional, defaults to UTC)")
+	createCmd.Flags().StringVar(&approval, "approval", "",
+		"wait for volmig approve between replicas: PerReplica, AfterFirst or None (optional, defaults to None)")
+	createCmd.Flags().BoolVar(&canary, "canary", false, "migrate one replica first and soak it before the others")
+	createCmd.Flags().Int32Var(&canaryReplica, "canary-replica", -1, "ordinal of the canary replica (optional, defaults to the lowest)")
+	createCmd.Flags().DurationVar(&canarySoak, "canary-soak", 30*time.Minute, "how long the canary must stay ready")
+	createCmd.Flags().Int32Var(&canaryRestarts, "canary-max-restarts", 0, "container restarts of the canary tolerated during the soak")
+	createCmd.Flags().BoolVar(&canaryRollback, "canary-rollback", false,
+		"move the canary back to its old volume when it fails, instead of only aborting")
+	createCmd.Flags().Int32Var(&minReadySeconds, "min-ready-seconds", -1,
+		"seconds a migrated pod must stay ready before the next replica (optional, defaults to the StatefulSet's)")
+	createCmd.Flags().StringVar(&order, "order", "",
+		"order replicas are migrated in: Ascending, Descending, Explicit or LeaderLast (optional, defaults to Ascending)")
+	createCmd.Flags().Int32SliceVar(&orderReplicas, "order-replicas", nil, "ordinals in the order they are migrated, for --order Explicit")
+	createCmd.Flags().StringVar(&leaderSelector, "leader-selector", "", "label selector of the leader pod, for --order LeaderLast")
+	createCmd.Flags().StringSliceVar(&replicaSubset, "replicas", nil,
+		"ordinals or ranges of ordinals to migrate, e.g. 0,2-3 (optional, defaults to every replica)")
 	createCmd.Flags().BoolVarP(&watch, "watch", "w", false, "watch migration progress after creation")
 
 	_ = createCmd.MarkFlagRequired("statefulset")
@@ -114,6 +185,78 @@ func runCreate(cmd *cobra.Command, args []string) {
 		vr.Spec.Volumes[0].StorageClass = &storageClass
 	}
 
+	vr.Spec.OverrideRetentionPolicy = overridePolicy
+	if suspendGitOps {
+		vr.Spec.GitOps = &storagev1alpha1.GitOpsSpec{Suspend: true}
+	}
+	vr.Spec.DriftPolicy = driftPolicy
+	vr.Spec.Approval = approval
+	if minReadySeconds >= 0 {
+		vr.Spec.MinReadySeconds = &minReadySeconds
+	}
+
+	// Add canary if specified
+	if canary {
+		vr.Spec.Canary = &storagev1alpha1.CanarySpec{
+			SoakDuration: metav1.Duration{Duration: canarySoak},
+			MaxRestarts:  canaryRestarts,
+		}
+		if canaryReplica >= 0 {
+			vr.Spec.Canary.Replica = &canaryReplica
+		}
+		if canaryRollback {
+			vr.Spec.Canary.OnFailure = canaryOnFailureRollback
+		}
+	}
+
+	// Add order if specified
+	if order != "" {
+		vr.Spec.Order = &storagev1alpha1.ReplicaOrder{Type: order, Replicas: orderReplicas}
+	}
+	if leaderSelector != "" {
+		if vr.Spec.Order == nil {
+			exitWithError("invalid --leader-selector", fmt.Errorf("it needs --order %s", orderLeaderLast))
+		}
+		selector, err := metav1.ParseToLabelSelector(leaderSelector)
+		if err != nil {
+			exitWithError("invalid --leader-selector", err)
+		}
+		vr.Spec.Order.Leader = &storagev1alpha1.LeaderSelector{Selector: selector}
+	}
+
+	// Only migrate a subset of the replicas if specified
+	vr.Spec.Replicas = replicaSubset
+
+	// Add schedule if specified
+	if notBefore != "" || len(windows) > 0 || timeZone != "" {
+		vr.Spec.Schedule = &storagev1alpha1.ScheduleSpec{TimeZone: timeZone}
+	}
+	if notBefore != "" {
+		t, err := time.Parse(time.RFC3339, notBefore)
+		if err != nil {
+			exitWithError("invalid --not-before", err)
+		}
+		vr.Spec.Schedule.NotBefore = &metav1.Time{Time: t}
+	}
+	for _, start := range windows {
+		vr.Spec.Schedule.Windows = append(vr.Spec.Schedule.Windows, storagev1alpha1.MaintenanceWindow{
+			Start:    start,
+			Duration: metav1.Duration{Duration: windowDuration},
+		})
+	}
+
+	// Add strategy if specified
+	if maxUnavailable != "" || offline {
+		vr.Spec.Strategy = &storagev1alpha1.MigrationStrategy{}
+	}
+	if maxUnavailable != "" {
+		value := intstr.Parse(maxUnavailable)
+		vr.Spec.Strategy.MaxUnavailable = &value
+	}
+	if offline {
+		vr.Spec.Strategy.Type = strategyTypeOffline
+	}
+
 	// Create the VolumeResize
 	if err := c.Create(ctx, vr); err != nil {
 		exitWithError("failed to create volumeresize", err)
@@ -126,6 +269,27 @@ func runCreate(cmd *cobra.Command, args []string) {
 	if storageClass != "" {
 		fmt.Printf("  StorageClass: %s\n", storageClass)
 	}
+	if maxUnavailable != "" {
+		fmt.Printf("  MaxUnavailable: %s\n", maxUnavailable)
+	}
+	if offline {
+		fmt.Printf("  Strategy:    Offline\n")
+	}
+	if canary {
+		fmt.Printf("  Canary:      soaked for %s\n", canarySoak)
+	}
+	if order != "" {
+		fmt.Printf("  Order:       %s\n", order)
+	}
+	if len(replicaSubset) > 0 {
+		fmt.Printf("  Replicas:    %s\n", strings.Join(replicaSubset, ", "))
+	}
+	if notBefore != "" {
+		fmt.Printf("  NotBefore:   %s\n", notBefore)
+	}
+	for _, start := range windows {
+		fmt.Printf("  Window:      %s for %s\n", start, windowDuration)
+	}
 	fmt.Println()
 	fmt.Printf("Monitor progress with:\n")
 	fmt.Printf("  volmig watch %s -n %s\n", name, namespace)
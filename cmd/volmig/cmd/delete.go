@@ -31,18 +31,30 @@ var deleteCmd = &cobra.Command{
 	Short: "Delete a VolumeResize resource",
 	Long: `Delete a VolumeResize resource by name.
 
+If the migration is still running, the operator recreates the StatefulSet from
+its backup and rebinds any PVC removed mid-swap before letting the resource go.
+Use --force to skip that and only remove the migration's own resources.
+
 Examples:
   # Delete a volume resize
   volmig delete resize-weaviate
 
   # Delete in specific namespace
-  volmig delete resize-weaviate -n production`,
+  volmig delete resize-weaviate -n production
+
+  # Delete without restoring the StatefulSet
+  volmig delete resize-weaviate --force`,
 	Args: cobra.ExactArgs(1),
 	Run:  runDelete,
 }
 
+var forceDelete bool
+
 func init() {
 	rootCmd.AddCommand(deleteCmd)
+
+	deleteCmd.Flags().BoolVar(&forceDelete, "force", false,
+		"skip restoring the StatefulSet and PVCs, only clean up the migration's resources")
 }
 
 func runDelete(cmd *cobra.Command, args []string) {
@@ -55,7 +67,14 @@ func runDelete(cmd *cobra.Command, args []string) {
 	}
 
 	vr := &storagev1alpha1.VolumeResize{}
-	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, vr); err != nil {
+	if forceDelete {
+		vr = patchVolumeResize(ctx, name, func(vr *storagev1alpha1.VolumeResize) {
+			if vr.Annotations == nil {
+				vr.Annotations = map[string]string{}
+			}
+			vr.Annotations[annotationForceDelete] = "true"
+		})
+	} else if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, vr); err != nil {
 		exitWithError("failed to get volumeresize", err)
 	}
 
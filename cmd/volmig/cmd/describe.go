@@ -19,11 +19,15 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+	"github.com/thomas-maurice/migcontroller/internal/controller"
 )
 
 var describeCmd = &cobra.Command{
@@ -75,6 +79,65 @@ func runDescribe(cmd *cobra.Command, args []string) {
 			fmt.Printf("      StorageClass: %s\n", *vol.StorageClass)
 		}
 	}
+	if len(vr.Spec.Replicas) > 0 {
+		fmt.Printf("  Replicas:     %s\n", strings.Join(vr.Spec.Replicas, ", "))
+	}
+	if order := vr.Spec.Order; order != nil {
+		fmt.Printf("  Order:        %s\n", order.Type)
+		if len(order.Replicas) > 0 {
+			fmt.Printf("    Replicas:   %v\n", order.Replicas)
+		}
+		if leader := order.Leader; leader != nil && leader.Selector != nil {
+			fmt.Printf("    Leader:     %s\n", metav1.FormatLabelSelector(leader.Selector))
+		} else if leader != nil && leader.Exec != nil {
+			fmt.Printf("    Leader:     exec %s\n", strings.Join(leader.Exec.Command, " "))
+		}
+	}
+	if canary := vr.Spec.Canary; canary != nil {
+		fmt.Println("  Canary:")
+		if canary.Replica != nil {
+			fmt.Printf("    Replica:    %d\n", *canary.Replica)
+		}
+		fmt.Printf("    Soak:       %s\n", canary.SoakDuration.Duration)
+		fmt.Printf("    MaxRestarts: %d\n", canary.MaxRestarts)
+		if canary.OnFailure != "" {
+			fmt.Printf("    OnFailure:  %s\n", canary.OnFailure)
+		}
+	}
+	if vr.Spec.MinReadySeconds != nil {
+		fmt.Printf("  MinReadySeconds: %d\n", *vr.Spec.MinReadySeconds)
+	}
+	if len(vr.Spec.HealthGates) > 0 {
+		fmt.Println("  HealthGates:")
+	}
+	for _, gate := range vr.Spec.HealthGates {
+		switch {
+		case gate.HTTPGet != nil:
+			fmt.Printf("    - %s: GET %s on port %s\n", gate.Name, gate.HTTPGet.Path, gate.HTTPGet.Port.String())
+		case gate.Exec != nil:
+			fmt.Printf("    - %s: exec %s\n", gate.Name, strings.Join(gate.Exec.Command, " "))
+		case gate.Job != nil:
+			fmt.Printf("    - %s: job %s\n", gate.Name, gate.Job.Image)
+		}
+	}
+	if hooks := vr.Spec.Hooks; hooks != nil {
+		fmt.Println("  Hooks:")
+		printHooks("PreStop", hooks.PreStop)
+		printHooks("PostCopy", hooks.PostCopy)
+		printHooks("PostStart", hooks.PostStart)
+	}
+	if sched := vr.Spec.Schedule; sched != nil {
+		fmt.Println("  Schedule:")
+		if sched.NotBefore != nil {
+			fmt.Printf("    NotBefore:  %s\n", sched.NotBefore.Format(time.RFC3339))
+		}
+		if sched.TimeZone != "" {
+			fmt.Printf("    TimeZone:   %s\n", sched.TimeZone)
+		}
+		for _, w := range sched.Windows {
+			fmt.Printf("    Window:     %s for %s\n", w.Start, w.Duration.Duration)
+		}
+	}
 	fmt.Println()
 
 	fmt.Println("Status:")
@@ -84,7 +147,9 @@ func runDescribe(cmd *cobra.Command, args []string) {
 	}
 	fmt.Printf("  Phase:        %s\n", phase)
 
-	if vr.Status.CurrentReplica != nil {
+	if len(vr.Status.InFlightReplicas) > 0 {
+		fmt.Printf("  InFlightReplicas: %s\n", controller.FormatReplicas(vr.Status.InFlightReplicas))
+	} else if vr.Status.CurrentReplica != nil {
 		fmt.Printf("  CurrentReplica: %d\n", *vr.Status.CurrentReplica)
 	}
 	if vr.Status.CurrentVolume != "" {
@@ -96,6 +161,27 @@ func runDescribe(cmd *cobra.Command, args []string) {
 	if vr.Status.BackupConfigMapName != "" {
 		fmt.Printf("  BackupConfigMap: %s\n", vr.Status.BackupConfigMapName)
 	}
+	if p := vr.Status.PausedParent; p != nil {
+		fmt.Printf("  PausedParent: %s %s/%s (%s)\n", p.Kind, p.Namespace, p.Name, p.Type)
+	}
+	if canary := vr.Status.Canary; canary != nil {
+		fmt.Printf("  Canary:       replica %d %s (%d restarts)\n", canary.Replica, canary.Phase, canary.Restarts)
+		if canary.ReadyTime != nil {
+			fmt.Printf("    ReadySince: %s\n", canary.ReadyTime.Format("2006-01-02 15:04:05"))
+		}
+		if canary.Message != "" {
+			fmt.Printf("    Reason:     %s\n", canary.Message)
+		}
+	}
+	if vr.Status.PendingApproval != nil {
+		fmt.Printf("  PendingApproval: replica %d\n", *vr.Status.PendingApproval)
+	}
+	if len(vr.Status.ApprovedReplicas) > 0 {
+		fmt.Printf("  ApprovedReplicas: %s\n", controller.FormatReplicas(vr.Status.ApprovedReplicas))
+	}
+	if g := vr.Status.SuspendedGitOps; g != nil {
+		fmt.Printf("  SuspendedGitOps: %s %s/%s\n", g.Kind, g.Namespace, g.Name)
+	}
 	if vr.Status.StartTime != nil {
 		fmt.Printf("  StartTime:    %s\n", vr.Status.StartTime.Format("2006-01-02 15:04:05"))
 	}
@@ -128,6 +214,18 @@ func runDescribe(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	if len(vr.Status.Hooks) > 0 {
+		fmt.Println()
+		fmt.Println("Hooks:")
+		for _, hs := range vr.Status.Hooks {
+			fmt.Printf("  - %s %s, Replica: %d\n", hs.Stage, hs.Name, hs.Replica)
+			fmt.Printf("    Phase:    %s\n", hs.Phase)
+			if hs.Message != "" {
+				fmt.Printf("    Message:  %s\n", hs.Message)
+			}
+		}
+	}
+
 	if len(vr.Status.Conditions) > 0 {
 		fmt.Println()
 		fmt.Println("Conditions:")
@@ -143,3 +241,15 @@ func runDescribe(cmd *cobra.Command, args []string) {
 		}
 	}
 }
+
+// printHooks lists the hooks of a stage
+func printHooks(stage string, hooks []storagev1alpha1.ReplicaHook) {
+	for _, hook := range hooks {
+		switch {
+		case hook.Exec != nil:
+			fmt.Printf("    - %s %s: exec %s\n", stage, hook.Name, strings.Join(hook.Exec.Command, " "))
+		case hook.Job != nil:
+			fmt.Printf("    - %s %s: job %s\n", stage, hook.Name, hook.Job.Image)
+		}
+	}
+}
@@ -0,0 +1,316 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// Diff color modes
+const (
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+)
+
+const (
+	ansiRed   = "\033[31m"
+	ansiGreen = "\033[32m"
+	ansiCyan  = "\033[36m"
+	ansiReset = "\033[0m"
+)
+
+var (
+	diffOutput string
+	diffColor  string
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <volumeresize>",
+	Short: "Show what changed between the backed up and the live StatefulSet",
+	Long: `Compare the StatefulSet stored in the backup of a migration with the live one.
+Both are normalized first: status, server-set fields, the kubectl last-applied
+annotation and fields left at their Kubernetes defaults are dropped. Changes to
+volumeClaimTemplate sizes and storage classes are the ones the migration made,
+anything else drifted.
+
+Examples:
+  # Unified diff, colored on a terminal
+  volmig diff resize-weaviate
+
+  # One entry per changed field
+  volmig diff resize-weaviate -o json`,
+	Args: cobra.ExactArgs(1),
+	Run:  runDiff,
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().StringVarP(&diffOutput, "output", "o", "", "output format (json), a unified diff if unset")
+	diffCmd.Flags().StringVar(&diffColor, "color", colorAuto, "color the unified diff (auto, always, never)")
+	diffCmd.Flags().IntVar(&backupRevision, "revision", 0, "backup revision to compare with, the latest if unset")
+}
+
+// fieldChange is one field that differs between the backup and the live StatefulSet
+type fieldChange struct {
+	Path   string `json:"path"`
+	Backup any    `json:"backup,omitempty"`
+	Live   any    `json:"live,omitempty"`
+	// Migrated is set on the volumeClaimTemplate fields a migration rewrites
+	Migrated bool `json:"migrated"`
+}
+
+// stsDiff is the JSON output of volmig diff
+type stsDiff struct {
+	StatefulSet     string        `json:"statefulSet"`
+	BackupConfigMap string        `json:"backupConfigMap"`
+	Changes         []fieldChange `json:"changes"`
+}
+
+// migratedPath matches the volumeClaimTemplate fields a migration rewrites
+var migratedPath = regexp.MustCompile(`^spec\.volumeClaimTemplates\[\d+\]\.spec\.(resources\.requests\.storage|storageClassName)$`)
+
+// stsDefaults are fields the API server sets when they are left out, by path from the StatefulSet.
+// Paths under a list apply to every item, marked by [].
+var stsDefaults = map[string]any{
+	"spec.podManagementPolicy":                                     "OrderedReady",
+	"spec.revisionHistoryLimit":                                    int64(10),
+	"spec.updateStrategy.type":                                     "RollingUpdate",
+	"spec.updateStrategy.rollingUpdate.partition":                  int64(0),
+	"spec.persistentVolumeClaimRetentionPolicy.whenDeleted":        "Retain",
+	"spec.persistentVolumeClaimRetentionPolicy.whenScaled":         "Retain",
+	"spec.template.spec.restartPolicy":                             "Always",
+	"spec.template.spec.dnsPolicy":                                 "ClusterFirst",
+	"spec.template.spec.schedulerName":                             "default-scheduler",
+	"spec.template.spec.terminationGracePeriodSeconds":             int64(30),
+	"spec.template.spec.containers[].terminationMessagePath":       "/dev/termination-log",
+	"spec.template.spec.containers[].terminationMessagePolicy":     "File",
+	"spec.template.spec.initContainers[].terminationMessagePath":   "/dev/termination-log",
+	"spec.template.spec.initContainers[].terminationMessagePolicy": "File",
+	"spec.volumeClaimTemplates[].apiVersion":                       "v1",
+	"spec.volumeClaimTemplates[].kind":                             "PersistentVolumeClaim",
+	"spec.volumeClaimTemplates[].spec.volumeMode":                  "Filesystem",
+}
+
+// normalizeForDiff drops from a normalized StatefulSet what differs without anyone changing it
+func normalizeForDiff(obj map[string]any) {
+	unstructured.RemoveNestedField(obj, "metadata", "annotations", "kubectl.kubernetes.io/last-applied-configuration")
+	if annotations, found, _ := unstructured.NestedMap(obj, "metadata", "annotations"); found && len(annotations) == 0 {
+		unstructured.RemoveNestedField(obj, "metadata", "annotations")
+	}
+	for path, value := range stsDefaults {
+		removeDefault(obj, strings.Split(path, "."), value)
+	}
+	pruneEmpty(obj)
+}
+
+// removeDefault removes the field at path if it holds the default value
+func removeDefault(obj map[string]any, path []string, value any) {
+	key := path[0]
+	if list, ok := strings.CutSuffix(key, "[]"); ok {
+		items, _ := obj[list].([]any)
+		for _, item := range items {
+			if m, ok := item.(map[string]any); ok {
+				removeDefault(m, path[1:], value)
+			}
+		}
+		return
+	}
+	if len(path) == 1 {
+		if reflect.DeepEqual(obj[key], value) {
+			delete(obj, key)
+		}
+		return
+	}
+	if m, ok := obj[key].(map[string]any); ok {
+		removeDefault(m, path[1:], value)
+	}
+}
+
+// pruneEmpty removes the maps left empty by removeDefault
+func pruneEmpty(obj map[string]any) {
+	for k, v := range obj {
+		switch v := v.(type) {
+		case map[string]any:
+			pruneEmpty(v)
+			if len(v) == 0 {
+				delete(obj, k)
+			}
+		case []any:
+			for _, item := range v {
+				if m, ok := item.(map[string]any); ok {
+					pruneEmpty(m)
+				}
+			}
+		}
+	}
+}
+
+// fieldChanges lists the leaf fields that differ between two objects, sorted by path
+func fieldChanges(path string, backup, live any) []fieldChange {
+	if reflect.DeepEqual(backup, live) {
+		return nil
+	}
+
+	backupMap, backupIsMap := backup.(map[string]any)
+	liveMap, liveIsMap := live.(map[string]any)
+	if backupIsMap && liveIsMap {
+		keys := map[string]bool{}
+		for k := range backupMap {
+			keys[k] = true
+		}
+		for k := range liveMap {
+			keys[k] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+
+		changes := []fieldChange{}
+		for _, k := range sorted {
+			sub := k
+			if path != "" {
+				sub = path + "." + k
+			}
+			changes = append(changes, fieldChanges(sub, backupMap[k], liveMap[k])...)
+		}
+		return changes
+	}
+
+	backupList, backupIsList := backup.([]any)
+	liveList, liveIsList := live.([]any)
+	if backupIsList && liveIsList && len(backupList) == len(liveList) {
+		changes := []fieldChange{}
+		for i := range backupList {
+			changes = append(changes, fieldChanges(fmt.Sprintf("%s[%d]", path, i), backupList[i], liveList[i])...)
+		}
+		return changes
+	}
+
+	return []fieldChange{{Path: path, Backup: backup, Live: live, Migrated: migratedPath.MatchString(path)}}
+}
+
+// colorDiff colors the lines of a unified diff
+func colorDiff(diff string) string {
+	lines := strings.SplitAfter(diff, "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "+"):
+			lines[i] = ansiGreen + strings.TrimSuffix(line, "\n") + ansiReset + "\n"
+		case strings.HasPrefix(line, "-"):
+			lines[i] = ansiRed + strings.TrimSuffix(line, "\n") + ansiReset + "\n"
+		case strings.HasPrefix(line, "@@"):
+			lines[i] = ansiCyan + strings.TrimSuffix(line, "\n") + ansiReset + "\n"
+		}
+	}
+	return strings.Join(lines, "")
+}
+
+func runDiff(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	if diffOutput != "" && diffOutput != "json" {
+		exitWithError("invalid output format", fmt.Errorf("%q, expected json", diffOutput))
+	}
+	if diffColor != colorAuto && diffColor != colorAlways && diffColor != colorNever {
+		exitWithError("invalid color mode", fmt.Errorf("%q, expected auto, always or never", diffColor))
+	}
+
+	c, err := getClient()
+	if err != nil {
+		exitWithError("failed to create kubernetes client", err)
+	}
+
+	cm, backup := getBackup(ctx, c, args[0])
+	live := &appsv1.StatefulSet{}
+	err = c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: backup.Name}, live)
+	if apierrors.IsNotFound(err) {
+		exitWithError("nothing to compare with", fmt.Errorf("statefulset %s is missing, recreate it with volmig recover recreate-sts %s",
+			backup.Name, cm.Name))
+	}
+	if err != nil {
+		exitWithError("failed to get statefulset", err)
+	}
+
+	before, err := normalizeSTS(backup)
+	if err != nil {
+		exitWithError("failed to normalize backup", err)
+	}
+	after, err := normalizeSTS(live)
+	if err != nil {
+		exitWithError("failed to normalize statefulset", err)
+	}
+	normalizeForDiff(before)
+	normalizeForDiff(after)
+
+	if diffOutput == "json" {
+		out := stsDiff{StatefulSet: live.Name, BackupConfigMap: cm.Name, Changes: fieldChanges("", before, after)}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			exitWithError("failed to render diff", err)
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	beforeYAML, err := yaml.Marshal(before)
+	if err != nil {
+		exitWithError("failed to render backup", err)
+	}
+	afterYAML, err := yaml.Marshal(after)
+	if err != nil {
+		exitWithError("failed to render statefulset", err)
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(beforeYAML)),
+		B:        difflib.SplitLines(string(afterYAML)),
+		FromFile: "backup/" + cm.Name,
+		ToFile:   "live/" + live.Name,
+		Context:  3,
+	})
+	if err != nil {
+		exitWithError("failed to compute diff", err)
+	}
+	if diff == "" {
+		fmt.Printf("No differences between StatefulSet '%s' and its backup\n", live.Name)
+		return
+	}
+
+	if diffColor == colorAlways || (diffColor == colorAuto && term.IsTerminal(int(os.Stdout.Fd()))) {
+		diff = colorDiff(diff)
+	}
+	fmt.Print(diff)
+}
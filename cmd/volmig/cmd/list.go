@@ -28,6 +28,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+	"github.com/thomas-maurice/migcontroller/internal/controller"
 )
 
 var (
@@ -109,7 +110,9 @@ func runList(cmd *cobra.Command, args []string) {
 		}
 
 		replica := "-"
-		if vr.Status.CurrentReplica != nil {
+		if len(vr.Status.InFlightReplicas) > 0 {
+			replica = controller.FormatReplicas(vr.Status.InFlightReplicas)
+		} else if vr.Status.CurrentReplica != nil {
 			replica = fmt.Sprintf("%d", *vr.Status.CurrentReplica)
 		}
 
@@ -0,0 +1,56 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+var pauseCmd = &cobra.Command{
+	Use:   "pause <name>",
+	Short: "Pause a VolumeResize once its in-flight replicas are done",
+	Long: `Pause a VolumeResize. Replicas being migrated are finished and brought back
+online, then no further replica is started until the migration is resumed.
+
+Examples:
+  # Pause a migration
+  volmig pause resize-weaviate
+
+  # Resume it later
+  volmig resume resize-weaviate`,
+	Args: cobra.ExactArgs(1),
+	Run:  runPause,
+}
+
+func init() {
+	rootCmd.AddCommand(pauseCmd)
+}
+
+func runPause(cmd *cobra.Command, args []string) {
+	name := args[0]
+
+	patchVolumeResize(context.Background(), name, func(vr *storagev1alpha1.VolumeResize) {
+		vr.Spec.Paused = true
+	})
+
+	fmt.Printf("VolumeResize '%s' will pause once the in-flight replicas are done\n", name)
+}
@@ -0,0 +1,325 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+	"github.com/thomas-maurice/migcontroller/internal/stsbackup"
+)
+
+var recoverDryRun bool
+
+var recoverCmd = &cobra.Command{
+	Use:   "recover",
+	Short: "Inspect and repair half-finished migrations",
+	Long: `Scan a namespace for the artifacts the operator leaves around during a
+migration: StatefulSet backups, temp PVCs and retained PVs. They are grouped by
+migration, StatefulSet and replica, and the commands to repair each problem are
+suggested. Every repair command supports --dry-run.
+
+Examples:
+  # Diagnose the current namespace
+  volmig recover
+
+  # Recreate a StatefulSet from its backup
+  volmig recover recreate-sts resize-weaviate-sts-backup
+
+  # Bind a missing PVC to a retained PV
+  volmig recover rebind weaviate-data-weaviate-1 --pv pvc-1234
+
+  # Delete what is left of a migration
+  volmig recover cleanup resize-weaviate --dry-run`,
+	Args: cobra.NoArgs,
+	Run:  runRecover,
+}
+
+func init() {
+	rootCmd.AddCommand(recoverCmd)
+	recoverCmd.PersistentFlags().BoolVar(&recoverDryRun, "dry-run", false,
+		"print what would be done and validate it against the API server without persisting anything")
+}
+
+// migrationArtifacts is everything found for one migration
+type migrationArtifacts struct {
+	Migration    string
+	VolumeResize *storagev1alpha1.VolumeResize
+	Backup       *corev1.ConfigMap
+	// BackupChunks hold the revisions of Backup
+	BackupChunks []*corev1.ConfigMap
+	// BackupSTS is the StatefulSet stored in Backup
+	BackupSTS *appsv1.StatefulSet
+	// StatefulSet is the live StatefulSet, nil if it is missing
+	StatefulSet *appsv1.StatefulSet
+	TempPVCs    map[string]*corev1.PersistentVolumeClaim
+	RetainedPVs []*corev1.PersistentVolume
+}
+
+// statefulSetName returns the name of the migrated StatefulSet, or an empty string if it cannot be told
+func (m *migrationArtifacts) statefulSetName() string {
+	if m.VolumeResize != nil {
+		return m.VolumeResize.Spec.StatefulSetName
+	}
+	if m.BackupSTS != nil {
+		return m.BackupSTS.Name
+	}
+	return ""
+}
+
+// scanArtifacts collects the operator-managed artifacts of the namespace, keyed by migration name
+func scanArtifacts(ctx context.Context, c client.Client) (map[string]*migrationArtifacts, error) {
+	found := map[string]*migrationArtifacts{}
+	get := func(migration string) *migrationArtifacts {
+		if _, ok := found[migration]; !ok {
+			found[migration] = &migrationArtifacts{Migration: migration, TempPVCs: map[string]*corev1.PersistentVolumeClaim{}}
+		}
+		return found[migration]
+	}
+
+	vrList := &storagev1alpha1.VolumeResizeList{}
+	if err := c.List(ctx, vrList, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list volumeresizes: %w", err)
+	}
+	for i := range vrList.Items {
+		get(vrList.Items[i].Name).VolumeResize = &vrList.Items[i]
+	}
+
+	cmList := &corev1.ConfigMapList{}
+	if err := c.List(ctx, cmList, client.InNamespace(namespace), client.HasLabels{labelMigrationName}); err != nil {
+		return nil, fmt.Errorf("failed to list configmaps: %w", err)
+	}
+	for i := range cmList.Items {
+		cm := &cmList.Items[i]
+		m := get(cm.Labels[labelMigrationName])
+		if stsbackup.IsChunk(cm) {
+			m.BackupChunks = append(m.BackupChunks, cm)
+			continue
+		}
+		m.Backup = cm
+		sts, err := stsbackup.Read(ctx, c, cm)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: backup %s is unreadable: %v\n", cm.Name, err)
+			continue
+		}
+		m.BackupSTS = sts
+	}
+
+	pvcList := &corev1.PersistentVolumeClaimList{}
+	if err := c.List(ctx, pvcList, client.InNamespace(namespace), client.HasLabels{labelMigrationName}); err != nil {
+		return nil, fmt.Errorf("failed to list persistentvolumeclaims: %w", err)
+	}
+	for i := range pvcList.Items {
+		pvc := &pvcList.Items[i]
+		get(pvc.Labels[labelMigrationName]).TempPVCs[pvc.Name] = pvc
+	}
+
+	pvList := &corev1.PersistentVolumeList{}
+	if err := c.List(ctx, pvList, client.HasLabels{labelMigrationName}); err != nil {
+		return nil, fmt.Errorf("failed to list persistentvolumes: %w", err)
+	}
+	for i := range pvList.Items {
+		pv := &pvList.Items[i]
+		if pv.Status.Phase == corev1.VolumeBound || pv.Spec.ClaimRef == nil || pv.Spec.ClaimRef.Namespace != namespace {
+			continue
+		}
+		m := get(pv.Labels[labelMigrationName])
+		m.RetainedPVs = append(m.RetainedPVs, pv)
+	}
+
+	for _, m := range found {
+		stsName := m.statefulSetName()
+		if stsName == "" {
+			continue
+		}
+		sts := &appsv1.StatefulSet{}
+		err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: stsName}, sts)
+		if err == nil {
+			m.StatefulSet = sts
+		} else if !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get statefulset %s: %w", stsName, err)
+		}
+	}
+
+	return found, nil
+}
+
+func runRecover(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	c, err := getClient()
+	if err != nil {
+		exitWithError("failed to create kubernetes client", err)
+	}
+
+	found, err := scanArtifacts(ctx, c)
+	if err != nil {
+		exitWithError("failed to scan namespace", err)
+	}
+
+	names := []string{}
+	for name, m := range found {
+		// A VolumeResize without anything left around is not worth reporting
+		if m.Backup == nil && len(m.TempPVCs) == 0 && len(m.RetainedPVs) == 0 {
+			continue
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		fmt.Printf("No migration artifacts found in namespace '%s'\n", namespace)
+		return
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		if i > 0 {
+			fmt.Println()
+		}
+		if err := diagnose(ctx, c, found[name]); err != nil {
+			exitWithError("failed to diagnose migration "+name, err)
+		}
+	}
+}
+
+// diagnose prints the state of one migration, replica by replica, followed by the suggested repairs
+func diagnose(ctx context.Context, c client.Client, m *migrationArtifacts) error {
+	suggestions := []string{}
+	stsName := m.statefulSetName()
+
+	fmt.Printf("Migration:    %s\n", m.Migration)
+	if m.VolumeResize != nil {
+		phase := m.VolumeResize.Status.Phase
+		if phase == "" {
+			phase = phasePending
+		}
+		fmt.Printf("VolumeResize: %s\n", phase)
+	} else {
+		fmt.Println("VolumeResize: not found")
+	}
+
+	switch {
+	case stsName == "":
+		fmt.Println("StatefulSet:  unknown, no VolumeResize or backup left")
+	case m.StatefulSet != nil:
+		fmt.Printf("StatefulSet:  %s\n", stsName)
+	case m.Backup != nil:
+		fmt.Printf("StatefulSet:  %s MISSING, backup in ConfigMap %s\n", stsName, m.Backup.Name)
+		suggestions = append(suggestions, fmt.Sprintf("volmig recover recreate-sts %s -n %s", m.Backup.Name, namespace))
+	default:
+		fmt.Printf("StatefulSet:  %s MISSING, no backup found\n", stsName)
+	}
+
+	template := m.StatefulSet
+	if template == nil {
+		template = m.BackupSTS
+	}
+	if template != nil {
+		replicas := int32(1)
+		if template.Spec.Replicas != nil {
+			replicas = *template.Spec.Replicas
+		}
+
+		fmt.Println()
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "REPLICA\tVOLUME\tPVC\tTEMP PVC\tRETAINED PVS")
+		for replica := range replicas {
+			for _, vct := range template.Spec.VolumeClaimTemplates {
+				pvcName := fmt.Sprintf("%s-%s-%d", vct.Name, stsName, replica)
+				tempName := pvcName + "-new"
+
+				pvcState := "MISSING"
+				pvc := &corev1.PersistentVolumeClaim{}
+				err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: pvcName}, pvc)
+				if err == nil {
+					pvcState = describeClaim(pvc)
+				} else if !apierrors.IsNotFound(err) {
+					return fmt.Errorf("failed to get pvc %s: %w", pvcName, err)
+				}
+
+				tempState := "-"
+				if temp, ok := m.TempPVCs[tempName]; ok {
+					tempState = describeClaim(temp)
+				}
+
+				retained := "-"
+				var oldPV, newPV string
+				for _, pv := range m.RetainedPVs {
+					switch pv.Spec.ClaimRef.Name {
+					case pvcName:
+						oldPV = pv.Name
+					case tempName:
+						newPV = pv.Name
+					default:
+						continue
+					}
+					if retained == "-" {
+						retained = ""
+					} else {
+						retained += ","
+					}
+					retained += fmt.Sprintf("%s (%s)", pv.Name, pv.Status.Phase)
+				}
+
+				_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", replica, vct.Name, pvcState, tempState, retained)
+
+				if pvcState == "MISSING" {
+					// The old PV is the safe choice, the new one may hold an incomplete copy
+					if oldPV != "" {
+						suggestions = append(suggestions, fmt.Sprintf("volmig recover rebind %s --pv %s -n %s", pvcName, oldPV, namespace))
+					} else if newPV != "" {
+						suggestions = append(suggestions, fmt.Sprintf("volmig recover rebind %s --pv %s -n %s", pvcName, newPV, namespace))
+					}
+				}
+			}
+		}
+		_ = w.Flush()
+	}
+
+	if m.VolumeResize == nil {
+		suggestions = append(suggestions, fmt.Sprintf("volmig recover cleanup %s -n %s", m.Migration, namespace))
+	}
+
+	fmt.Println()
+	if len(suggestions) == 0 {
+		fmt.Println("Nothing to repair, the operator still manages this migration.")
+		return nil
+	}
+	fmt.Println("Suggested actions:")
+	for _, s := range suggestions {
+		fmt.Printf("  %s\n", s)
+	}
+	return nil
+}
+
+// describeClaim summarises the binding of a PVC
+func describeClaim(pvc *corev1.PersistentVolumeClaim) string {
+	if pvc.Spec.VolumeName == "" {
+		return string(pvc.Status.Phase)
+	}
+	return fmt.Sprintf("%s to %s", pvc.Status.Phase, pvc.Spec.VolumeName)
+}
@@ -0,0 +1,323 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+	"github.com/thomas-maurice/migcontroller/internal/stsbackup"
+)
+
+var (
+	rebindPV      string
+	cleanupDelPVs bool
+)
+
+var recoverRecreateSTSCmd = &cobra.Command{
+	Use:   "recreate-sts <backup-configmap>",
+	Short: "Recreate a missing StatefulSet from its backup ConfigMap",
+	Args:  cobra.ExactArgs(1),
+	Run:   runRecoverRecreateSTS,
+}
+
+var recoverRebindCmd = &cobra.Command{
+	Use:   "rebind <pvc>",
+	Short: "Create a missing PVC bound to a retained PV",
+	Long: `Create a PVC bound to the given PV. The PV is released from its previous
+claim first. The PVC must not exist.
+
+Examples:
+  volmig recover rebind weaviate-data-weaviate-1 --pv pvc-1234 --dry-run`,
+	Args: cobra.ExactArgs(1),
+	Run:  runRecoverRebind,
+}
+
+var recoverCleanupCmd = &cobra.Command{
+	Use:   "cleanup <migration>",
+	Short: "Delete the leftovers of a migration whose VolumeResize is gone",
+	Long: `Delete the temp PVCs, pods, Services and Secrets labelled with the migration.
+The backup ConfigMap is only deleted once the StatefulSet exists again. Retained
+PVs are kept unless --delete-pvs is set.`,
+	Args: cobra.ExactArgs(1),
+	Run:  runRecoverCleanup,
+}
+
+func init() {
+	recoverCmd.AddCommand(recoverRecreateSTSCmd)
+	recoverCmd.AddCommand(recoverRebindCmd)
+	recoverCmd.AddCommand(recoverCleanupCmd)
+
+	recoverRebindCmd.Flags().StringVar(&rebindPV, "pv", "", "name of the PV to bind the PVC to (required)")
+	_ = recoverRebindCmd.MarkFlagRequired("pv")
+	recoverCleanupCmd.Flags().BoolVar(&cleanupDelPVs, "delete-pvs", false,
+		"also delete the unbound PVs retained by the migration, destroying their data")
+}
+
+// dryRunOpts returns the options making writes server-side dry runs when --dry-run is set
+func dryRunOpts() []client.CreateOption {
+	if recoverDryRun {
+		return []client.CreateOption{client.DryRunAll}
+	}
+	return nil
+}
+
+// actionPrefix marks the output of dry runs
+func actionPrefix() string {
+	if recoverDryRun {
+		return "[dry-run] "
+	}
+	return ""
+}
+
+func runRecoverRecreateSTS(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	c, err := getClient()
+	if err != nil {
+		exitWithError("failed to create kubernetes client", err)
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: args[0]}, cm); err != nil {
+		exitWithError("failed to get backup configmap", err)
+	}
+	backup, err := stsbackup.Read(ctx, c, cm)
+	if err != nil {
+		exitWithError("failed to read backup", err)
+	}
+
+	err = c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: backup.Name}, &appsv1.StatefulSet{})
+	if err == nil {
+		exitWithError("refusing to recreate", fmt.Errorf("statefulset %s already exists", backup.Name))
+	}
+	if !apierrors.IsNotFound(err) {
+		exitWithError("failed to get statefulset", err)
+	}
+
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            backup.Name,
+			Namespace:       namespace,
+			Labels:          backup.Labels,
+			Annotations:     backup.Annotations,
+			OwnerReferences: backup.OwnerReferences,
+		},
+		Spec: backup.Spec,
+	}
+	if err := c.Create(ctx, sts, dryRunOpts()...); err != nil {
+		exitWithError("failed to create statefulset", err)
+	}
+
+	fmt.Printf("%sStatefulSet '%s' recreated from '%s'\n", actionPrefix(), sts.Name, cm.Name)
+}
+
+func runRecoverRebind(cmd *cobra.Command, args []string) {
+	pvcName := args[0]
+	ctx := context.Background()
+
+	c, err := getClient()
+	if err != nil {
+		exitWithError("failed to create kubernetes client", err)
+	}
+
+	err = c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: pvcName}, &corev1.PersistentVolumeClaim{})
+	if err == nil {
+		exitWithError("refusing to rebind", fmt.Errorf("pvc %s already exists", pvcName))
+	}
+	if !apierrors.IsNotFound(err) {
+		exitWithError("failed to get pvc", err)
+	}
+
+	pv := &corev1.PersistentVolume{}
+	if err := c.Get(ctx, types.NamespacedName{Name: rebindPV}, pv); err != nil {
+		exitWithError("failed to get pv", err)
+	}
+	if pv.Status.Phase == corev1.VolumeBound {
+		exitWithError("refusing to rebind", fmt.Errorf("pv %s is bound to %s/%s",
+			pv.Name, pv.Spec.ClaimRef.Namespace, pv.Spec.ClaimRef.Name))
+	}
+
+	if pv.Spec.ClaimRef != nil {
+		patch := client.MergeFrom(pv.DeepCopy())
+		pv.Spec.ClaimRef = nil
+		var opts []client.PatchOption
+		if recoverDryRun {
+			opts = append(opts, client.DryRunAll)
+		}
+		if err := c.Patch(ctx, pv, patch, opts...); err != nil {
+			exitWithError("failed to release pv", err)
+		}
+		fmt.Printf("%sPV '%s' released from its previous claim\n", actionPrefix(), pv.Name)
+	}
+
+	var storageClassName *string
+	if pv.Spec.StorageClassName != "" {
+		storageClassName = &pv.Spec.StorageClassName
+	}
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pvcName,
+			Namespace: namespace,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      pv.Spec.AccessModes,
+			StorageClassName: storageClassName,
+			VolumeMode:       pv.Spec.VolumeMode,
+			VolumeName:       pv.Name,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: pv.Spec.Capacity[corev1.ResourceStorage],
+				},
+			},
+		},
+	}
+	if err := c.Create(ctx, pvc, dryRunOpts()...); err != nil {
+		exitWithError("failed to create pvc", err)
+	}
+
+	fmt.Printf("%sPVC '%s' created and bound to PV '%s'\n", actionPrefix(), pvcName, pv.Name)
+}
+
+func runRecoverCleanup(cmd *cobra.Command, args []string) {
+	migration := args[0]
+	ctx := context.Background()
+
+	c, err := getClient()
+	if err != nil {
+		exitWithError("failed to create kubernetes client", err)
+	}
+
+	err = c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: migration}, &storagev1alpha1.VolumeResize{})
+	if err == nil {
+		exitWithError("refusing to clean up",
+			fmt.Errorf("volumeresize %s still exists, use 'volmig delete' instead", migration))
+	}
+	if !apierrors.IsNotFound(err) {
+		exitWithError("failed to get volumeresize", err)
+	}
+
+	found, err := scanArtifacts(ctx, c)
+	if err != nil {
+		exitWithError("failed to scan namespace", err)
+	}
+
+	inMigration := []client.ListOption{client.InNamespace(namespace), client.MatchingLabels{labelMigrationName: migration}}
+	toDelete := []client.Object{}
+
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, inMigration...); err != nil {
+		exitWithError("failed to list pods", err)
+	}
+	for i := range pods.Items {
+		toDelete = append(toDelete, &pods.Items[i])
+	}
+	services := &corev1.ServiceList{}
+	if err := c.List(ctx, services, inMigration...); err != nil {
+		exitWithError("failed to list services", err)
+	}
+	for i := range services.Items {
+		toDelete = append(toDelete, &services.Items[i])
+	}
+	secrets := &corev1.SecretList{}
+	if err := c.List(ctx, secrets, inMigration...); err != nil {
+		exitWithError("failed to list secrets", err)
+	}
+	for i := range secrets.Items {
+		toDelete = append(toDelete, &secrets.Items[i])
+	}
+	jobs := &batchv1.JobList{}
+	if err := c.List(ctx, jobs, inMigration...); err != nil {
+		exitWithError("failed to list jobs", err)
+	}
+	for i := range jobs.Items {
+		toDelete = append(toDelete, &jobs.Items[i])
+	}
+
+	if m, ok := found[migration]; ok {
+		for _, pvc := range m.TempPVCs {
+			toDelete = append(toDelete, pvc)
+		}
+		if m.Backup != nil {
+			if m.StatefulSet != nil {
+				toDelete = append(toDelete, m.Backup)
+				for _, chunk := range m.BackupChunks {
+					toDelete = append(toDelete, chunk)
+				}
+			} else {
+				fmt.Printf("Keeping ConfigMap '%s', StatefulSet '%s' is missing and this is its only backup\n",
+					m.Backup.Name, m.statefulSetName())
+			}
+		}
+		for _, pv := range m.RetainedPVs {
+			if cleanupDelPVs {
+				toDelete = append(toDelete, pv)
+			} else {
+				fmt.Printf("Keeping PV '%s', use --delete-pvs to delete it\n", pv.Name)
+			}
+		}
+	}
+
+	if len(toDelete) == 0 {
+		fmt.Printf("Nothing to clean up for migration '%s'\n", migration)
+		return
+	}
+
+	// Jobs leave their pods behind unless the deletion propagates
+	opts := []client.DeleteOption{client.PropagationPolicy(metav1.DeletePropagationBackground)}
+	if recoverDryRun {
+		opts = append(opts, client.DryRunAll)
+	}
+	for _, obj := range toDelete {
+		if err := c.Delete(ctx, obj, opts...); err != nil && !apierrors.IsNotFound(err) {
+			exitWithError("failed to delete "+obj.GetName(), err)
+		}
+		fmt.Printf("%sDeleted %s '%s'\n", actionPrefix(), kindOf(obj), obj.GetName())
+	}
+}
+
+// kindOf names the kind of a typed object for display, the API server leaves TypeMeta empty on lists
+func kindOf(obj client.Object) string {
+	switch obj.(type) {
+	case *corev1.Pod:
+		return "Pod"
+	case *corev1.Service:
+		return "Service"
+	case *corev1.Secret:
+		return "Secret"
+	case *batchv1.Job:
+		return "Job"
+	case *corev1.ConfigMap:
+		return "ConfigMap"
+	case *corev1.PersistentVolumeClaim:
+		return "PVC"
+	case *corev1.PersistentVolume:
+		return "PV"
+	default:
+		return "object"
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+var resumeCmd = &cobra.Command{
+	Use:   "resume <name>",
+	Short: "Resume a paused VolumeResize",
+	Long: `Resume a VolumeResize paused with 'volmig pause'.
+
+Examples:
+  # Resume a migration
+  volmig resume resize-weaviate`,
+	Args: cobra.ExactArgs(1),
+	Run:  runResume,
+}
+
+func init() {
+	rootCmd.AddCommand(resumeCmd)
+}
+
+func runResume(cmd *cobra.Command, args []string) {
+	name := args[0]
+
+	patchVolumeResize(context.Background(), name, func(vr *storagev1alpha1.VolumeResize) {
+		vr.Spec.Paused = false
+	})
+
+	fmt.Printf("VolumeResize '%s' resumed\n", name)
+}
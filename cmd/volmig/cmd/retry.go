@@ -0,0 +1,68 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+var retryCmd = &cobra.Command{
+	Use:   "retry <name>",
+	Short: "Retry a failed VolumeResize",
+	Long: `Retry a failed VolumeResize. The migration moves back into Syncing and resumes
+from the replicas that were not migrated yet, starting their copies from scratch.
+
+Examples:
+  # Retry a failed migration
+  volmig retry resize-weaviate
+
+  # Retry and watch progress
+  volmig retry resize-weaviate --watch`,
+	Args: cobra.ExactArgs(1),
+	Run:  runRetry,
+}
+
+var retryWatch bool
+
+func init() {
+	rootCmd.AddCommand(retryCmd)
+
+	retryCmd.Flags().BoolVarP(&retryWatch, "watch", "w", false, "watch migration progress after retrying")
+}
+
+func runRetry(cmd *cobra.Command, args []string) {
+	name := args[0]
+
+	vr := patchVolumeResize(context.Background(), name, func(vr *storagev1alpha1.VolumeResize) {
+		if vr.Status.Phase != phaseFailed {
+			exitWithError("only failed migrations can be retried", fmt.Errorf("phase: %s", vr.Status.Phase))
+		}
+		vr.Spec.RetryGeneration++
+	})
+
+	fmt.Printf("VolumeResize '%s' retry requested (generation %d)\n", name, vr.Spec.RetryGeneration)
+
+	if retryWatch {
+		fmt.Println()
+		runWatch(cmd, []string{name})
+	}
+}
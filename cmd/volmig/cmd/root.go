@@ -17,12 +17,14 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
@@ -105,6 +107,27 @@ func getClient() (client.Client, error) {
 	return c, nil
 }
 
+// patchVolumeResize fetches a VolumeResize, applies mutate to its spec and merge-patches the change
+func patchVolumeResize(ctx context.Context, name string, mutate func(vr *storagev1alpha1.VolumeResize)) *storagev1alpha1.VolumeResize {
+	c, err := getClient()
+	if err != nil {
+		exitWithError("failed to create kubernetes client", err)
+	}
+
+	vr := &storagev1alpha1.VolumeResize{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, vr); err != nil {
+		exitWithError("failed to get volumeresize", err)
+	}
+
+	patch := client.MergeFrom(vr.DeepCopy())
+	mutate(vr)
+	if err := c.Patch(ctx, vr, patch); err != nil {
+		exitWithError("failed to patch volumeresize", err)
+	}
+
+	return vr
+}
+
 func exitWithError(msg string, err error) {
 	fmt.Fprintf(os.Stderr, "Error: %s: %v\n", msg, err)
 	os.Exit(1)
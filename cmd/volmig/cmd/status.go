@@ -79,10 +79,12 @@ func runStatus(cmd *cobra.Command, args []string) {
 	switch phase {
 	case phaseCompleted:
 		icon = "✅"
-	case phaseFailed:
+	case phaseFailed, phaseAborted:
 		icon = "❌"
 	case "Syncing":
 		icon = "🔄"
+	case "Paused":
+		icon = "⏸️"
 	case "Validating":
 		icon = "🔍"
 	}
@@ -92,6 +94,12 @@ func runStatus(cmd *cobra.Command, args []string) {
 	if vr.Status.Message != "" {
 		fmt.Printf("   Message: %s\n", vr.Status.Message)
 	}
+	if canary := vr.Status.Canary; canary != nil && canary.Phase != canaryPhasePassed {
+		fmt.Printf("   Canary: replica %d %s\n", canary.Replica, canary.Phase)
+	}
+	if vr.Status.PendingApproval != nil {
+		fmt.Printf("   Approve with: volmig approve %s --replica %d -n %s\n", name, *vr.Status.PendingApproval, namespace)
+	}
 	fmt.Println()
 
 	// Print spec summary
@@ -151,7 +159,7 @@ func runStatus(cmd *cobra.Command, args []string) {
 	}
 
 	// If following, continue watching
-	if followStatus && phase != phaseCompleted && phase != phaseFailed {
+	if followStatus && phase != phaseCompleted && phase != phaseFailed && phase != phaseAborted {
 		fmt.Println()
 		fmt.Println("Following status updates (Ctrl+C to stop)...")
 		fmt.Println()
@@ -160,7 +168,7 @@ func runStatus(cmd *cobra.Command, args []string) {
 	}
 
 	// Exit with error if failed
-	if phase == phaseFailed {
+	if phase == phaseFailed || phase == phaseAborted {
 		os.Exit(1)
 	}
 }
@@ -26,6 +26,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 
 	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+	"github.com/thomas-maurice/migcontroller/internal/controller"
 )
 
 var watchCmd = &cobra.Command{
@@ -96,6 +97,12 @@ func runWatch(cmd *cobra.Command, args []string) {
 			os.Exit(0)
 		}
 
+		if vr.Status.Phase == phaseAborted {
+			fmt.Println()
+			fmt.Printf("Migration aborted: %s\n", vr.Status.Message)
+			os.Exit(1)
+		}
+
 		if vr.Status.Phase == phaseFailed {
 			fmt.Println()
 			fmt.Fprintf(os.Stderr, "Migration failed: %s\n", vr.Status.Message)
@@ -115,7 +122,9 @@ func printStatus(vr *storagev1alpha1.VolumeResize) {
 
 	fmt.Printf("[%s] Phase: %-12s", timestamp, phase)
 
-	if vr.Status.CurrentReplica != nil {
+	if len(vr.Status.InFlightReplicas) > 1 {
+		fmt.Printf(" | Replicas: %s", controller.FormatReplicas(vr.Status.InFlightReplicas))
+	} else if vr.Status.CurrentReplica != nil {
 		fmt.Printf(" | Replica: %d", *vr.Status.CurrentReplica)
 	}
 
@@ -0,0 +1,92 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+// approvalRequired reports whether the batch starting with the next replica waits for an approval.
+// The first batch never does, someone just created the VolumeResize.
+func approvalRequired(vr *storagev1alpha1.VolumeResize, next int32) bool {
+	started := slices.ContainsFunc(vr.Status.VolumeStatuses, func(vs storagev1alpha1.VolumeStatus) bool {
+		return vs.Phase == VolumeStatusCompleted
+	})
+	if !started {
+		return false
+	}
+
+	switch vr.Spec.Approval {
+	case ApprovalPerReplica:
+		return !slices.Contains(vr.Status.ApprovedReplicas, next)
+	case ApprovalAfterFirst:
+		return len(vr.Status.ApprovedReplicas) == 0
+	default:
+		return false
+	}
+}
+
+// checkApproval reports whether the batch starting with the next replica may start. An approval
+// annotation naming it is recorded in the status, otherwise the wait is reported once.
+func (r *VolumeResizeReconciler) checkApproval(ctx context.Context, vr *storagev1alpha1.VolumeResize, next int32) (bool, error) {
+	if !approvalRequired(vr, next) {
+		return true, nil
+	}
+	log := logf.FromContext(ctx)
+
+	if vr.Annotations[AnnotationApprovedReplica] == strconv.Itoa(int(next)) {
+		log.Info("Replica approved", "replica", next)
+		vr.Status.ApprovedReplicas = append(vr.Status.ApprovedReplicas, next)
+		vr.Status.PendingApproval = nil
+		meta.SetStatusCondition(&vr.Status.Conditions, metav1.Condition{
+			Type:               ConditionTypeAwaitingApproval,
+			Status:             metav1.ConditionFalse,
+			Reason:             "Approved",
+			Message:            fmt.Sprintf("Replica %d was approved", next),
+			ObservedGeneration: vr.Generation,
+		})
+		r.recordEvent(vr, corev1.EventTypeNormal, "Approved", "Approve", "Replica %d was approved", next)
+		return true, r.Status().Update(ctx, vr)
+	}
+
+	if vr.Status.PendingApproval != nil && *vr.Status.PendingApproval == next {
+		return false, nil
+	}
+	log.Info("Waiting for approval", "replica", next)
+	vr.Status.PendingApproval = ptrInt32(next)
+	vr.Status.Message = fmt.Sprintf("Awaiting approval to migrate replica %d", next)
+	meta.SetStatusCondition(&vr.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeAwaitingApproval,
+		Status:             metav1.ConditionTrue,
+		Reason:             "ReplicaPending",
+		Message:            fmt.Sprintf("Replica %d is next, approve it with: volmig approve %s --replica %d", next, vr.Name, next),
+		ObservedGeneration: vr.Generation,
+	})
+	r.recordEvent(vr, corev1.EventTypeNormal, "AwaitingApproval", "Approve",
+		"Replica %d is next, set the %s annotation to %d to start it", next, AnnotationApprovedReplica, next)
+	return false, r.Status().Update(ctx, vr)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+func TestApprovalRequired(t *testing.T) {
+	vr := controlsTestVR()
+	assert.False(t, approvalRequired(vr, 1))
+
+	vr.Spec.Approval = ApprovalPerReplica
+	assert.True(t, approvalRequired(vr, 1))
+	vr.Status.ApprovedReplicas = []int32{1}
+	assert.False(t, approvalRequired(vr, 1))
+	assert.True(t, approvalRequired(vr, 2))
+
+	vr.Spec.Approval = ApprovalAfterFirst
+	assert.False(t, approvalRequired(vr, 2))
+	vr.Status.ApprovedReplicas = nil
+	assert.True(t, approvalRequired(vr, 2))
+
+	// Nothing was migrated yet, the first batch starts right away
+	vr.Status.VolumeStatuses = []storagev1alpha1.VolumeStatus{{VolumeName: "data", Replica: 0, Phase: VolumeStatusPending}}
+	assert.False(t, approvalRequired(vr, 0))
+}
+
+func TestApprovalHoldsBetweenBatches(t *testing.T) {
+	vr := controlsTestVR()
+	vr.Spec.Approval = ApprovalPerReplica
+	// Replica 1 failed, only replica 2 is left to migrate
+	vr.Status.VolumeStatuses[1].Phase = VolumeStatusCompleted
+	r, c := newControlsTestReconciler(t, vr, controlsTestSTS())
+	require.NoError(t, policyv1.AddToScheme(r.Scheme))
+	ctx := context.Background()
+
+	_, err := r.startNextBatch(ctx, vr)
+	require.NoError(t, err)
+
+	updated := &storagev1alpha1.VolumeResize{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-resize"}, updated))
+	assert.Empty(t, updated.Status.InFlightReplicas)
+	require.NotNil(t, updated.Status.PendingApproval)
+	assert.Equal(t, int32(2), *updated.Status.PendingApproval)
+	assert.True(t, meta.IsStatusConditionTrue(updated.Status.Conditions, ConditionTypeAwaitingApproval))
+
+	// Approving another replica does nothing
+	updated.Annotations = map[string]string{AnnotationApprovedReplica: "1"}
+	_, err = r.startNextBatch(ctx, updated)
+	require.NoError(t, err)
+	assert.Empty(t, updated.Status.InFlightReplicas)
+
+	updated.Annotations[AnnotationApprovedReplica] = "2"
+	_, err = r.startNextBatch(ctx, updated)
+	require.NoError(t, err)
+	assert.Equal(t, []int32{2}, updated.Status.InFlightReplicas)
+	assert.Equal(t, []int32{2}, updated.Status.ApprovedReplicas)
+	assert.Nil(t, updated.Status.PendingApproval)
+	assert.True(t, meta.IsStatusConditionFalse(updated.Status.Conditions, ConditionTypeAwaitingApproval))
+}
+
+func TestPerReplicaApprovalMigratesOneAtATime(t *testing.T) {
+	vr := controlsTestVR()
+	vr.Spec.Approval = ApprovalPerReplica
+	maxUnavailable := intstr.FromInt32(3)
+	vr.Spec.Strategy = &storagev1alpha1.MigrationStrategy{MaxUnavailable: &maxUnavailable}
+	vr.Status.VolumeStatuses[1].Phase = VolumeStatusPending
+	vr.Annotations = map[string]string{AnnotationApprovedReplica: "1"}
+	r, _ := newControlsTestReconciler(t, vr, controlsTestSTS())
+	require.NoError(t, policyv1.AddToScheme(r.Scheme))
+	ctx := context.Background()
+
+	// Replica 2 was not approved, it must not ride along with replica 1
+	_, err := r.startNextBatch(ctx, vr)
+	require.NoError(t, err)
+	assert.Equal(t, []int32{1}, vr.Status.InFlightReplicas)
+}
@@ -0,0 +1,254 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+const (
+	// defaultCanarySoakDuration applies when the CRD default was not set
+	defaultCanarySoakDuration = 30 * time.Minute
+	// canaryPollInterval is how often the canary pod is checked during the soak
+	canaryPollInterval = 15 * time.Second
+)
+
+// canarySoakDuration returns how long the canary must stay ready
+func canarySoakDuration(spec *storagev1alpha1.CanarySpec) time.Duration {
+	if spec.SoakDuration.Duration <= 0 {
+		return defaultCanarySoakDuration
+	}
+	return spec.SoakDuration.Duration
+}
+
+// canaryReplica returns the replica migrated on its own first, as long as spec.canary has not been migrated
+func canaryReplica(vr *storagev1alpha1.VolumeResize, pending []int32) (int32, bool) {
+	if vr.Spec.Canary == nil || len(pending) == 0 {
+		return 0, false
+	}
+	if vr.Status.Canary != nil && vr.Status.Canary.Phase != CanaryPhaseMigrating {
+		return 0, false
+	}
+
+	replica := pending[0]
+	if vr.Spec.Canary.Replica != nil {
+		replica = *vr.Spec.Canary.Replica
+	}
+	return replica, slices.Contains(pending, replica)
+}
+
+// startCanarySoak starts the soak once the batch holding the canary is back online
+func startCanarySoak(vr *storagev1alpha1.VolumeResize, batch []int32) {
+	canary := vr.Status.Canary
+	if canary == nil || canary.Phase != CanaryPhaseMigrating || !slices.Contains(batch, canary.Replica) {
+		return
+	}
+	now := metav1.Now()
+	canary.Phase = CanaryPhaseSoaking
+	canary.SoakStartTime = &now
+}
+
+// podRestarts sums the restarts of every container of a pod
+func podRestarts(pod *corev1.Pod) int32 {
+	restarts := int32(0)
+	for _, cs := range pod.Status.InitContainerStatuses {
+		restarts += cs.RestartCount
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		restarts += cs.RestartCount
+	}
+	return restarts
+}
+
+// podReadyCondition returns the Ready condition of a pod when it is true
+func podReadyCondition(pod *corev1.Pod) *corev1.PodCondition {
+	if pod == nil {
+		return nil
+	}
+	for i := range pod.Status.Conditions {
+		cond := &pod.Status.Conditions[i]
+		if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue {
+			return cond
+		}
+	}
+	return nil
+}
+
+// evaluateCanary updates the canary status from its pod, nil when the pod is gone. Returns whether the
+// soak is over, or why the canary failed. A Ready condition that changed since the pod first became
+// ready means it went unready in between two checks.
+func evaluateCanary(spec *storagev1alpha1.CanarySpec, canary *storagev1alpha1.CanaryStatus, pod *corev1.Pod, now time.Time) (bool, string) {
+	soak := canarySoakDuration(spec)
+	if pod != nil {
+		canary.Restarts = podRestarts(pod)
+	}
+	if canary.Restarts > spec.MaxRestarts {
+		return false, fmt.Sprintf("restarted %d times, more than the %d allowed", canary.Restarts, spec.MaxRestarts)
+	}
+
+	ready := podReadyCondition(pod)
+	if canary.ReadyTime == nil {
+		if ready == nil {
+			if canary.SoakStartTime != nil && now.Sub(canary.SoakStartTime.Time) >= soak {
+				return false, fmt.Sprintf("did not become ready within %s", soak)
+			}
+			return false, ""
+		}
+		readyTime := ready.LastTransitionTime
+		if readyTime.IsZero() {
+			readyTime = metav1.NewTime(now)
+		}
+		canary.ReadyTime = &readyTime
+	} else if ready == nil || ready.LastTransitionTime.After(canary.ReadyTime.Time) {
+		return false, "went unready"
+	}
+
+	return now.Sub(canary.ReadyTime.Time) >= soak, ""
+}
+
+// soakCanary holds the next batch while the canary soaks, and acts on its failure. Returns false once the
+// canary passed, or when there is none.
+func (r *VolumeResizeReconciler) soakCanary(ctx context.Context, vr *storagev1alpha1.VolumeResize) (ctrl.Result, bool, error) {
+	canary := vr.Status.Canary
+	if vr.Spec.Canary == nil || canary == nil {
+		return ctrl.Result{}, false, nil
+	}
+	switch canary.Phase {
+	case CanaryPhaseFailed:
+		result, err := r.failCanary(ctx, vr)
+		return result, true, err
+	case CanaryPhaseSoaking:
+	default:
+		return ctrl.Result{}, false, nil
+	}
+	log := logf.FromContext(ctx)
+
+	var pod *corev1.Pod
+	podName := getPodName(vr.Spec.StatefulSetName, canary.Replica)
+	found := &corev1.Pod{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: vr.Namespace, Name: podName}, found)
+	if err == nil {
+		pod = found
+	} else if !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, true, err
+	}
+
+	before := canary.DeepCopy()
+	passed, failure := evaluateCanary(vr.Spec.Canary, canary, pod, time.Now())
+
+	if failure != "" {
+		log.Info("Canary failed", "replica", canary.Replica, "reason", failure)
+		canary.Phase = CanaryPhaseFailed
+		canary.Message = failure
+		r.recordEvent(vr, corev1.EventTypeWarning, "CanaryFailed", "Soak", "Canary replica %d %s", canary.Replica, failure)
+		if err := r.Status().Update(ctx, vr); err != nil {
+			return ctrl.Result{}, true, err
+		}
+		result, err := r.failCanary(ctx, vr)
+		return result, true, err
+	}
+
+	soak := canarySoakDuration(vr.Spec.Canary)
+	if passed {
+		log.Info("Canary passed its soak", "replica", canary.Replica)
+		canary.Phase = CanaryPhasePassed
+		vr.Status.Message = fmt.Sprintf("Canary replica %d stayed ready for %s", canary.Replica, soak)
+		r.recordEvent(vr, corev1.EventTypeNormal, "CanaryPassed", "Soak", "Canary replica %d stayed ready for %s", canary.Replica, soak)
+		if err := r.Status().Update(ctx, vr); err != nil {
+			return ctrl.Result{}, true, err
+		}
+		return ctrl.Result{Requeue: true}, true, nil
+	}
+
+	message := fmt.Sprintf("Waiting for canary replica %d to become ready", canary.Replica)
+	wait := canaryPollInterval
+	if canary.ReadyTime != nil {
+		end := canary.ReadyTime.Add(soak)
+		message = fmt.Sprintf("Soaking canary replica %d until %s", canary.Replica, end.Format(time.RFC3339))
+		wait = min(wait, time.Until(end))
+	}
+	if vr.Status.Message != message || !equality.Semantic.DeepEqual(before, canary) {
+		vr.Status.Message = message
+		if err := r.Status().Update(ctx, vr); err != nil {
+			return ctrl.Result{}, true, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: max(wait, time.Second)}, true, nil
+}
+
+// failCanary applies spec.canary.onFailure: the migration is aborted, after moving the canary back to its
+// old volumes for Rollback
+func (r *VolumeResizeReconciler) failCanary(ctx context.Context, vr *storagev1alpha1.VolumeResize) (ctrl.Result, error) {
+	canary := vr.Status.Canary
+	reason := fmt.Sprintf("Migration aborted after canary replica %d %s", canary.Replica, canary.Message)
+
+	if vr.Spec.Canary.OnFailure == CanaryOnFailureRollback {
+		if err := r.rollbackCanary(ctx, vr); err != nil {
+			return r.setFailed(ctx, vr, fmt.Sprintf("failed to roll back canary replica %d: %v", canary.Replica, err))
+		}
+		reason += ", it was rolled back to its old volumes"
+	}
+	return r.abortMigration(ctx, vr, reason)
+}
+
+// rollbackCanary puts the canary back on the volumes it had before the migration. The StatefulSet is
+// deleted again to stop the pod, the abort recreates it with the templates of the remaining migrated volumes.
+func (r *VolumeResizeReconciler) rollbackCanary(ctx context.Context, vr *storagev1alpha1.VolumeResize) error {
+	log := logf.FromContext(ctx)
+	replica := vr.Status.Canary.Replica
+
+	if err := r.ensureSTSDeleted(ctx, vr); err != nil {
+		return err
+	}
+	if err := deletePod(ctx, r.Client, vr.Namespace, vr.Spec.StatefulSetName, replica); err != nil {
+		return err
+	}
+	podName := getPodName(vr.Spec.StatefulSetName, replica)
+	if err := waitForPodTermination(ctx, r.Client, vr.Namespace, podName, time.Minute*2); err != nil {
+		return err
+	}
+
+	for _, vs := range vr.Status.VolumeStatuses {
+		if vs.Replica != replica || vs.Phase != VolumeStatusCompleted {
+			continue
+		}
+		if err := restoreOldPVC(ctx, r.Client, vr.Namespace, vs); err != nil {
+			return err
+		}
+		log.Info("Rolled back canary volume", "pvc", vs.OldPVCName, "pv", vs.OldPVName)
+
+		// Persist each volume, the templates of the recreated StatefulSet follow the completed ones
+		r.updateVolumeStatus(vr, vs.VolumeName, replica, VolumeStatusPending, "Rolled back after the canary failed")
+		if err := r.Status().Update(ctx, vr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+func canaryTestPod(ready bool, since time.Time, restarts int32) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sts-0", Namespace: "default"},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: status, LastTransitionTime: metav1.NewTime(since)}},
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "app", RestartCount: restarts}},
+		},
+	}
+}
+
+func TestEvaluateCanary(t *testing.T) {
+	start := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	spec := &storagev1alpha1.CanarySpec{SoakDuration: metav1.Duration{Duration: 30 * time.Minute}, MaxRestarts: 1}
+	soaking := func() *storagev1alpha1.CanaryStatus {
+		return &storagev1alpha1.CanaryStatus{Phase: CanaryPhaseSoaking, SoakStartTime: &metav1.Time{Time: start}}
+	}
+
+	// Not ready yet, still within the soak
+	canary := soaking()
+	passed, failure := evaluateCanary(spec, canary, canaryTestPod(false, start, 0), start.Add(time.Minute))
+	assert.False(t, passed)
+	assert.Empty(t, failure)
+	assert.Nil(t, canary.ReadyTime)
+
+	// Ready, the soak counts from the Ready transition
+	readyAt := start.Add(2 * time.Minute)
+	passed, failure = evaluateCanary(spec, canary, canaryTestPod(true, readyAt, 1), start.Add(10*time.Minute))
+	assert.False(t, passed)
+	assert.Empty(t, failure)
+	require.NotNil(t, canary.ReadyTime)
+	assert.Equal(t, readyAt, canary.ReadyTime.Time)
+	assert.Equal(t, int32(1), canary.Restarts)
+
+	passed, failure = evaluateCanary(spec, canary, canaryTestPod(true, readyAt, 1), readyAt.Add(30*time.Minute))
+	assert.True(t, passed)
+	assert.Empty(t, failure)
+
+	// Too many restarts
+	_, failure = evaluateCanary(spec, soaking(), canaryTestPod(true, readyAt, 2), start.Add(10*time.Minute))
+	assert.Equal(t, "restarted 2 times, more than the 1 allowed", failure)
+
+	// Never ready
+	_, failure = evaluateCanary(spec, soaking(), canaryTestPod(false, start, 0), start.Add(31*time.Minute))
+	assert.Equal(t, "did not become ready within 30m0s", failure)
+
+	// Unready, or ready again since the last check, after having been ready
+	canary = soaking()
+	canary.ReadyTime = &metav1.Time{Time: readyAt}
+	_, failure = evaluateCanary(spec, canary, canaryTestPod(false, readyAt.Add(time.Minute), 0), readyAt.Add(5*time.Minute))
+	assert.Equal(t, "went unready", failure)
+	_, failure = evaluateCanary(spec, canary, canaryTestPod(true, readyAt.Add(time.Minute), 0), readyAt.Add(5*time.Minute))
+	assert.Equal(t, "went unready", failure)
+	_, failure = evaluateCanary(spec, canary, nil, readyAt.Add(5*time.Minute))
+	assert.Equal(t, "went unready", failure)
+}
+
+func TestCanaryStartsAlone(t *testing.T) {
+	vr := controlsTestVR()
+	for i := range vr.Status.VolumeStatuses {
+		vr.Status.VolumeStatuses[i].Phase = VolumeStatusPending
+	}
+	maxUnavailable := intstr.FromInt32(3)
+	vr.Spec.Strategy = &storagev1alpha1.MigrationStrategy{MaxUnavailable: &maxUnavailable}
+	vr.Spec.Canary = &storagev1alpha1.CanarySpec{Replica: ptrInt32(1)}
+	r, _ := newControlsTestReconciler(t, vr, controlsTestSTS())
+	require.NoError(t, policyv1.AddToScheme(r.Scheme))
+	ctx := context.Background()
+
+	_, err := r.startNextBatch(ctx, vr)
+	require.NoError(t, err)
+	assert.Equal(t, []int32{1}, vr.Status.InFlightReplicas)
+	require.NotNil(t, vr.Status.Canary)
+	assert.Equal(t, CanaryPhaseMigrating, vr.Status.Canary.Phase)
+
+	// Once back online the canary soaks, and holds the next batch
+	vr.Status.InFlightReplicas = nil
+	vr.Status.VolumeStatuses[1].Phase = VolumeStatusCompleted
+	startCanarySoak(vr, []int32{1})
+	assert.Equal(t, CanaryPhaseSoaking, vr.Status.Canary.Phase)
+
+	result, err := r.startNextBatch(ctx, vr)
+	require.NoError(t, err)
+	assert.Empty(t, vr.Status.InFlightReplicas)
+	assert.Equal(t, canaryPollInterval, result.RequeueAfter)
+	assert.Equal(t, "Waiting for canary replica 1 to become ready", vr.Status.Message)
+
+	// The other replicas follow together once it passed
+	vr.Status.Canary.Phase = CanaryPhasePassed
+	_, err = r.startNextBatch(ctx, vr)
+	require.NoError(t, err)
+	assert.Equal(t, []int32{0, 2}, vr.Status.InFlightReplicas)
+}
+
+func TestCanaryFailureAborts(t *testing.T) {
+	vr := controlsTestVR()
+	vr.Spec.Canary = &storagev1alpha1.CanarySpec{MaxRestarts: 2}
+	vr.Status.Canary = &storagev1alpha1.CanaryStatus{Replica: 0, Phase: CanaryPhaseSoaking, SoakStartTime: &metav1.Time{Time: time.Now()}}
+	r, c := newControlsTestReconciler(t, vr, canaryTestPod(true, time.Now(), 3))
+	ctx := context.Background()
+
+	_, err := r.startNextBatch(ctx, vr)
+	require.NoError(t, err)
+
+	updated := &storagev1alpha1.VolumeResize{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-resize"}, updated))
+	assert.Equal(t, PhaseAborted, updated.Status.Phase)
+	assert.Equal(t, CanaryPhaseFailed, updated.Status.Canary.Phase)
+	assert.Equal(t, "Migration aborted after canary replica 0 restarted 3 times, more than the 2 allowed, 1 of 3 volumes were migrated",
+		updated.Status.Message)
+}
+
+func TestCanaryFailureRollsBack(t *testing.T) {
+	vr := controlsTestVR()
+	vr.Spec.Canary = &storagev1alpha1.CanarySpec{OnFailure: CanaryOnFailureRollback}
+	vr.Status.VolumeStatuses[0].OldPVCName = "data-test-sts-0"
+	vr.Status.VolumeStatuses[0].OldPVName = "old-pv"
+	vr.Status.VolumeStatuses[0].NewPVName = "new-pv"
+	vr.Status.Canary = &storagev1alpha1.CanaryStatus{Replica: 0, Phase: CanaryPhaseFailed, Message: "went unready"}
+
+	oldPV := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "old-pv"},
+		Spec: corev1.PersistentVolumeSpec{
+			Capacity:         corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+			ClaimRef:         &corev1.ObjectReference{Namespace: "default", Name: "data-test-sts-0", UID: "old-uid"},
+			StorageClassName: "standard",
+		},
+	}
+	newPVC := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data-test-sts-0", Namespace: "default"},
+		Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "new-pv"},
+	}
+	sts := controlsTestSTS()
+	r, c := newControlsTestReconciler(t, vr, sts, oldPV, newPVC, canaryTestPod(false, time.Now(), 0))
+	ctx := context.Background()
+
+	_, err := r.startNextBatch(ctx, vr)
+	require.NoError(t, err)
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "data-test-sts-0"}, pvc))
+	assert.Equal(t, "old-pv", pvc.Spec.VolumeName)
+
+	updated := &storagev1alpha1.VolumeResize{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-resize"}, updated))
+	assert.Equal(t, PhaseAborted, updated.Status.Phase)
+	assert.Equal(t, VolumeStatusPending, updated.Status.VolumeStatuses[0].Phase)
+	assert.Contains(t, updated.Status.Message, "it was rolled back to its old volumes, 0 of 3 volumes were migrated")
+
+	// The StatefulSet is back with the original size, no volume is migrated anymore
+	recreated := &appsv1.StatefulSet{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-sts"}, recreated))
+	size := recreated.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests[corev1.ResourceStorage]
+	assert.Equal(t, "1Gi", size.String())
+}
@@ -22,8 +22,10 @@ const (
 	PhaseValidating = "Validating"
 	PhaseSyncing    = "Syncing"
 	PhaseReplacing  = "Replacing"
+	PhasePaused     = "Paused"
 	PhaseCompleted  = "Completed"
 	PhaseFailed     = "Failed"
+	PhaseAborted    = "Aborted"
 )
 
 // Volume-level phase constants
@@ -41,6 +43,12 @@ const (
 	ConditionTypeReady       = "Ready"
 	ConditionTypeValidated   = "Validated"
 	ConditionTypeProgressing = "Progressing"
+	// ConditionTypeSpecDrift is True when the StatefulSet changed since it was backed up
+	ConditionTypeSpecDrift = "SpecDrift"
+	// ConditionTypeAwaitingApproval is True while the next replica waits for spec.approval
+	ConditionTypeAwaitingApproval = "AwaitingApproval"
+	// ConditionTypePartiallyResized is True while replicas left out of spec.replicas still have the old size
+	ConditionTypePartiallyResized = "PartiallyResized"
 )
 
 // Annotation keys
@@ -49,11 +57,34 @@ const (
 	AnnotationManagedBy  = "storage.maurice.fr/managed-by"
 	AnnotationSTSDeleted = "storage.maurice.fr/sts-deleted"
 	AnnotationSTSBackup  = "storage.maurice.fr/sts-backup-cm"
+	// AnnotationForceDelete skips restoring the StatefulSet and PVCs when a VolumeResize is deleted
+	AnnotationForceDelete = "storage.maurice.fr/force-delete"
+	// AnnotationUnlockStatefulSet lets changes to a StatefulSet through the lock webhook while it is migrated
+	AnnotationUnlockStatefulSet = "storage.maurice.fr/unlock"
+	// AnnotationApprovedReplica approves the replica with this ordinal to start when spec.approval waits for it
+	AnnotationApprovedReplica = "storage.maurice.fr/approved-replica"
+	// AnnotationHookStartTime ties a hook Job to the run recorded in status.hooks, a Job of an earlier run is replaced
+	AnnotationHookStartTime = "storage.maurice.fr/hook-start-time"
 )
 
-// ConfigMap key for STS backup
+// Well-known Kubernetes annotation keys
 const (
-	ConfigMapKeySTSSpec = "statefulset.json"
+	// AnnotationSelectedNode tells the provisioner which node a WaitForFirstConsumer claim is scheduled to
+	AnnotationSelectedNode = "volume.kubernetes.io/selected-node"
+)
+
+// Labels and annotations GitOps tools put on the objects they deploy
+const (
+	// AnnotationArgoCDTrackingID is set by Argo CD with annotation-based resource tracking
+	AnnotationArgoCDTrackingID = "argocd.argoproj.io/tracking-id"
+	// LabelArgoCDInstance is set by Argo CD with the default label-based resource tracking
+	LabelArgoCDInstance             = "app.kubernetes.io/instance"
+	LabelFluxKustomizationName      = "kustomize.toolkit.fluxcd.io/name"
+	LabelFluxKustomizationNamespace = "kustomize.toolkit.fluxcd.io/namespace"
+	LabelFluxHelmReleaseName        = "helm.toolkit.fluxcd.io/name"
+	LabelFluxHelmReleaseNamespace   = "helm.toolkit.fluxcd.io/namespace"
+	// DefaultArgoCDNamespace is where Argo CD Applications live unless spec.gitOps says otherwise
+	DefaultArgoCDNamespace = "argocd"
 )
 
 // Label keys
@@ -61,6 +92,11 @@ const (
 	LabelMigrationName = "storage.maurice.fr/migration-name"
 	LabelReplica       = "storage.maurice.fr/replica"
 	LabelVolumeName    = "storage.maurice.fr/volume-name"
+	LabelTransferRole  = "storage.maurice.fr/transfer-role"
+	// LabelHealthGate names the health gate a Job was run for
+	LabelHealthGate = "storage.maurice.fr/health-gate"
+	// LabelHook names the hook a Job was run for
+	LabelHook = "storage.maurice.fr/hook"
 )
 
 // Finalizer name
@@ -68,6 +104,122 @@ const (
 	FinalizerName = "storage.maurice.fr/finalizer"
 )
 
+// Block copy modes for volumeMode Block volumes
+const (
+	BlockCopyModeFilesystem = "Filesystem"
+	BlockCopyModeLength     = "Length"
+)
+
+// Migrator modes passed to the migrator image
+const (
+	MigrationModeFilesystem = "filesystem"
+	MigrationModeBlock      = "block"
+	MigrationModeSend       = "send"
+	MigrationModeReceive    = "receive"
+)
+
+// Transfer modes
+const (
+	// TransferModeLocal copies the data in a single pod mounting both volumes
+	TransferModeLocal = "Local"
+	// TransferModeNetwork streams the data from a sender pod to a receiver pod over TCP
+	TransferModeNetwork = "Network"
+)
+
+// Drift policies
+const (
+	// DriftPolicyMerge folds pod template changes into the backup and fails on other changes
+	DriftPolicyMerge = "Merge"
+	// DriftPolicyFail fails the migration on any change
+	DriftPolicyFail = "Fail"
+	// DriftPolicyIgnore recreates the StatefulSet from the backup as it was taken
+	DriftPolicyIgnore = "Ignore"
+)
+
+// Approval modes
+const (
+	// ApprovalPerReplica waits for approval before every batch after the first one
+	ApprovalPerReplica = "PerReplica"
+	// ApprovalAfterFirst waits for approval once, after the first batch
+	ApprovalAfterFirst = "AfterFirst"
+	// ApprovalNone never waits
+	ApprovalNone = "None"
+)
+
+// Canary failure actions
+const (
+	// CanaryOnFailureAbort stops the migration and keeps the canary on its new volumes
+	CanaryOnFailureAbort = "Abort"
+	// CanaryOnFailureRollback moves the canary back to its old volumes, then stops the migration
+	CanaryOnFailureRollback = "Rollback"
+)
+
+// Canary phases
+const (
+	CanaryPhaseMigrating = "Migrating"
+	CanaryPhaseSoaking   = "Soaking"
+	CanaryPhasePassed    = "Passed"
+	CanaryPhaseFailed    = "Failed"
+)
+
+// Hook stages
+const (
+	HookStagePreStop   = "PreStop"
+	HookStagePostCopy  = "PostCopy"
+	HookStagePostStart = "PostStart"
+)
+
+// Hook phases
+const (
+	HookPhaseRunning   = "Running"
+	HookPhaseSucceeded = "Succeeded"
+	HookPhaseFailed    = "Failed"
+	// HookPhaseIgnored is a failure the failurePolicy of the hook lets through
+	HookPhaseIgnored = "Ignored"
+)
+
+// Hook failure policies
+const (
+	HookFailurePolicyFail   = "Fail"
+	HookFailurePolicyIgnore = "Ignore"
+)
+
+// Parent pause hook types
+const (
+	// ParentPauseTypeAnnotation sets an annotation on the object owning the StatefulSet
+	ParentPauseTypeAnnotation = "Annotation"
+	// ParentPauseTypeScaleDown scales the operator Deployment to zero
+	ParentPauseTypeScaleDown = "ScaleDown"
+	// ParentPauseTypeNone migrates an owned StatefulSet without pausing its owner
+	ParentPauseTypeNone = "None"
+)
+
+// Migration strategy types
+const (
+	// StrategyTypeRolling migrates maxUnavailable replicas at a time while the others keep serving
+	StrategyTypeRolling = "Rolling"
+	// StrategyTypeOffline takes the StatefulSet down once and migrates every replica in parallel
+	StrategyTypeOffline = "Offline"
+)
+
+// Replica orders
+const (
+	OrderAscending  = "Ascending"
+	OrderDescending = "Descending"
+	// OrderExplicit follows spec.order.replicas
+	OrderExplicit = "Explicit"
+	// OrderLeaderLast migrates the followers first and the replica found by spec.order.leader last
+	OrderLeaderLast = "LeaderLast"
+)
+
+// Network transfer roles and settings
+const (
+	TransferRoleSender     = "sender"
+	TransferRoleReceiver   = "receiver"
+	SecretKeyTransferToken = "token"
+	DefaultTransferPort    = int32(9000)
+)
+
 // Default values
 const (
 	DefaultMigratorImage = "mauricethomas/migcontroller-migrator:latest"
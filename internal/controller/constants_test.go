@@ -29,8 +29,10 @@ func TestPhaseConstants(t *testing.T) {
 		PhaseValidating,
 		PhaseSyncing,
 		PhaseReplacing,
+		PhasePaused,
 		PhaseCompleted,
 		PhaseFailed,
+		PhaseAborted,
 	}
 
 	for _, phase := range phases {
@@ -0,0 +1,227 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+// cleanupVolumeCopy deletes the migrator, sender and receiver pods of a volume, and optionally its temp PVC
+func (r *VolumeResizeReconciler) cleanupVolumeCopy(ctx context.Context, vr *storagev1alpha1.VolumeResize, volName string, replica int32, deleteTempPVC bool) error {
+	if err := cleanupMigratorPod(ctx, r.Client, getMigratorPodName(vr.Name, volName, replica), vr.Namespace); err != nil {
+		return err
+	}
+	if err := cleanupNetworkTransfer(ctx, r.Client, vr.Namespace, vr.Name, volName, replica); err != nil {
+		return err
+	}
+	if !deleteTempPVC {
+		return nil
+	}
+
+	tempPVC := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getTempPVCName(volName, vr.Spec.StatefulSetName, replica),
+			Namespace: vr.Namespace,
+		},
+	}
+	if err := r.Delete(ctx, tempPVC); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete temp PVC %s: %w", tempPVC.Name, err)
+	}
+	return nil
+}
+
+// resetUnfinishedVolumes cleans up every volume that started but did not complete and moves it back to Pending
+func (r *VolumeResizeReconciler) resetUnfinishedVolumes(ctx context.Context, vr *storagev1alpha1.VolumeResize, message string) error {
+	for i := range vr.Status.VolumeStatuses {
+		vs := &vr.Status.VolumeStatuses[i]
+		if vs.Phase == VolumeStatusCompleted || vs.Phase == VolumeStatusPending {
+			continue
+		}
+
+		if err := r.cleanupVolumeCopy(ctx, vr, vs.VolumeName, vs.Replica, true); err != nil {
+			return err
+		}
+		vs.Phase = VolumeStatusPending
+		vs.Message = message
+	}
+	return nil
+}
+
+// handlePaused holds the migration until spec.paused is cleared
+func (r *VolumeResizeReconciler) handlePaused(ctx context.Context, vr *storagev1alpha1.VolumeResize) (ctrl.Result, error) {
+	if vr.Spec.Paused {
+		return ctrl.Result{}, nil
+	}
+
+	logf.FromContext(ctx).Info("Resuming migration")
+	vr.Status.Phase = PhaseSyncing
+	vr.Status.Message = "Migration resumed"
+	if err := r.Status().Update(ctx, vr); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// handleAbort drops the in-flight copies and brings the StatefulSet back.
+// Replicas that were not migrated come back on their original volumes.
+func (r *VolumeResizeReconciler) handleAbort(ctx context.Context, vr *storagev1alpha1.VolumeResize) (ctrl.Result, error) {
+	return r.abortMigration(ctx, vr, "Migration aborted")
+}
+
+// abortMigration ends the migration as Aborted, the reason starts the status message
+func (r *VolumeResizeReconciler) abortMigration(ctx context.Context, vr *storagev1alpha1.VolumeResize, reason string) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+	log.Info("Aborting migration", "phase", vr.Status.Phase, "reason", reason)
+
+	// A replica that lost its PVC mid-swap would come back on an empty volume provisioned by the StatefulSet
+	if err := r.rebindMissingPVCs(ctx, vr); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.resetUnfinishedVolumes(ctx, vr, "Aborted"); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Persist before recreating the STS so its templates only get the sizes of migrated volumes
+	if err := r.Status().Update(ctx, vr); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.restoreSTS(ctx, vr); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.resumeParent(ctx, vr); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.resumeGitOps(ctx, vr); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.deleteMigrationJobs(ctx, vr, LabelHook); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	migrated := 0
+	for _, vs := range vr.Status.VolumeStatuses {
+		if vs.Phase == VolumeStatusCompleted {
+			migrated++
+		}
+	}
+
+	now := metav1.Now()
+	vr.Status.Phase = PhaseAborted
+	vr.Status.CompletionTime = &now
+	vr.Status.InFlightReplicas = nil
+	vr.Status.CurrentReplica = nil
+	vr.Status.CurrentVolume = ""
+	vr.Status.Message = fmt.Sprintf("%s, %d of %d volumes were migrated", reason, migrated, len(vr.Status.VolumeStatuses))
+	if err := r.Status().Update(ctx, vr); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// handleRetry moves a Failed migration back into Syncing, from the replicas that were not migrated yet.
+// A retryGeneration change on a migration that did not fail is only acknowledged.
+func (r *VolumeResizeReconciler) handleRetry(ctx context.Context, vr *storagev1alpha1.VolumeResize) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	vr.Status.ObservedRetryGeneration = vr.Spec.RetryGeneration
+	if vr.Status.Phase != PhaseFailed {
+		if err := r.Status().Update(ctx, vr); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	log.Info("Retrying failed migration", "retryGeneration", vr.Spec.RetryGeneration)
+
+	// The copies restart from scratch on fresh temp PVCs, from an original PVC bound again if it was
+	// lost mid-swap
+	if err := r.rebindMissingPVCs(ctx, vr); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.resetUnfinishedVolumes(ctx, vr, "Retrying"); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	vr.Status.CompletionTime = nil
+	vr.Status.InFlightReplicas = nil
+	vr.Status.CurrentReplica = nil
+	if len(vr.Status.VolumeStatuses) == 0 {
+		// Failed during validation, start over
+		vr.Status.Phase = PhasePending
+		vr.Status.Message = "Retrying validation"
+	} else {
+		vr.Status.Phase = PhaseSyncing
+		vr.Status.Message = fmt.Sprintf("Retrying replicas %s", FormatReplicas(pendingReplicas(vr.Status.VolumeStatuses)))
+	}
+
+	if err := r.Status().Update(ctx, vr); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// rebindMissingPVCs recreates the original PVC of every replica that lost it mid-swap. Synced volumes
+// point at the copied data and are Completed from then on, so they are neither reported as not migrated
+// nor copied again from the new PV. Anything earlier goes back to the retained old PV.
+func (r *VolumeResizeReconciler) rebindMissingPVCs(ctx context.Context, vr *storagev1alpha1.VolumeResize) error {
+	log := logf.FromContext(ctx)
+
+	for i := range vr.Status.VolumeStatuses {
+		vs := &vr.Status.VolumeStatuses[i]
+		if vs.OldPVCName == "" {
+			continue
+		}
+		if _, err := getPVC(ctx, r.Client, vr.Namespace, vs.OldPVCName); err == nil {
+			continue
+		} else if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get PVC %s: %w", vs.OldPVCName, err)
+		}
+
+		pvName := vs.OldPVName
+		swapped := vs.Phase == VolumeStatusSynced && vs.NewPVName != ""
+		if swapped {
+			pvName = vs.NewPVName
+		}
+		if pvName == "" {
+			log.Info("PVC is missing and no PV is recorded for it, leaving it to the StatefulSet", "pvc", vs.OldPVCName)
+			continue
+		}
+
+		pv, err := getPV(ctx, r.Client, pvName)
+		if err != nil {
+			return fmt.Errorf("failed to get PV %s for PVC %s: %w", pvName, vs.OldPVCName, err)
+		}
+		if err := bindPVCToPV(ctx, r.Client, vr.Namespace, vs.OldPVCName, pv); err != nil {
+			return err
+		}
+		log.Info("Rebound PVC", "pvc", vs.OldPVCName, "pv", pvName)
+		if swapped {
+			vs.Phase = VolumeStatusCompleted
+			vs.Message = "PVC replaced"
+		}
+	}
+	return nil
+}
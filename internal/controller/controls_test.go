@@ -0,0 +1,401 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+func newControlsTestReconciler(t *testing.T, objs ...client.Object) (*VolumeResizeReconciler, client.Client) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, storagev1alpha1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, batchv1.AddToScheme(scheme))
+
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&storagev1alpha1.VolumeResize{}).
+		Build()
+	return &VolumeResizeReconciler{Client: c, Scheme: scheme}, c
+}
+
+func controlsTestVR() *storagev1alpha1.VolumeResize {
+	return &storagev1alpha1.VolumeResize{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-resize",
+			Namespace:  "default",
+			Finalizers: []string{FinalizerName},
+		},
+		Spec: storagev1alpha1.VolumeResizeSpec{
+			StatefulSetName: "test-sts",
+			Volumes:         []storagev1alpha1.VolumeResizeTarget{{Name: "data", NewSize: resource.MustParse("500Mi")}},
+		},
+		Status: storagev1alpha1.VolumeResizeStatus{
+			Phase: PhaseSyncing,
+			VolumeStatuses: []storagev1alpha1.VolumeStatus{
+				{VolumeName: "data", Replica: 0, Phase: VolumeStatusCompleted},
+				{VolumeName: "data", Replica: 1, Phase: VolumeStatusFailed},
+				{VolumeName: "data", Replica: 2, Phase: VolumeStatusPending},
+			},
+		},
+	}
+}
+
+func controlsTestSTS() *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sts", Namespace: "default"},
+		Spec: appsv1.StatefulSetSpec{
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "data"},
+					Spec: corev1.PersistentVolumeClaimSpec{
+						Resources: corev1.VolumeResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestPauseHoldsBetweenBatches(t *testing.T) {
+	vr := controlsTestVR()
+	vr.Spec.Paused = true
+	r, c := newControlsTestReconciler(t, vr)
+	ctx := context.Background()
+
+	_, err := r.startNextBatch(ctx, vr)
+	require.NoError(t, err)
+
+	updated := &storagev1alpha1.VolumeResize{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-resize"}, updated))
+	assert.Equal(t, PhasePaused, updated.Status.Phase)
+	assert.Empty(t, updated.Status.InFlightReplicas)
+
+	// Resuming moves back to Syncing
+	updated.Spec.Paused = false
+	_, err = r.handlePaused(ctx, updated)
+	require.NoError(t, err)
+	assert.Equal(t, PhaseSyncing, updated.Status.Phase)
+}
+
+func TestRetryResumesFailedMigration(t *testing.T) {
+	vr := controlsTestVR()
+	vr.Status.Phase = PhaseFailed
+	vr.Spec.RetryGeneration = 1
+
+	failedPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: getMigratorPodName("test-resize", "data", 1), Namespace: "default"},
+		Status:     corev1.PodStatus{Phase: corev1.PodFailed},
+	}
+	r, c := newControlsTestReconciler(t, vr, failedPod)
+	ctx := context.Background()
+
+	_, err := r.Reconcile(ctx, ctrlRequest("test-resize"))
+	require.NoError(t, err)
+
+	updated := &storagev1alpha1.VolumeResize{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-resize"}, updated))
+	assert.Equal(t, PhaseSyncing, updated.Status.Phase)
+	assert.Equal(t, int64(1), updated.Status.ObservedRetryGeneration)
+	assert.Equal(t, VolumeStatusCompleted, updated.Status.VolumeStatuses[0].Phase)
+	assert.Equal(t, VolumeStatusPending, updated.Status.VolumeStatuses[1].Phase)
+
+	err = c.Get(ctx, types.NamespacedName{Namespace: "default", Name: failedPod.Name}, &corev1.Pod{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestRetryIgnoredWhenNotFailed(t *testing.T) {
+	vr := controlsTestVR()
+	vr.Spec.RetryGeneration = 3
+	r, c := newControlsTestReconciler(t, vr)
+	ctx := context.Background()
+
+	_, err := r.handleRetry(ctx, vr)
+	require.NoError(t, err)
+
+	updated := &storagev1alpha1.VolumeResize{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-resize"}, updated))
+	assert.Equal(t, PhaseSyncing, updated.Status.Phase)
+	assert.Equal(t, int64(3), updated.Status.ObservedRetryGeneration)
+	assert.Equal(t, VolumeStatusFailed, updated.Status.VolumeStatuses[1].Phase)
+}
+
+func TestAbortRestoresStatefulSet(t *testing.T) {
+	vr := controlsTestVR()
+	vr.Spec.Abort = true
+	vr.Annotations = map[string]string{AnnotationSTSDeleted: "true"}
+	vr.Status.VolumeStatuses[1].Phase = VolumeStatusSyncing
+	vr.Status.InFlightReplicas = []int32{1}
+
+	sts := controlsTestSTS()
+	tempPVC := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: getTempPVCName("data", "test-sts", 1), Namespace: "default"},
+	}
+
+	r, c := newControlsTestReconciler(t, vr, tempPVC)
+	ctx := context.Background()
+	require.NoError(t, backupSTSToConfigMap(ctx, c, vr, sts))
+
+	_, err := r.Reconcile(ctx, ctrlRequest("test-resize"))
+	require.NoError(t, err)
+
+	updated := &storagev1alpha1.VolumeResize{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-resize"}, updated))
+	assert.Equal(t, PhaseAborted, updated.Status.Phase)
+	assert.Contains(t, updated.Status.Message, "1 of 3")
+	assert.Empty(t, updated.Status.InFlightReplicas)
+	assert.Equal(t, VolumeStatusPending, updated.Status.VolumeStatuses[1].Phase)
+	assert.NotContains(t, updated.Annotations, AnnotationSTSDeleted)
+
+	restored := &appsv1.StatefulSet{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-sts"}, restored))
+	size := restored.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests[corev1.ResourceStorage]
+	assert.Equal(t, "500Mi", size.String())
+
+	err = c.Get(ctx, types.NamespacedName{Namespace: "default", Name: tempPVC.Name}, &corev1.PersistentVolumeClaim{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestAbortRebindsMissingPVC(t *testing.T) {
+	vr := controlsTestVR()
+	vr.Spec.Abort = true
+	vr.Annotations = map[string]string{AnnotationSTSDeleted: "true"}
+	// The swap of replica 1 failed after its original PVC was deleted
+	vr.Status.VolumeStatuses[1] = storagev1alpha1.VolumeStatus{
+		VolumeName: "data", Replica: 1, Phase: VolumeStatusFailed, OldPVCName: "data-test-sts-1", OldPVName: "pv-old-1",
+	}
+	vr.Status.InFlightReplicas = []int32{1}
+	oldPV := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-old-1"},
+		Spec: corev1.PersistentVolumeSpec{
+			Capacity:    corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			ClaimRef:    &corev1.ObjectReference{Namespace: "default", Name: "data-test-sts-1"},
+		},
+	}
+
+	r, c := newControlsTestReconciler(t, vr, oldPV)
+	ctx := context.Background()
+	require.NoError(t, backupSTSToConfigMap(ctx, c, vr, controlsTestSTS()))
+
+	_, err := r.Reconcile(ctx, ctrlRequest("test-resize"))
+	require.NoError(t, err)
+
+	// The StatefulSet finds the PVC bound to the old data instead of provisioning an empty one
+	pvc := &corev1.PersistentVolumeClaim{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "data-test-sts-1"}, pvc))
+	assert.Equal(t, "pv-old-1", pvc.Spec.VolumeName)
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-sts"}, &appsv1.StatefulSet{}))
+
+	updated := &storagev1alpha1.VolumeResize{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-resize"}, updated))
+	assert.Equal(t, PhaseAborted, updated.Status.Phase)
+}
+
+func TestAbortAndRetryKeepSwappedVolume(t *testing.T) {
+	newPV := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-new-1"},
+		Spec: corev1.PersistentVolumeSpec{
+			Capacity:    corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("500Mi")},
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+		},
+	}
+	// The copy of replica 1 is done and its original PVC was deleted, the swap did not finish
+	swapped := storagev1alpha1.VolumeStatus{
+		VolumeName: "data", Replica: 1, Phase: VolumeStatusSynced,
+		OldPVCName: "data-test-sts-1", OldPVName: "pv-old-1", NewPVName: "pv-new-1",
+	}
+
+	vr := controlsTestVR()
+	vr.Spec.Abort = true
+	vr.Annotations = map[string]string{AnnotationSTSDeleted: "true"}
+	vr.Status.VolumeStatuses[1] = swapped
+	vr.Status.InFlightReplicas = []int32{1}
+	r, c := newControlsTestReconciler(t, vr, newPV.DeepCopy())
+	ctx := context.Background()
+	require.NoError(t, backupSTSToConfigMap(ctx, c, vr, controlsTestSTS()))
+
+	_, err := r.Reconcile(ctx, ctrlRequest("test-resize"))
+	require.NoError(t, err)
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "data-test-sts-1"}, pvc))
+	assert.Equal(t, "pv-new-1", pvc.Spec.VolumeName)
+	updated := &storagev1alpha1.VolumeResize{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-resize"}, updated))
+	assert.Equal(t, PhaseAborted, updated.Status.Phase)
+	assert.Equal(t, VolumeStatusCompleted, updated.Status.VolumeStatuses[1].Phase)
+	assert.Equal(t, "pv-old-1", updated.Status.VolumeStatuses[1].OldPVName, "the old PV must stay tracked for rollback")
+	assert.Contains(t, updated.Status.Message, "2 of 3 volumes were migrated")
+
+	// A retry does not copy the new volume again
+	vr = controlsTestVR()
+	vr.Status.Phase = PhaseFailed
+	vr.Spec.RetryGeneration = 1
+	vr.Status.VolumeStatuses[1] = swapped
+	r, c = newControlsTestReconciler(t, vr, newPV.DeepCopy())
+
+	_, err = r.handleRetry(ctx, vr)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "data-test-sts-1"}, pvc))
+	assert.Equal(t, "pv-new-1", pvc.Spec.VolumeName)
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-resize"}, updated))
+	assert.Equal(t, VolumeStatusCompleted, updated.Status.VolumeStatuses[1].Phase)
+	assert.Equal(t, []int32{2}, pendingReplicas(updated.Status.VolumeStatuses))
+}
+
+func TestDeletionRestoresStatefulSetAndRebindsPVCs(t *testing.T) {
+	now := metav1.Now()
+	vr := controlsTestVR()
+	vr.DeletionTimestamp = &now
+	vr.Annotations = map[string]string{AnnotationSTSDeleted: "true"}
+	vr.Status.VolumeStatuses = []storagev1alpha1.VolumeStatus{
+		{VolumeName: "data", Replica: 0, Phase: VolumeStatusCompleted, OldPVCName: "data-test-sts-0", OldPVName: "pv-old-0"},
+		{VolumeName: "data", Replica: 1, Phase: VolumeStatusSynced, OldPVCName: "data-test-sts-1", OldPVName: "pv-old-1", NewPVName: "pv-new-1"},
+		{VolumeName: "data", Replica: 2, Phase: VolumeStatusSyncing, OldPVCName: "data-test-sts-2", OldPVName: "pv-old-2"},
+	}
+
+	testPV := func(name string) *corev1.PersistentVolume {
+		return &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: corev1.PersistentVolumeSpec{
+				Capacity:    corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				ClaimRef:    &corev1.ObjectReference{Namespace: "default", Name: "gone"},
+			},
+		}
+	}
+	existingPVC := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data-test-sts-0", Namespace: "default"},
+		Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "pv-completed-0"},
+	}
+
+	r, c := newControlsTestReconciler(t, vr, existingPVC, testPV("pv-new-1"), testPV("pv-old-1"), testPV("pv-old-2"))
+	ctx := context.Background()
+	require.NoError(t, backupSTSToConfigMap(ctx, c, vr, controlsTestSTS()))
+
+	_, err := r.Reconcile(ctx, ctrlRequest("test-resize"))
+	require.NoError(t, err)
+
+	// Synced replicas keep the copied data, the others go back to their old PV
+	expected := map[string]string{
+		"data-test-sts-0": "pv-completed-0",
+		"data-test-sts-1": "pv-new-1",
+		"data-test-sts-2": "pv-old-2",
+	}
+	for pvcName, pvName := range expected {
+		pvc := &corev1.PersistentVolumeClaim{}
+		require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: pvcName}, pvc))
+		assert.Equal(t, pvName, pvc.Spec.VolumeName, pvcName)
+	}
+
+	pv := &corev1.PersistentVolume{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "pv-new-1"}, pv))
+	assert.Nil(t, pv.Spec.ClaimRef)
+
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-sts"}, &appsv1.StatefulSet{}))
+
+	// The StatefulSet is back, its backup is not an orphan to report
+	err = c.Get(ctx, types.NamespacedName{Namespace: "default", Name: getSTSBackupConfigMapName("test-resize")}, &corev1.ConfigMap{})
+	assert.True(t, apierrors.IsNotFound(err))
+
+	err = c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-resize"}, &storagev1alpha1.VolumeResize{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestDeletionOfCompletedMigrationRemovesBackup(t *testing.T) {
+	now := metav1.Now()
+	vr := controlsTestVR()
+	vr.DeletionTimestamp = &now
+	vr.Status.Phase = PhaseCompleted
+
+	r, c := newControlsTestReconciler(t, vr)
+	ctx := context.Background()
+	require.NoError(t, backupSTSToConfigMap(ctx, c, vr, controlsTestSTS()))
+
+	// The StatefulSet was deleted on purpose after the migration, it must not come back from the backup
+	_, err := r.Reconcile(ctx, ctrlRequest("test-resize"))
+	require.NoError(t, err)
+
+	cms := &corev1.ConfigMapList{}
+	require.NoError(t, c.List(ctx, cms))
+	assert.Empty(t, cms.Items)
+	err = c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-sts"}, &appsv1.StatefulSet{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestForcedDeletionSkipsRestore(t *testing.T) {
+	now := metav1.Now()
+	vr := controlsTestVR()
+	vr.DeletionTimestamp = &now
+	vr.Annotations = map[string]string{AnnotationSTSDeleted: "true", AnnotationForceDelete: "true"}
+
+	r, c := newControlsTestReconciler(t, vr)
+	ctx := context.Background()
+	require.NoError(t, backupSTSToConfigMap(ctx, c, vr, controlsTestSTS()))
+
+	_, err := r.Reconcile(ctx, ctrlRequest("test-resize"))
+	require.NoError(t, err)
+
+	err = c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-sts"}, &appsv1.StatefulSet{})
+	assert.True(t, apierrors.IsNotFound(err))
+	// The backup stays for the orphan scanner and volmig recover
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: getSTSBackupConfigMapName("test-resize")}, &corev1.ConfigMap{}))
+
+	err = c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-resize"}, &storagev1alpha1.VolumeResize{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func ctrlRequest(name string) ctrl.Request {
+	return ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: name}}
+}
+
+func TestAbortDeletesHookJobs(t *testing.T) {
+	vr := controlsTestVR()
+	vr.Spec.Abort = true
+	hookJob := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+		Name:      "test-resize-prestop-flush-1",
+		Namespace: "default",
+		Labels:    map[string]string{LabelMigrationName: "test-resize", LabelHook: "flush"},
+	}}
+	r, c := newControlsTestReconciler(t, vr, controlsTestSTS(), hookJob)
+	ctx := context.Background()
+
+	_, err := r.Reconcile(ctx, ctrlRequest("test-resize"))
+	require.NoError(t, err)
+	assert.True(t, apierrors.IsNotFound(c.Get(ctx, types.NamespacedName{Namespace: "default", Name: hookJob.Name}, &batchv1.Job{})))
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+	"github.com/thomas-maurice/migcontroller/internal/stsbackup"
+)
+
+// specDrift returns the top-level spec fields of the live StatefulSet that differ from the expected one
+func specDrift(expected, live *appsv1.StatefulSet) ([]string, error) {
+	toMap := func(spec appsv1.StatefulSetSpec) (map[string]any, error) {
+		raw, err := json.Marshal(spec)
+		if err != nil {
+			return nil, err
+		}
+		m := map[string]any{}
+		return m, json.Unmarshal(raw, &m)
+	}
+
+	want, err := toMap(expected.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize the backed up StatefulSet spec: %w", err)
+	}
+	got, err := toMap(live.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize the live StatefulSet spec: %w", err)
+	}
+
+	drifted := []string{}
+	for key := range want {
+		if !reflect.DeepEqual(want[key], got[key]) {
+			drifted = append(drifted, key)
+		}
+	}
+	for key := range got {
+		if _, ok := want[key]; !ok {
+			drifted = append(drifted, key)
+		}
+	}
+	slices.Sort(drifted)
+	return drifted, nil
+}
+
+// reconcileDrift compares the live StatefulSet with what the controller recreated from the backup, before
+// deleting it again. Depending on spec.driftPolicy, pod template changes are merged into the backup so they
+// survive the next recreation, or the migration fails rather than silently reverting them.
+func (r *VolumeResizeReconciler) reconcileDrift(ctx context.Context, vr *storagev1alpha1.VolumeResize, live *appsv1.StatefulSet) error {
+	log := logf.FromContext(ctx)
+
+	if vr.Spec.DriftPolicy == DriftPolicyIgnore {
+		return nil
+	}
+
+	// Nothing to compare with before the first deletion
+	cm := &corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: vr.Namespace, Name: getSTSBackupConfigMapName(vr.Name)}, cm)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get backup ConfigMap: %w", err)
+	}
+	backup, err := stsbackup.Read(ctx, r.Client, cm)
+	if err != nil {
+		return err
+	}
+
+	expected := backup.DeepCopy()
+	oldSize, err := r.oldSizeReplicas(ctx, vr, expected)
+	if err != nil {
+		return err
+	}
+	applyMigratedTemplates(vr, expected, oldSize)
+	drifted, err := specDrift(expected, live)
+	if err != nil {
+		return err
+	}
+	if len(drifted) == 0 {
+		return nil
+	}
+
+	fields := "spec." + strings.Join(drifted, ", spec.")
+	if vr.Spec.DriftPolicy == DriftPolicyFail || !slices.Equal(drifted, []string{"template"}) {
+		r.setDriftCondition(vr, "DriftRejected", fmt.Sprintf("%s changed since the backup", fields))
+		r.recordEvent(vr, corev1.EventTypeWarning, "SpecDrift", "DetectDrift",
+			"StatefulSet %s changed since it was backed up (%s), refusing to revert it", live.Name, fields)
+		return fmt.Errorf("StatefulSet %s changed since it was backed up (%s); with driftPolicy %s only pod template changes are merged, "+
+			"revert the change or set driftPolicy to Ignore to recreate it from the backup", live.Name, fields, driftPolicyOrDefault(vr))
+	}
+
+	backup.Spec.Template = live.Spec.Template
+	revision, err := stsbackup.Write(ctx, r.Client, cm.ObjectMeta, backup)
+	if err != nil {
+		return err
+	}
+	log.Info("Merged StatefulSet pod template drift into the backup", "revision", revision)
+
+	r.setDriftCondition(vr, "TemplateMerged", fmt.Sprintf("pod template changes merged into backup revision %d", revision))
+	r.recordEvent(vr, corev1.EventTypeNormal, "SpecDrift", "MergeDrift",
+		"StatefulSet %s pod template changed since it was backed up, merged into backup revision %d", live.Name, revision)
+	return r.Status().Update(ctx, vr)
+}
+
+// setDriftCondition records detected drift in the status conditions
+func (r *VolumeResizeReconciler) setDriftCondition(vr *storagev1alpha1.VolumeResize, reason, message string) {
+	meta.SetStatusCondition(&vr.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeSpecDrift,
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: vr.Generation,
+	})
+}
+
+func driftPolicyOrDefault(vr *storagev1alpha1.VolumeResize) string {
+	if vr.Spec.DriftPolicy == "" {
+		return DriftPolicyMerge
+	}
+	return vr.Spec.DriftPolicy
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+	"github.com/thomas-maurice/migcontroller/internal/stsbackup"
+)
+
+// driftTestSetup backs up the StatefulSet and returns it as the controller recreated it after the first batch
+func driftTestSetup(t *testing.T, policy string) (*VolumeResizeReconciler, *storagev1alpha1.VolumeResize, *appsv1.StatefulSet) {
+	vr := controlsTestVR()
+	vr.Spec.DriftPolicy = policy
+	r, _ := newControlsTestReconciler(t, vr)
+
+	sts := controlsTestSTS()
+	sts.Spec.Template.Spec.Containers = []corev1.Container{{Name: "app", Image: "app:1.0"}}
+	require.NoError(t, backupSTSToConfigMap(context.Background(), r.Client, vr, sts))
+
+	live := sts.DeepCopy()
+	applyMigratedTemplates(vr, live, nil)
+	return r, vr, live
+}
+
+func TestReconcileDriftNoChange(t *testing.T) {
+	r, vr, live := driftTestSetup(t, "")
+	require.NoError(t, r.reconcileDrift(context.Background(), vr, live))
+	assert.Nil(t, meta.FindStatusCondition(vr.Status.Conditions, ConditionTypeSpecDrift))
+}
+
+func TestReconcileDriftMergesTemplate(t *testing.T) {
+	r, vr, live := driftTestSetup(t, DriftPolicyMerge)
+	ctx := context.Background()
+	live.Spec.Template.Spec.Containers[0].Image = "app:1.1"
+
+	require.NoError(t, r.reconcileDrift(ctx, vr, live))
+	cond := meta.FindStatusCondition(vr.Status.Conditions, ConditionTypeSpecDrift)
+	require.NotNil(t, cond)
+	assert.Equal(t, "TemplateMerged", cond.Reason)
+
+	cm := &corev1.ConfigMap{}
+	require.NoError(t, r.Get(ctx, types.NamespacedName{Namespace: "default", Name: getSTSBackupConfigMapName(vr.Name)}, cm))
+	assert.Equal(t, "2", cm.Annotations[stsbackup.AnnotationRevision])
+	backup, err := stsbackup.Read(ctx, r.Client, cm)
+	require.NoError(t, err)
+	assert.Equal(t, "app:1.1", backup.Spec.Template.Spec.Containers[0].Image)
+	// The backup keeps the original sizes, restoreSTS applies the new ones
+	assert.Equal(t, "1Gi", backup.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests.Storage().String())
+}
+
+func TestReconcileDriftRejectsOtherChanges(t *testing.T) {
+	r, vr, live := driftTestSetup(t, DriftPolicyMerge)
+	replicas := int32(5)
+	live.Spec.Replicas = &replicas
+
+	err := r.reconcileDrift(context.Background(), vr, live)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "spec.replicas")
+	assert.Equal(t, "DriftRejected", meta.FindStatusCondition(vr.Status.Conditions, ConditionTypeSpecDrift).Reason)
+}
+
+func TestReconcileDriftPolicies(t *testing.T) {
+	r, vr, live := driftTestSetup(t, DriftPolicyFail)
+	live.Spec.Template.Spec.Containers[0].Image = "app:1.1"
+	require.Error(t, r.reconcileDrift(context.Background(), vr, live))
+
+	r, vr, live = driftTestSetup(t, DriftPolicyIgnore)
+	live.Spec.Template.Spec.Containers[0].Image = "app:1.1"
+	require.NoError(t, r.reconcileDrift(context.Background(), vr, live))
+	assert.Empty(t, vr.Status.Conditions)
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+// GitOps object kinds and API versions
+const (
+	argoCDApplicationAPIVersion = "argoproj.io/v1alpha1"
+	argoCDApplicationKind       = "Application"
+	fluxKustomizationAPIVersion = "kustomize.toolkit.fluxcd.io/v1"
+	fluxKustomizationKind       = "Kustomization"
+	fluxHelmReleaseAPIVersion   = "helm.toolkit.fluxcd.io/v2"
+	fluxHelmReleaseKind         = "HelmRelease"
+)
+
+// gitOpsTracker identifies the Argo CD or Flux object that may deploy a StatefulSet
+type gitOpsTracker struct {
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+}
+
+// gitOpsTrackers returns the candidate trackers of a StatefulSet from its tracking labels and annotations,
+// most specific first. Helm charts also set app.kubernetes.io/instance, so candidates may not exist.
+func gitOpsTrackers(sts *appsv1.StatefulSet, argoCDNamespace string) []gitOpsTracker {
+	if argoCDNamespace == "" {
+		argoCDNamespace = DefaultArgoCDNamespace
+	}
+
+	trackers := []gitOpsTracker{}
+	if name := sts.Labels[LabelFluxHelmReleaseName]; name != "" {
+		trackers = append(trackers, gitOpsTracker{fluxHelmReleaseAPIVersion, fluxHelmReleaseKind, sts.Labels[LabelFluxHelmReleaseNamespace], name})
+	}
+	if name := sts.Labels[LabelFluxKustomizationName]; name != "" {
+		trackers = append(trackers, gitOpsTracker{fluxKustomizationAPIVersion, fluxKustomizationKind, sts.Labels[LabelFluxKustomizationNamespace], name})
+	}
+	// The tracking id is <app>:<group>/<kind>:<namespace>/<name>, with <app> being <namespace>_<name>
+	// for Applications outside the Argo CD namespace
+	if id := sts.Annotations[AnnotationArgoCDTrackingID]; id != "" {
+		app, _, _ := strings.Cut(id, ":")
+		namespace := argoCDNamespace
+		if ns, name, ok := strings.Cut(app, "_"); ok {
+			namespace, app = ns, name
+		}
+		trackers = append(trackers, gitOpsTracker{argoCDApplicationAPIVersion, argoCDApplicationKind, namespace, app})
+	}
+	if name := sts.Labels[LabelArgoCDInstance]; name != "" {
+		trackers = append(trackers, gitOpsTracker{argoCDApplicationAPIVersion, argoCDApplicationKind, argoCDNamespace, name})
+	}
+	return trackers
+}
+
+// findGitOpsTracker returns the first candidate tracker that exists, or nil if the StatefulSet is not deployed by GitOps
+func findGitOpsTracker(ctx context.Context, c client.Client, sts *appsv1.StatefulSet, argoCDNamespace string) (*unstructured.Unstructured, error) {
+	for _, t := range gitOpsTrackers(sts, argoCDNamespace) {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(t.APIVersion)
+		obj.SetKind(t.Kind)
+		err := c.Get(ctx, types.NamespacedName{Namespace: t.Namespace, Name: t.Name}, obj)
+		if err == nil {
+			return obj, nil
+		}
+		// The tracker is gone or its CRD is not installed
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			continue
+		}
+		return nil, fmt.Errorf("failed to get %s %s/%s: %w", t.Kind, t.Namespace, t.Name, err)
+	}
+	return nil, nil
+}
+
+// argoCDNamespace returns the Argo CD namespace configured on the VolumeResize
+func argoCDNamespace(vr *storagev1alpha1.VolumeResize) string {
+	if vr.Spec.GitOps == nil {
+		return ""
+	}
+	return vr.Spec.GitOps.ArgoCDNamespace
+}
+
+// warnGitOpsTracked warns when a GitOps tool deploys the StatefulSet and spec.gitOps.suspend is not set
+func (r *VolumeResizeReconciler) warnGitOpsTracked(ctx context.Context, vr *storagev1alpha1.VolumeResize, sts *appsv1.StatefulSet) {
+	if vr.Spec.GitOps != nil && vr.Spec.GitOps.Suspend {
+		return
+	}
+	tracker, err := findGitOpsTracker(ctx, r.Client, sts, argoCDNamespace(vr))
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to look for a GitOps tracker")
+		return
+	}
+	if tracker == nil {
+		return
+	}
+	r.recordEvent(vr, corev1.EventTypeWarning, "GitOpsTracked", "Validate",
+		"StatefulSet %s is deployed by %s %s/%s which may recreate it from Git with the old sizes, set spec.gitOps.suspend to pause it",
+		sts.Name, tracker.GetKind(), tracker.GetNamespace(), tracker.GetName())
+}
+
+// suspendGitOps stops the Argo CD or Flux object deploying the StatefulSet from syncing while it is deleted.
+// What was changed is persisted in the status first, so a crash cannot lose track of it.
+func (r *VolumeResizeReconciler) suspendGitOps(ctx context.Context, vr *storagev1alpha1.VolumeResize, sts *appsv1.StatefulSet) error {
+	if vr.Spec.GitOps == nil || !vr.Spec.GitOps.Suspend || vr.Status.SuspendedGitOps != nil {
+		return nil
+	}
+	obj, err := findGitOpsTracker(ctx, r.Client, sts, argoCDNamespace(vr))
+	if err != nil || obj == nil {
+		return err
+	}
+
+	suspended := &storagev1alpha1.SuspendedGitOps{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+	}
+	patch := client.MergeFrom(obj.DeepCopy())
+
+	if obj.GetKind() == argoCDApplicationKind {
+		automated, found, err := unstructured.NestedFieldCopy(obj.Object, "spec", "syncPolicy", "automated")
+		if err != nil || !found {
+			// Auto-sync is off, nothing will recreate the StatefulSet
+			return err
+		}
+		raw, err := json.Marshal(automated)
+		if err != nil {
+			return fmt.Errorf("failed to serialize automated sync policy: %w", err)
+		}
+		suspended.PreviousAutomated = string(raw)
+		unstructured.RemoveNestedField(obj.Object, "spec", "syncPolicy", "automated")
+	} else {
+		if already, _, _ := unstructured.NestedBool(obj.Object, "spec", "suspend"); already {
+			return nil
+		}
+		if err := unstructured.SetNestedField(obj.Object, true, "spec", "suspend"); err != nil {
+			return err
+		}
+	}
+
+	if err := r.Patch(ctx, obj, patch); err != nil {
+		return fmt.Errorf("failed to suspend %s %s/%s: %w", obj.GetKind(), obj.GetNamespace(), obj.GetName(), err)
+	}
+
+	logf.FromContext(ctx).Info("Suspended GitOps sync", "kind", suspended.Kind, "namespace", suspended.Namespace, "name", suspended.Name)
+	vr.Status.SuspendedGitOps = suspended
+	return r.Status().Update(ctx, vr)
+}
+
+// resumeGitOps undoes suspendGitOps once the StatefulSet is back and warns if Argo CD still sees it differ
+// from Git, which means the source declares the old sizes. The caller persists the status.
+func (r *VolumeResizeReconciler) resumeGitOps(ctx context.Context, vr *storagev1alpha1.VolumeResize) error {
+	suspended := vr.Status.SuspendedGitOps
+	if suspended == nil {
+		return nil
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(suspended.APIVersion)
+	obj.SetKind(suspended.Kind)
+	err := r.Get(ctx, types.NamespacedName{Namespace: suspended.Namespace, Name: suspended.Name}, obj)
+	if apierrors.IsNotFound(err) {
+		vr.Status.SuspendedGitOps = nil
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get %s %s/%s: %w", suspended.Kind, suspended.Namespace, suspended.Name, err)
+	}
+
+	patch := client.MergeFrom(obj.DeepCopy())
+	if suspended.Kind == argoCDApplicationKind {
+		automated := map[string]any{}
+		if err := json.Unmarshal([]byte(suspended.PreviousAutomated), &automated); err != nil {
+			return fmt.Errorf("failed to read the previous automated sync policy: %w", err)
+		}
+		if err := unstructured.SetNestedField(obj.Object, automated, "spec", "syncPolicy", "automated"); err != nil {
+			return err
+		}
+	} else {
+		unstructured.RemoveNestedField(obj.Object, "spec", "suspend")
+	}
+
+	if err := r.Patch(ctx, obj, patch); err != nil {
+		return fmt.Errorf("failed to resume %s %s/%s: %w", suspended.Kind, suspended.Namespace, suspended.Name, err)
+	}
+	logf.FromContext(ctx).Info("Resumed GitOps sync", "kind", suspended.Kind, "namespace", suspended.Namespace, "name", suspended.Name)
+
+	if suspended.Kind == argoCDApplicationKind && argoCDResourceOutOfSync(obj, vr.Namespace, vr.Spec.StatefulSetName) {
+		r.recordEvent(vr, corev1.EventTypeWarning, "GitSourceOutdated", "ResumeGitOps",
+			"Application %s/%s reports StatefulSet %s OutOfSync, make sure Git declares the new volumeClaimTemplate sizes",
+			suspended.Namespace, suspended.Name, vr.Spec.StatefulSetName)
+	} else if suspended.Kind != argoCDApplicationKind {
+		// Flux has no per-resource sync status to look at
+		r.recordEvent(vr, corev1.EventTypeNormal, "GitOpsResumed", "ResumeGitOps",
+			"Resumed %s %s/%s, make sure Git declares the new volumeClaimTemplate sizes before it reconciles",
+			suspended.Kind, suspended.Namespace, suspended.Name)
+	}
+
+	vr.Status.SuspendedGitOps = nil
+	return nil
+}
+
+// argoCDResourceOutOfSync reports whether an Application lists the StatefulSet as OutOfSync with Git
+func argoCDResourceOutOfSync(app *unstructured.Unstructured, namespace, stsName string) bool {
+	resources, _, _ := unstructured.NestedSlice(app.Object, "status", "resources")
+	for _, res := range resources {
+		m, ok := res.(map[string]any)
+		if !ok {
+			continue
+		}
+		if m["kind"] == "StatefulSet" && m["name"] == stsName && m["namespace"] == namespace {
+			return m["status"] == "OutOfSync"
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+var (
+	argoAppGVK       = schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "Application"}
+	fluxKustomizeGVK = schema.GroupVersionKind{Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Kind: "Kustomization"}
+)
+
+func TestGitOpsTrackers(t *testing.T) {
+	sts := controlsTestSTS()
+	assert.Empty(t, gitOpsTrackers(sts, ""))
+
+	sts.Annotations = map[string]string{AnnotationArgoCDTrackingID: "apps_my-app:apps/StatefulSet:default/test-sts"}
+	sts.Labels = map[string]string{
+		LabelArgoCDInstance:             "my-release",
+		LabelFluxKustomizationName:      "infra",
+		LabelFluxKustomizationNamespace: "flux-system",
+	}
+
+	trackers := gitOpsTrackers(sts, "")
+	require.Len(t, trackers, 3)
+	assert.Equal(t, gitOpsTracker{fluxKustomizationAPIVersion, fluxKustomizationKind, "flux-system", "infra"}, trackers[0])
+	assert.Equal(t, gitOpsTracker{argoCDApplicationAPIVersion, argoCDApplicationKind, "apps", "my-app"}, trackers[1])
+	assert.Equal(t, gitOpsTracker{argoCDApplicationAPIVersion, argoCDApplicationKind, DefaultArgoCDNamespace, "my-release"}, trackers[2])
+}
+
+func TestSuspendAndResumeArgoCD(t *testing.T) {
+	app := &unstructured.Unstructured{}
+	app.SetGroupVersionKind(argoAppGVK)
+	app.SetName("my-app")
+	app.SetNamespace("argocd")
+	require.NoError(t, unstructured.SetNestedField(app.Object, map[string]any{"prune": true, "selfHeal": true}, "spec", "syncPolicy", "automated"))
+
+	vr := controlsTestVR()
+	vr.Spec.GitOps = &storagev1alpha1.GitOpsSpec{Suspend: true}
+	r, c := newControlsTestReconciler(t, vr)
+	r.Scheme.AddKnownTypeWithName(argoAppGVK, &unstructured.Unstructured{})
+	recorder := events.NewFakeRecorder(10)
+	r.Recorder = recorder
+	ctx := context.Background()
+	require.NoError(t, c.Create(ctx, app))
+
+	sts := controlsTestSTS()
+	sts.Labels = map[string]string{LabelArgoCDInstance: "my-app"}
+
+	require.NoError(t, r.suspendGitOps(ctx, vr, sts))
+	require.NotNil(t, vr.Status.SuspendedGitOps)
+	assert.JSONEq(t, `{"prune":true,"selfHeal":true}`, vr.Status.SuspendedGitOps.PreviousAutomated)
+
+	got := &unstructured.Unstructured{}
+	got.SetGroupVersionKind(argoAppGVK)
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "argocd", Name: "my-app"}, got))
+	_, found, _ := unstructured.NestedFieldCopy(got.Object, "spec", "syncPolicy", "automated")
+	assert.False(t, found)
+
+	// Git still declares the old size
+	require.NoError(t, unstructured.SetNestedSlice(got.Object, []any{
+		map[string]any{"kind": "StatefulSet", "namespace": "default", "name": "test-sts", "status": "OutOfSync"},
+	}, "status", "resources"))
+	require.NoError(t, c.Update(ctx, got))
+
+	require.NoError(t, r.resumeGitOps(ctx, vr))
+	assert.Nil(t, vr.Status.SuspendedGitOps)
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "argocd", Name: "my-app"}, got))
+	selfHeal, _, _ := unstructured.NestedBool(got.Object, "spec", "syncPolicy", "automated", "selfHeal")
+	assert.True(t, selfHeal)
+
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "GitSourceOutdated")
+}
+
+func TestSuspendAndResumeFlux(t *testing.T) {
+	ks := &unstructured.Unstructured{}
+	ks.SetGroupVersionKind(fluxKustomizeGVK)
+	ks.SetName("infra")
+	ks.SetNamespace("flux-system")
+
+	vr := controlsTestVR()
+	vr.Spec.GitOps = &storagev1alpha1.GitOpsSpec{Suspend: true}
+	r, c := newControlsTestReconciler(t, vr)
+	r.Scheme.AddKnownTypeWithName(fluxKustomizeGVK, &unstructured.Unstructured{})
+	ctx := context.Background()
+	require.NoError(t, c.Create(ctx, ks))
+
+	sts := controlsTestSTS()
+	sts.Labels = map[string]string{LabelFluxKustomizationName: "infra", LabelFluxKustomizationNamespace: "flux-system"}
+
+	require.NoError(t, r.suspendGitOps(ctx, vr, sts))
+	require.NotNil(t, vr.Status.SuspendedGitOps)
+
+	got := &unstructured.Unstructured{}
+	got.SetGroupVersionKind(fluxKustomizeGVK)
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "flux-system", Name: "infra"}, got))
+	suspended, _, _ := unstructured.NestedBool(got.Object, "spec", "suspend")
+	assert.True(t, suspended)
+
+	require.NoError(t, r.resumeGitOps(ctx, vr))
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "flux-system", Name: "infra"}, got))
+	_, found, _ := unstructured.NestedFieldCopy(got.Object, "spec", "suspend")
+	assert.False(t, found)
+}
+
+func TestSuspendGitOpsSkipsMissingTracker(t *testing.T) {
+	vr := controlsTestVR()
+	vr.Spec.GitOps = &storagev1alpha1.GitOpsSpec{Suspend: true}
+	r, _ := newControlsTestReconciler(t, vr)
+	ctx := context.Background()
+
+	// Argo CD is not installed, the instance label comes from a plain Helm install
+	sts := controlsTestSTS()
+	sts.Labels = map[string]string{LabelArgoCDInstance: "my-release"}
+
+	require.NoError(t, r.suspendGitOps(ctx, vr, sts))
+	assert.Nil(t, vr.Status.SuspendedGitOps)
+}
@@ -0,0 +1,404 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+const (
+	// healthGateTimeout bounds a single HTTP request or command of a health gate
+	healthGateTimeout = 10 * time.Second
+	// healthGatePollInterval is how often failing health gates are checked again
+	healthGatePollInterval = 15 * time.Second
+)
+
+// healthGateHTTPClient does not verify certificates, like the kubelet for HTTPS probes
+var healthGateHTTPClient = &http.Client{
+	Timeout: healthGateTimeout,
+	Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec // pods serve self-signed certificates
+	},
+}
+
+// PodExecutor runs a command in a container of a pod and returns its output
+type PodExecutor interface {
+	Exec(ctx context.Context, namespace, pod, container string, command []string) (string, error)
+}
+
+// restPodExecutor goes through the pods/exec subresource of the API server
+type restPodExecutor struct {
+	config    *rest.Config
+	clientset kubernetes.Interface
+}
+
+// NewPodExecutor returns a PodExecutor using the given API server connection
+func NewPodExecutor(config *rest.Config) (PodExecutor, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create clientset: %w", err)
+	}
+	return &restPodExecutor{config: config, clientset: clientset}, nil
+}
+
+func (e *restPodExecutor) Exec(ctx context.Context, namespace, pod, container string, command []string) (string, error) {
+	req := e.clientset.CoreV1().RESTClient().Post().
+		Namespace(namespace).Resource("pods").Name(pod).SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(e.config, http.MethodPost, req.URL())
+	if err != nil {
+		return "", fmt.Errorf("failed to create executor: %w", err)
+	}
+	var stdout, stderr bytes.Buffer
+	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{Stdout: &stdout, Stderr: &stderr})
+	return strings.TrimSpace(stdout.String() + stderr.String()), err
+}
+
+// ValidateHealthGates checks every health gate has a unique DNS label name, it ends up in Job names and
+// labels, and exactly one check
+func ValidateHealthGates(gates []storagev1alpha1.HealthGate, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	seen := map[string]bool{}
+	for i, gate := range gates {
+		gatePath := path.Index(i)
+		if seen[gate.Name] {
+			allErrs = append(allErrs, field.Duplicate(gatePath.Child("name"), gate.Name))
+		}
+		seen[gate.Name] = true
+		for _, msg := range validation.IsDNS1123Label(gate.Name) {
+			allErrs = append(allErrs, field.Invalid(gatePath.Child("name"), gate.Name, msg))
+		}
+
+		checks := 0
+		for _, set := range []bool{gate.HTTPGet != nil, gate.Exec != nil, gate.Job != nil} {
+			if set {
+				checks++
+			}
+		}
+		if checks != 1 {
+			allErrs = append(allErrs, field.Invalid(gatePath, gate.Name, "exactly one of httpGet, exec or job must be set"))
+		}
+		if gate.HTTPGet != nil {
+			port := gate.HTTPGet.Port
+			if port.Type == intstr.Int && port.IntVal <= 0 || port.Type == intstr.String && port.StrVal == "" {
+				allErrs = append(allErrs, field.Invalid(gatePath.Child("httpGet", "port"), port.String(), "must be a port number or name"))
+			}
+		}
+		if gate.Exec != nil && len(gate.Exec.Command) == 0 {
+			allErrs = append(allErrs, field.Required(gatePath.Child("exec", "command"), "a command is required"))
+		}
+	}
+	return allErrs
+}
+
+// minReadySeconds returns how long migrated pods must have been ready, spec.minReadySeconds or the StatefulSet's
+func minReadySeconds(vr *storagev1alpha1.VolumeResize, stsMinReadySeconds int32) int32 {
+	if vr.Spec.MinReadySeconds != nil {
+		return *vr.Spec.MinReadySeconds
+	}
+	return stsMinReadySeconds
+}
+
+// podAvailable reports whether a pod has been ready for minReadySeconds, and how long is left otherwise
+func podAvailable(pod *corev1.Pod, minReadySeconds int32, now time.Time) (bool, time.Duration) {
+	ready := podReadyCondition(pod)
+	if ready == nil {
+		return false, 0
+	}
+	left := ready.LastTransitionTime.Add(time.Duration(minReadySeconds) * time.Second).Sub(now)
+	return left <= 0, left
+}
+
+// checkHealthGates runs every health gate against the batch, and reports the first one failing in the status.
+// The Jobs of the gates are cleaned up once they all passed.
+func (r *VolumeResizeReconciler) checkHealthGates(ctx context.Context, vr *storagev1alpha1.VolumeResize, batch []int32, desiredReplicas int32) (bool, error) {
+	if len(vr.Spec.HealthGates) == 0 {
+		return true, nil
+	}
+
+	for _, gate := range vr.Spec.HealthGates {
+		err := r.checkHealthGate(ctx, vr, gate, batch, desiredReplicas)
+		if err == nil {
+			continue
+		}
+
+		message := fmt.Sprintf("Waiting for health gate %s: %v", gate.Name, err)
+		if vr.Status.Message == message {
+			return false, nil
+		}
+		logf.FromContext(ctx).Info("Health gate is not passing", "gate", gate.Name, "reason", err.Error())
+		r.recordEvent(vr, corev1.EventTypeWarning, "HealthGateFailing", "CheckHealthGate", "%s", message)
+		vr.Status.Message = message
+		return false, r.Status().Update(ctx, vr)
+	}
+
+	return true, r.cleanupHealthGateJobs(ctx, vr)
+}
+
+// checkHealthGate runs a Job gate once for the batch, and the other gates against every pod of the batch.
+// Ordinals above the StatefulSet replicas have no pod to check.
+func (r *VolumeResizeReconciler) checkHealthGate(ctx context.Context, vr *storagev1alpha1.VolumeResize, gate storagev1alpha1.HealthGate, batch []int32, desiredReplicas int32) error {
+	if gate.Job != nil {
+		return r.checkJobGate(ctx, vr, gate, batch[0])
+	}
+
+	for _, replica := range batch {
+		if replica >= desiredReplicas {
+			continue
+		}
+		pod := &corev1.Pod{}
+		podName := getPodName(vr.Spec.StatefulSetName, replica)
+		if err := r.Get(ctx, types.NamespacedName{Namespace: vr.Namespace, Name: podName}, pod); err != nil {
+			return fmt.Errorf("failed to get pod %s: %w", podName, err)
+		}
+
+		var err error
+		switch {
+		case gate.HTTPGet != nil:
+			err = checkHTTPGate(ctx, pod, gate.HTTPGet)
+		case gate.Exec != nil:
+			err = r.checkExecGate(ctx, pod, gate.Exec)
+		}
+		if err != nil {
+			return fmt.Errorf("pod %s: %w", podName, err)
+		}
+	}
+	return nil
+}
+
+// resolvePort returns the number of a port given by number or by container port name
+func resolvePort(pod *corev1.Pod, port intstr.IntOrString) (int, error) {
+	if port.Type == intstr.Int {
+		return port.IntValue(), nil
+	}
+	for _, container := range pod.Spec.Containers {
+		for _, p := range container.Ports {
+			if p.Name == port.StrVal {
+				return int(p.ContainerPort), nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("no container port named %s", port.StrVal)
+}
+
+// checkHTTPGate requests the gate path on the pod IP, an answer between 200 and 399 passes
+func checkHTTPGate(ctx context.Context, pod *corev1.Pod, gate *storagev1alpha1.HTTPGetHealthGate) error {
+	if pod.Status.PodIP == "" {
+		return errors.New("pod has no IP yet")
+	}
+	port, err := resolvePort(pod, gate.Port)
+	if err != nil {
+		return err
+	}
+
+	scheme := "http"
+	if gate.Scheme == string(corev1.URISchemeHTTPS) {
+		scheme = "https"
+	}
+	path := gate.Path
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	url := fmt.Sprintf("%s://%s%s", scheme, net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(port)), path)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := healthGateHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("GET %s failed: %w", path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("GET %s returned %d", path, resp.StatusCode)
+	}
+	return nil
+}
+
+// checkExecGate runs the gate command in the pod, exit code 0 passes
+func (r *VolumeResizeReconciler) checkExecGate(ctx context.Context, pod *corev1.Pod, gate *storagev1alpha1.ExecHealthGate) error {
+	ctx, cancel := context.WithTimeout(ctx, healthGateTimeout)
+	defer cancel()
+	return r.execInPod(ctx, pod, gate.Container, gate.Command)
+}
+
+// execInPod runs a command in a container of the pod, the first one by default. A failure carries the last
+// line of the output, which usually says what is wrong and keeps the status message short.
+func (r *VolumeResizeReconciler) execInPod(ctx context.Context, pod *corev1.Pod, container string, command []string) error {
+	if r.Executor == nil {
+		return errors.New("exec is not available, the controller has no pod executor")
+	}
+	if container == "" && len(pod.Spec.Containers) > 0 {
+		container = pod.Spec.Containers[0].Name
+	}
+
+	output, err := r.Executor.Exec(ctx, pod.Namespace, pod.Name, container, command)
+	if err == nil {
+		return nil
+	}
+	if output = strings.TrimSpace(output); output != "" {
+		lines := strings.Split(output, "\n")
+		return fmt.Errorf("%s failed: %v: %s", command[0], err, lines[len(lines)-1])
+	}
+	return fmt.Errorf("%s failed: %v", command[0], err)
+}
+
+// boundedJobName keeps a generated Job name within 63 characters, the Job controller copies it into the
+// job-name label of its pods. A longer name is truncated and ends with a hash of the full name instead.
+func boundedJobName(name string) string {
+	if len(name) <= validation.DNS1123LabelMaxLength {
+		return name
+	}
+	sum := sha256.Sum256([]byte(name))
+	hash := hex.EncodeToString(sum[:])[:8]
+	prefix := strings.TrimRight(name[:validation.DNS1123LabelMaxLength-len(hash)-1], "-")
+	return prefix + "-" + hash
+}
+
+// getHealthGateJobName returns the name of the Job of a gate for the batch starting with the replica
+func getHealthGateJobName(vrName, gateName string, replica int32) string {
+	return boundedJobName(fmt.Sprintf("%s-gate-%s-%d", vrName, gateName, replica))
+}
+
+// buildHealthGateJob creates the Job of a gate. It does not retry on its own, a failed Job is run again.
+func buildHealthGateJob(vr *storagev1alpha1.VolumeResize, gate storagev1alpha1.HealthGate, replica int32) *batchv1.Job {
+	labels := map[string]string{
+		LabelMigrationName: vr.Name,
+		LabelReplica:       fmt.Sprintf("%d", replica),
+		LabelHealthGate:    gate.Name,
+	}
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getHealthGateJobName(vr.Name, gate.Name, replica),
+			Namespace: vr.Namespace,
+			Labels:    labels,
+			Annotations: map[string]string{
+				AnnotationManagedBy: "volume-resize-operator",
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: new(int32),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyNever,
+					ServiceAccountName: gate.Job.ServiceAccountName,
+					Containers: []corev1.Container{
+						{
+							Name:    "gate",
+							Image:   gate.Job.Image,
+							Command: gate.Job.Command,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// checkJobGate starts the Job of a gate and passes once it completed. A failed Job is deleted to be run again.
+func (r *VolumeResizeReconciler) checkJobGate(ctx context.Context, vr *storagev1alpha1.VolumeResize, gate storagev1alpha1.HealthGate, replica int32) error {
+	name := getHealthGateJobName(vr.Name, gate.Name, replica)
+
+	job := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: vr.Namespace, Name: name}, job)
+	if apierrors.IsNotFound(err) {
+		if err := r.Create(ctx, buildHealthGateJob(vr, gate, replica)); err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create job %s: %w", name, err)
+		}
+		return fmt.Errorf("job %s is running", name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get job %s: %w", name, err)
+	}
+	if !job.DeletionTimestamp.IsZero() {
+		return fmt.Errorf("job %s failed, waiting for it to be deleted to run it again", name)
+	}
+
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			return nil
+		case batchv1.JobFailed:
+			if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrors.IsNotFound(err) {
+				return fmt.Errorf("failed to delete failed job %s: %w", name, err)
+			}
+			return fmt.Errorf("job %s failed, waiting for it to be deleted to run it again", name)
+		}
+	}
+	return fmt.Errorf("job %s is running", name)
+}
+
+// cleanupHealthGateJobs deletes the Jobs run by the health gates of the migration
+func (r *VolumeResizeReconciler) cleanupHealthGateJobs(ctx context.Context, vr *storagev1alpha1.VolumeResize) error {
+	return r.deleteMigrationJobs(ctx, vr, LabelHealthGate)
+}
+
+// deleteMigrationJobs deletes the Jobs of the migration carrying the label
+func (r *VolumeResizeReconciler) deleteMigrationJobs(ctx context.Context, vr *storagev1alpha1.VolumeResize, label string) error {
+	jobs := &batchv1.JobList{}
+	if err := r.List(ctx, jobs, client.InNamespace(vr.Namespace),
+		client.MatchingLabels{LabelMigrationName: vr.Name}, client.HasLabels{label}); err != nil {
+		return fmt.Errorf("failed to list jobs: %w", err)
+	}
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete job %s: %w", job.Name, err)
+		}
+	}
+	return nil
+}
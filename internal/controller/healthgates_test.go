@@ -0,0 +1,199 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+type fakeExecutor struct {
+	calls  [][]string
+	output string
+	err    error
+}
+
+func (e *fakeExecutor) Exec(_ context.Context, _, pod, container string, command []string) (string, error) {
+	e.calls = append(e.calls, append([]string{pod, container}, command...))
+	return e.output, e.err
+}
+
+func TestPodAvailable(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	// Running is not enough
+	available, _ := podAvailable(canaryTestPod(false, now, 0), 0, now)
+	assert.False(t, available)
+
+	available, _ = podAvailable(canaryTestPod(true, now, 0), 0, now)
+	assert.True(t, available)
+
+	available, left := podAvailable(canaryTestPod(true, now.Add(-10*time.Second), 0), 30, now)
+	assert.False(t, available)
+	assert.Equal(t, 20*time.Second, left)
+}
+
+func TestValidateHealthGates(t *testing.T) {
+	path := field.NewPath("spec", "healthGates")
+	valid := []storagev1alpha1.HealthGate{
+		{Name: "http", HTTPGet: &storagev1alpha1.HTTPGetHealthGate{Port: intstr.FromString("http")}},
+		{Name: "exec", Exec: &storagev1alpha1.ExecHealthGate{Command: []string{"nodetool", "status"}}},
+		{Name: "job", Job: &storagev1alpha1.JobHealthGate{Image: "busybox"}},
+	}
+	assert.Empty(t, ValidateHealthGates(valid, path))
+
+	errs := ValidateHealthGates([]storagev1alpha1.HealthGate{
+		{Name: "none"},
+		{Name: "both", HTTPGet: &storagev1alpha1.HTTPGetHealthGate{Port: intstr.FromInt32(8080)}, Job: &storagev1alpha1.JobHealthGate{Image: "busybox"}},
+		{Name: "both", HTTPGet: &storagev1alpha1.HTTPGetHealthGate{}},
+		{Name: "Cluster_Health", Job: &storagev1alpha1.JobHealthGate{Image: "busybox"}},
+	}, path)
+	require.Len(t, errs, 5)
+	assert.Equal(t, "spec.healthGates[0]", errs[0].Field)
+	assert.Equal(t, "spec.healthGates[1]", errs[1].Field)
+	assert.Equal(t, field.ErrorTypeDuplicate, errs[2].Type)
+	assert.Equal(t, "spec.healthGates[2].httpGet.port", errs[3].Field)
+	assert.Equal(t, "spec.healthGates[3].name", errs[4].Field)
+}
+
+func TestGetHealthGateJobName(t *testing.T) {
+	assert.Equal(t, "test-resize-gate-quorum-2", getHealthGateJobName("test-resize", "quorum", 2))
+
+	// Long names stay valid label values and distinct per replica
+	vrName := strings.Repeat("a", 50)
+	first := getHealthGateJobName(vrName, "cluster-health", 1)
+	second := getHealthGateJobName(vrName, "cluster-health", 2)
+	assert.Len(t, first, 63)
+	assert.NotEqual(t, first, second)
+	assert.Empty(t, validation.IsDNS1123Label(first))
+}
+
+func TestCheckHTTPGate(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/.well-known/ready", r.URL.Path)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	host, portStr, err := net.SplitHostPort(serverURL.Host)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+
+	pod := canaryTestPod(true, time.Now(), 0)
+	pod.Status.PodIP = host
+	pod.Spec.Containers = []corev1.Container{{Name: "app", Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: int32(port)}}}}
+	gate := &storagev1alpha1.HTTPGetHealthGate{Path: "/v1/.well-known/ready", Port: intstr.FromString("http")}
+	ctx := context.Background()
+
+	require.NoError(t, checkHTTPGate(ctx, pod, gate))
+
+	status = http.StatusServiceUnavailable
+	err = checkHTTPGate(ctx, pod, gate)
+	require.Error(t, err)
+	assert.Equal(t, "GET /v1/.well-known/ready returned 503", err.Error())
+
+	gate.Port = intstr.FromString("grpc")
+	err = checkHTTPGate(ctx, pod, gate)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no container port named grpc")
+}
+
+func TestCheckExecGate(t *testing.T) {
+	pod := canaryTestPod(true, time.Now(), 0)
+	pod.Spec.Containers = []corev1.Container{{Name: "cassandra"}, {Name: "sidecar"}}
+	gate := &storagev1alpha1.ExecHealthGate{Command: []string{"nodetool", "status"}}
+	ctx := context.Background()
+
+	r := &VolumeResizeReconciler{}
+	require.Error(t, r.checkExecGate(ctx, pod, gate))
+
+	executor := &fakeExecutor{}
+	r.Executor = executor
+	require.NoError(t, r.checkExecGate(ctx, pod, gate))
+	assert.Equal(t, [][]string{{"test-sts-0", "cassandra", "nodetool", "status"}}, executor.calls)
+
+	executor.output = "Datacenter: dc1\nUN 10.0.0.1\nDN 10.0.0.2"
+	executor.err = errors.New("command terminated with exit code 1")
+	err := r.checkExecGate(ctx, pod, gate)
+	require.Error(t, err)
+	assert.Equal(t, "nodetool failed: command terminated with exit code 1: DN 10.0.0.2", err.Error())
+}
+
+func TestJobGateHoldsBatch(t *testing.T) {
+	vr := controlsTestVR()
+	vr.Spec.HealthGates = []storagev1alpha1.HealthGate{
+		{Name: "quorum", Job: &storagev1alpha1.JobHealthGate{Image: "cassandra-check", Command: []string{"check-quorum"}}},
+	}
+	r, c := newControlsTestReconciler(t, vr)
+	ctx := context.Background()
+	jobKey := types.NamespacedName{Namespace: "default", Name: "test-resize-gate-quorum-2"}
+
+	passed, err := r.checkHealthGates(ctx, vr, []int32{2}, 3)
+	require.NoError(t, err)
+	assert.False(t, passed)
+	assert.Equal(t, "Waiting for health gate quorum: job test-resize-gate-quorum-2 is running", vr.Status.Message)
+
+	job := &batchv1.Job{}
+	require.NoError(t, c.Get(ctx, jobKey, job))
+	assert.Equal(t, "cassandra-check", job.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, "quorum", job.Labels[LabelHealthGate])
+
+	// A failed Job is deleted to be run again
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+	require.NoError(t, c.Status().Update(ctx, job))
+	passed, err = r.checkHealthGates(ctx, vr, []int32{2}, 3)
+	require.NoError(t, err)
+	assert.False(t, passed)
+	assert.True(t, apierrors.IsNotFound(c.Get(ctx, jobKey, &batchv1.Job{})))
+
+	_, err = r.checkHealthGates(ctx, vr, []int32{2}, 3)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, jobKey, job))
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue, LastTransitionTime: metav1.Now()}}
+	require.NoError(t, c.Status().Update(ctx, job))
+
+	// A completed Job passes and is cleaned up
+	passed, err = r.checkHealthGates(ctx, vr, []int32{2}, 3)
+	require.NoError(t, err)
+	assert.True(t, passed)
+	assert.True(t, apierrors.IsNotFound(c.Get(ctx, jobKey, &batchv1.Job{})))
+}
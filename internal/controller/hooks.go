@@ -0,0 +1,356 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+const (
+	// defaultHookTimeout applies when the CRD default was not set
+	defaultHookTimeout = 5 * time.Minute
+	// maxExecHookTimeout caps exec hooks, they block the reconcile loop while they run
+	maxExecHookTimeout = 30 * time.Second
+	// hookPollInterval is how often running hook Jobs are checked
+	hookPollInterval = 10 * time.Second
+)
+
+// stageHooks returns the hooks of a stage
+func stageHooks(hooks *storagev1alpha1.MigrationHooks, stage string) []storagev1alpha1.ReplicaHook {
+	if hooks == nil {
+		return nil
+	}
+	switch stage {
+	case HookStagePreStop:
+		return hooks.PreStop
+	case HookStagePostCopy:
+		return hooks.PostCopy
+	case HookStagePostStart:
+		return hooks.PostStart
+	}
+	return nil
+}
+
+// ValidateHooks checks the hooks of every stage have a unique DNS label name, it ends up in Job names and
+// labels, and exactly one action. postCopy hooks can only run Jobs, the pods are down then.
+func ValidateHooks(hooks *storagev1alpha1.MigrationHooks, path *field.Path) field.ErrorList {
+	if hooks == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	stages := []struct {
+		name  string
+		hooks []storagev1alpha1.ReplicaHook
+	}{
+		{"preStop", hooks.PreStop},
+		{"postCopy", hooks.PostCopy},
+		{"postStart", hooks.PostStart},
+	}
+	for _, stage := range stages {
+		seen := map[string]bool{}
+		for i, hook := range stage.hooks {
+			hookPath := path.Child(stage.name).Index(i)
+			if seen[hook.Name] {
+				allErrs = append(allErrs, field.Duplicate(hookPath.Child("name"), hook.Name))
+			}
+			seen[hook.Name] = true
+			for _, msg := range validation.IsDNS1123Label(hook.Name) {
+				allErrs = append(allErrs, field.Invalid(hookPath.Child("name"), hook.Name, msg))
+			}
+
+			if (hook.Exec == nil) == (hook.Job == nil) {
+				allErrs = append(allErrs, field.Invalid(hookPath, hook.Name, "exactly one of exec or job must be set"))
+			}
+			if hook.Exec != nil {
+				if stage.name == "postCopy" {
+					allErrs = append(allErrs, field.Forbidden(hookPath.Child("exec"), "the pod is down after the copy, only job hooks can run"))
+				}
+				if len(hook.Exec.Command) == 0 {
+					allErrs = append(allErrs, field.Required(hookPath.Child("exec", "command"), "a command is required"))
+				}
+			}
+			if hook.Timeout.Duration < 0 {
+				allErrs = append(allErrs, field.Invalid(hookPath.Child("timeout"), hook.Timeout.Duration.String(), "must not be negative"))
+			}
+		}
+	}
+	return allErrs
+}
+
+// hookTimeout returns how long a hook may run, at most maxExecHookTimeout for exec hooks
+func hookTimeout(hook storagev1alpha1.ReplicaHook) time.Duration {
+	timeout := hook.Timeout.Duration
+	if timeout <= 0 {
+		timeout = defaultHookTimeout
+	}
+	if hook.Exec != nil {
+		timeout = min(timeout, maxExecHookTimeout)
+	}
+	return timeout
+}
+
+// expandHookCommand replaces $(REPLICA) and $(POD_NAME) in the command of an exec hook
+func expandHookCommand(command []string, replica int32, podName string) []string {
+	replacer := strings.NewReplacer("$(REPLICA)", fmt.Sprintf("%d", replica), "$(POD_NAME)", podName)
+	expanded := make([]string, len(command))
+	for i, arg := range command {
+		expanded[i] = replacer.Replace(arg)
+	}
+	return expanded
+}
+
+// findHookStatus returns the run of a hook for a replica
+func findHookStatus(statuses []storagev1alpha1.HookStatus, stage, name string, replica int32) *storagev1alpha1.HookStatus {
+	for i := range statuses {
+		hs := &statuses[i]
+		if hs.Stage == stage && hs.Name == name && hs.Replica == replica {
+			return hs
+		}
+	}
+	return nil
+}
+
+// clearHookStatuses drops the hook runs of replicas about to be migrated, e.g. again after a retry
+func clearHookStatuses(vr *storagev1alpha1.VolumeResize, batch []int32) {
+	vr.Status.Hooks = slices.DeleteFunc(vr.Status.Hooks, func(hs storagev1alpha1.HookStatus) bool {
+		return slices.Contains(batch, hs.Replica)
+	})
+}
+
+// failedHook returns the first hook of the batch whose failure stops the migration
+func failedHook(statuses []storagev1alpha1.HookStatus, batch []int32) *storagev1alpha1.HookStatus {
+	for i := range statuses {
+		hs := &statuses[i]
+		if hs.Phase == HookPhaseFailed && slices.Contains(batch, hs.Replica) {
+			return hs
+		}
+	}
+	return nil
+}
+
+// hookFailureMessage explains why a hook failed the migration
+func hookFailureMessage(hs *storagev1alpha1.HookStatus) string {
+	return fmt.Sprintf("%s hook %s failed on replica %d: %s", hs.Stage, hs.Name, hs.Replica, hs.Message)
+}
+
+// runHooks runs the hooks of a stage for every replica of the batch, one hook after the other. Returns true
+// once they all finished, or once one failed the migration, see failedHook.
+func (r *VolumeResizeReconciler) runHooks(ctx context.Context, vr *storagev1alpha1.VolumeResize, stage string, batch []int32) (bool, error) {
+	log := logf.FromContext(ctx)
+
+	for _, hook := range stageHooks(vr.Spec.Hooks, stage) {
+		finished := true
+		changed := false
+		for _, replica := range batch {
+			hs := findHookStatus(vr.Status.Hooks, stage, hook.Name, replica)
+			if hs != nil && hs.Phase != HookPhaseRunning {
+				continue
+			}
+			if hs == nil {
+				now := metav1.Now()
+				vr.Status.Hooks = append(vr.Status.Hooks, storagev1alpha1.HookStatus{
+					Name:      hook.Name,
+					Stage:     stage,
+					Replica:   replica,
+					Phase:     HookPhaseRunning,
+					StartTime: &now,
+				})
+				hs = &vr.Status.Hooks[len(vr.Status.Hooks)-1]
+				vr.Status.Message = fmt.Sprintf("Running %s hook %s on replica %d", stage, hook.Name, replica)
+				log.Info("Running hook", "stage", stage, "hook", hook.Name, "replica", replica)
+				changed = true
+			}
+
+			if err := r.runHook(ctx, vr, hook, hs); err != nil {
+				return false, err
+			}
+			switch hs.Phase {
+			case HookPhaseRunning:
+				finished = false
+			case HookPhaseSucceeded:
+				changed = true
+			default:
+				log.Info("Hook failed", "stage", stage, "hook", hook.Name, "replica", replica, "reason", hs.Message, "phase", hs.Phase)
+				r.recordEvent(vr, corev1.EventTypeWarning, "HookFailed", "RunHook", "%s", hookFailureMessage(hs))
+				changed = true
+			}
+		}
+
+		if changed {
+			if err := r.Status().Update(ctx, vr); err != nil {
+				return false, err
+			}
+		}
+		if !finished {
+			return false, nil
+		}
+		if failedHook(vr.Status.Hooks, batch) != nil {
+			return true, nil
+		}
+	}
+	return true, nil
+}
+
+// runHook moves a running hook forward, recording its outcome in hs. Exec hooks run to completion right away,
+// within maxExecHookTimeout, Job hooks are started and then checked on every call.
+func (r *VolumeResizeReconciler) runHook(ctx context.Context, vr *storagev1alpha1.VolumeResize, hook storagev1alpha1.ReplicaHook, hs *storagev1alpha1.HookStatus) error {
+	timeout := hookTimeout(hook)
+	if hs.StartTime != nil && time.Since(hs.StartTime.Time) > timeout {
+		failHook(hook, hs, "timed out after %s", timeout)
+		return nil
+	}
+	if hook.Job != nil {
+		return r.runJobHook(ctx, vr, hook, hs)
+	}
+
+	podName := getPodName(vr.Spec.StatefulSetName, hs.Replica)
+
+	// A replica above the StatefulSet replicas has no pod to run in
+	pod := &corev1.Pod{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: vr.Namespace, Name: podName}, pod); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		hs.Phase = HookPhaseSucceeded
+		hs.Message = "Skipped, the replica has no pod"
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if err := r.execInPod(ctx, pod, hook.Exec.Container, expandHookCommand(hook.Exec.Command, hs.Replica, podName)); err != nil {
+		failHook(hook, hs, "%v", err)
+		return nil
+	}
+	hs.Phase = HookPhaseSucceeded
+	return nil
+}
+
+// failHook records why a hook failed, as Ignored when its failure policy lets it through
+func failHook(hook storagev1alpha1.ReplicaHook, hs *storagev1alpha1.HookStatus, format string, args ...any) {
+	hs.Phase = HookPhaseFailed
+	if hook.FailurePolicy == HookFailurePolicyIgnore {
+		hs.Phase = HookPhaseIgnored
+	}
+	hs.Message = fmt.Sprintf(format, args...)
+}
+
+// getHookJobName returns the name of the Job of a hook for a replica
+func getHookJobName(vrName, stage, hookName string, replica int32) string {
+	return boundedJobName(fmt.Sprintf("%s-%s-%s-%d", vrName, strings.ToLower(stage), hookName, replica))
+}
+
+// buildHookJob creates the Job of a hook, with the replica in its environment. It does not retry, and its
+// activeDeadlineSeconds enforces the hook timeout.
+func buildHookJob(vr *storagev1alpha1.VolumeResize, hook storagev1alpha1.ReplicaHook, hs *storagev1alpha1.HookStatus) *batchv1.Job {
+	labels := map[string]string{
+		LabelMigrationName: vr.Name,
+		LabelReplica:       fmt.Sprintf("%d", hs.Replica),
+		LabelHook:          hook.Name,
+	}
+	deadline := int64(hookTimeout(hook).Seconds())
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getHookJobName(vr.Name, hs.Stage, hook.Name, hs.Replica),
+			Namespace: vr.Namespace,
+			Labels:    labels,
+			Annotations: map[string]string{
+				AnnotationManagedBy:     "volume-resize-operator",
+				AnnotationHookStartTime: hs.StartTime.Format(time.RFC3339),
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:          new(int32),
+			ActiveDeadlineSeconds: &deadline,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyNever,
+					ServiceAccountName: hook.Job.ServiceAccountName,
+					Containers: []corev1.Container{
+						{
+							Name:    "hook",
+							Image:   hook.Job.Image,
+							Command: hook.Job.Command,
+							Env: []corev1.EnvVar{
+								{Name: "REPLICA", Value: fmt.Sprintf("%d", hs.Replica)},
+								{Name: "POD_NAME", Value: getPodName(vr.Spec.StatefulSetName, hs.Replica)},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// runJobHook starts the Job of a hook and records its outcome once it finished. A Job left by an earlier run
+// of the hook is deleted first.
+func (r *VolumeResizeReconciler) runJobHook(ctx context.Context, vr *storagev1alpha1.VolumeResize, hook storagev1alpha1.ReplicaHook, hs *storagev1alpha1.HookStatus) error {
+	name := getHookJobName(vr.Name, hs.Stage, hook.Name, hs.Replica)
+
+	job := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: vr.Namespace, Name: name}, job)
+	if apierrors.IsNotFound(err) {
+		if err := r.Create(ctx, buildHookJob(vr, hook, hs)); err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create job %s: %w", name, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get job %s: %w", name, err)
+	}
+	if !job.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	if job.Annotations[AnnotationHookStartTime] != hs.StartTime.Format(time.RFC3339) {
+		if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete job %s of an earlier run: %w", name, err)
+		}
+		return nil
+	}
+
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			hs.Phase = HookPhaseSucceeded
+		case batchv1.JobFailed:
+			failHook(hook, hs, "job %s failed: %s", name, cond.Reason)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+func TestValidateHooks(t *testing.T) {
+	path := field.NewPath("spec", "hooks")
+	assert.Empty(t, ValidateHooks(nil, path))
+	assert.Empty(t, ValidateHooks(&storagev1alpha1.MigrationHooks{
+		PreStop:   []storagev1alpha1.ReplicaHook{{Name: "exclude", Exec: &storagev1alpha1.ExecHealthGate{Command: []string{"exclude"}}}},
+		PostCopy:  []storagev1alpha1.ReplicaHook{{Name: "fsck", Job: &storagev1alpha1.JobHealthGate{Image: "busybox"}}},
+		PostStart: []storagev1alpha1.ReplicaHook{{Name: "exclude", Exec: &storagev1alpha1.ExecHealthGate{Command: []string{"include"}}}},
+	}, path))
+
+	errs := ValidateHooks(&storagev1alpha1.MigrationHooks{
+		PreStop: []storagev1alpha1.ReplicaHook{
+			{Name: "none"},
+			{Name: "none", Job: &storagev1alpha1.JobHealthGate{Image: "busybox"}},
+		},
+		PostCopy:  []storagev1alpha1.ReplicaHook{{Name: "checkpoint", Exec: &storagev1alpha1.ExecHealthGate{Command: []string{"psql"}}}},
+		PostStart: []storagev1alpha1.ReplicaHook{{Name: "warm.cache", Job: &storagev1alpha1.JobHealthGate{Image: "busybox"}}},
+	}, path)
+	require.Len(t, errs, 4)
+	assert.Equal(t, "spec.hooks.preStop[0]", errs[0].Field)
+	assert.Equal(t, field.ErrorTypeDuplicate, errs[1].Type)
+	assert.Equal(t, "spec.hooks.postCopy[0].exec", errs[2].Field)
+	assert.Equal(t, "spec.hooks.postStart[0].name", errs[3].Field)
+}
+
+func TestGetHookJobName(t *testing.T) {
+	assert.Equal(t, "test-resize-postcopy-fsck-2", getHookJobName("test-resize", HookStagePostCopy, "fsck", 2))
+
+	name := getHookJobName(strings.Repeat("a", 40), HookStagePostCopy, "filesystem-check", 2)
+	assert.Len(t, name, 63)
+	assert.NotEqual(t, name, getHookJobName(strings.Repeat("a", 40), HookStagePostCopy, "filesystem-check", 3))
+}
+
+func TestHookTimeout(t *testing.T) {
+	job := storagev1alpha1.ReplicaHook{Name: "fsck", Job: &storagev1alpha1.JobHealthGate{Image: "e2fsprogs"}}
+	assert.Equal(t, defaultHookTimeout, hookTimeout(job))
+	job.Timeout = metav1.Duration{Duration: time.Hour}
+	assert.Equal(t, time.Hour, hookTimeout(job))
+
+	// Exec hooks block the reconcile loop, a long timeout is capped
+	exec := storagev1alpha1.ReplicaHook{Name: "flush", Exec: &storagev1alpha1.ExecHealthGate{Command: []string{"sync"}}}
+	assert.Equal(t, maxExecHookTimeout, hookTimeout(exec))
+	exec.Timeout = metav1.Duration{Duration: 5 * time.Second}
+	assert.Equal(t, 5*time.Second, hookTimeout(exec))
+}
+
+func TestExecHooks(t *testing.T) {
+	vr := controlsTestVR()
+	vr.Spec.Hooks = &storagev1alpha1.MigrationHooks{
+		PreStop: []storagev1alpha1.ReplicaHook{
+			{Name: "exclude", Exec: &storagev1alpha1.ExecHealthGate{Command: []string{"exclude", "$(POD_NAME)", "$(REPLICA)"}}},
+		},
+	}
+	pod := canaryTestPod(true, time.Now(), 0)
+	pod.Spec.Containers = []corev1.Container{{Name: "elasticsearch"}}
+	r, _ := newControlsTestReconciler(t, vr, pod)
+	executor := &fakeExecutor{}
+	r.Executor = executor
+	ctx := context.Background()
+
+	// Replica 1 has no pod, the hook is skipped for it
+	done, err := r.runHooks(ctx, vr, HookStagePreStop, []int32{0, 1})
+	require.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, [][]string{{"test-sts-0", "elasticsearch", "exclude", "test-sts-0", "0"}}, executor.calls)
+	require.Len(t, vr.Status.Hooks, 2)
+	assert.Equal(t, HookPhaseSucceeded, vr.Status.Hooks[0].Phase)
+	assert.Equal(t, "Skipped, the replica has no pod", vr.Status.Hooks[1].Message)
+
+	// A finished hook does not run again
+	_, err = r.runHooks(ctx, vr, HookStagePreStop, []int32{0, 1})
+	require.NoError(t, err)
+	assert.Len(t, executor.calls, 1)
+
+	// An ignored failure carries on, other failures stop the migration
+	executor.err = errors.New("command terminated with exit code 1")
+	clearHookStatuses(vr, []int32{0, 1})
+	vr.Spec.Hooks.PreStop[0].FailurePolicy = HookFailurePolicyIgnore
+	done, err = r.runHooks(ctx, vr, HookStagePreStop, []int32{0})
+	require.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, HookPhaseIgnored, vr.Status.Hooks[0].Phase)
+	assert.Nil(t, failedHook(vr.Status.Hooks, []int32{0}))
+
+	clearHookStatuses(vr, []int32{0})
+	vr.Spec.Hooks.PreStop[0].FailurePolicy = HookFailurePolicyFail
+	done, err = r.runHooks(ctx, vr, HookStagePreStop, []int32{0})
+	require.NoError(t, err)
+	assert.True(t, done)
+	hs := failedHook(vr.Status.Hooks, []int32{0})
+	require.NotNil(t, hs)
+	assert.Equal(t, "PreStop hook exclude failed on replica 0: exclude failed: command terminated with exit code 1", hookFailureMessage(hs))
+}
+
+func TestJobHooks(t *testing.T) {
+	vr := controlsTestVR()
+	vr.Spec.Hooks = &storagev1alpha1.MigrationHooks{
+		PostCopy: []storagev1alpha1.ReplicaHook{
+			{Name: "fsck", Job: &storagev1alpha1.JobHealthGate{Image: "e2fsprogs", Command: []string{"fsck", "$(POD_NAME)"}}, Timeout: metav1.Duration{Duration: time.Minute}},
+		},
+	}
+	r, c := newControlsTestReconciler(t, vr)
+	ctx := context.Background()
+	jobKey := types.NamespacedName{Namespace: "default", Name: "test-resize-postcopy-fsck-2"}
+
+	// A Job left by an earlier run is replaced
+	require.NoError(t, c.Create(ctx, &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+		Name:        jobKey.Name,
+		Namespace:   jobKey.Namespace,
+		Annotations: map[string]string{AnnotationHookStartTime: "2026-01-01T00:00:00Z"},
+	}}))
+	done, err := r.runHooks(ctx, vr, HookStagePostCopy, []int32{2})
+	require.NoError(t, err)
+	assert.False(t, done)
+	assert.True(t, apierrors.IsNotFound(c.Get(ctx, jobKey, &batchv1.Job{})))
+	assert.Equal(t, "Running PostCopy hook fsck on replica 2", vr.Status.Message)
+
+	_, err = r.runHooks(ctx, vr, HookStagePostCopy, []int32{2})
+	require.NoError(t, err)
+	job := &batchv1.Job{}
+	require.NoError(t, c.Get(ctx, jobKey, job))
+	assert.Equal(t, int64(60), *job.Spec.ActiveDeadlineSeconds)
+	assert.Contains(t, job.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "POD_NAME", Value: "test-sts-2"})
+	assert.Equal(t, "fsck", job.Labels[LabelHook])
+
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "DeadlineExceeded"}}
+	require.NoError(t, c.Status().Update(ctx, job))
+	done, err = r.runHooks(ctx, vr, HookStagePostCopy, []int32{2})
+	require.NoError(t, err)
+	assert.True(t, done)
+	hs := failedHook(vr.Status.Hooks, []int32{2})
+	require.NotNil(t, hs)
+	assert.Equal(t, "job test-resize-postcopy-fsck-2 failed: DeadlineExceeded", hs.Message)
+}
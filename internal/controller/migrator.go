@@ -19,7 +19,6 @@ package controller
 import (
 	"context"
 	"fmt"
-	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -30,11 +29,20 @@ import (
 	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
 )
 
+// migratorPodOptions carries the per-replica settings that shape the migrator pod
+type migratorPodOptions struct {
+	// BlockMode attaches both claims as raw devices instead of mounting them
+	BlockMode bool
+
+	// Placement keeps the pod on nodes that can attach the old volume
+	Placement podPlacement
+}
+
 // buildMigratorPod creates the pod spec for the rclone migration pod
-func buildMigratorPod(vr *storagev1alpha1.VolumeResize, vol storagev1alpha1.VolumeResizeTarget, replica int32, oldPVCName, newPVCName string) *corev1.Pod {
+func buildMigratorPod(vr *storagev1alpha1.VolumeResize, vol storagev1alpha1.VolumeResizeTarget, replica int32, oldPVCName, newPVCName string, opts migratorPodOptions) *corev1.Pod {
 	podName := fmt.Sprintf("%s-migrator-%d-%s", vr.Name, replica, vol.Name)
 
-	return &corev1.Pod{
+	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      podName,
 			Namespace: vr.Namespace,
@@ -95,11 +103,61 @@ func buildMigratorPod(vr *storagev1alpha1.VolumeResize, vol storagev1alpha1.Volu
 			},
 		},
 	}
+
+	if opts.BlockMode {
+		configureBlockMigrator(&pod.Spec.Containers[0], vol.Block)
+		if vol.Block != nil && vol.Block.Mode == BlockCopyModeFilesystem {
+			addBlockScratchVolume(&pod.Spec)
+		}
+	}
+
+	pod.Spec.Affinity = opts.Placement.Affinity
+	pod.Spec.Tolerations = opts.Placement.Tolerations
+
+	return pod
+}
+
+// configureBlockMigrator switches the migrator container to raw device access for volumeMode Block claims
+func configureBlockMigrator(container *corev1.Container, block *storagev1alpha1.BlockCopySpec) {
+	container.VolumeMounts = nil
+	container.VolumeDevices = []corev1.VolumeDevice{
+		{
+			Name:       "source",
+			DevicePath: "/dev/source",
+		},
+		{
+			Name:       "dest",
+			DevicePath: "/dev/dest",
+		},
+	}
+	container.Env = []corev1.EnvVar{
+		{Name: "MIGRATION_MODE", Value: MigrationModeBlock},
+		{Name: "SOURCE_DEVICE", Value: "/dev/source"},
+		{Name: "DEST_DEVICE", Value: "/dev/dest"},
+	}
+	if block != nil {
+		container.Env = append(container.Env, corev1.EnvVar{Name: "BLOCK_COPY_MODE", Value: block.Mode})
+		if block.Length != nil {
+			container.Env = append(container.Env, corev1.EnvVar{Name: "BLOCK_COPY_LENGTH", Value: fmt.Sprintf("%d", block.Length.Value())})
+		}
+	}
+}
+
+// addBlockScratchVolume gives the Filesystem block copy an emptyDir to shrink a sparse image of the source
+// in, so the source device is never written. It takes node ephemeral storage for the data in use.
+func addBlockScratchVolume(spec *corev1.PodSpec) {
+	container := &spec.Containers[0]
+	container.Env = append(container.Env, corev1.EnvVar{Name: "SCRATCH_DIR", Value: "/scratch"})
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: "scratch", MountPath: "/scratch"})
+	spec.Volumes = append(spec.Volumes, corev1.Volume{
+		Name:         "scratch",
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	})
 }
 
 // createMigratorPod creates and runs the migration pod
-func createMigratorPod(ctx context.Context, c client.Client, vr *storagev1alpha1.VolumeResize, vol storagev1alpha1.VolumeResizeTarget, replica int32, oldPVCName, newPVCName string) (*corev1.Pod, error) {
-	pod := buildMigratorPod(vr, vol, replica, oldPVCName, newPVCName)
+func createMigratorPod(ctx context.Context, c client.Client, vr *storagev1alpha1.VolumeResize, vol storagev1alpha1.VolumeResizeTarget, replica int32, oldPVCName, newPVCName string, opts migratorPodOptions) (*corev1.Pod, error) {
+	pod := buildMigratorPod(vr, vol, replica, oldPVCName, newPVCName, opts)
 
 	// Check if pod already exists (idempotency)
 	existingPod := &corev1.Pod{}
@@ -118,27 +176,21 @@ func createMigratorPod(ctx context.Context, c client.Client, vr *storagev1alpha1
 	return pod, nil
 }
 
-// waitForMigrationComplete waits for the migration pod to complete
-func waitForMigrationComplete(ctx context.Context, c client.Client, podName, namespace string, timeout time.Duration) error {
-	deadline := time.Now().Add(timeout)
-
-	for time.Now().Before(deadline) {
-		pod := &corev1.Pod{}
-		if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: podName}, pod); err != nil {
-			return fmt.Errorf("failed to get migration pod status: %w", err)
-		}
-
-		switch pod.Status.Phase {
-		case corev1.PodSucceeded:
-			return nil
-		case corev1.PodFailed:
-			return fmt.Errorf("migration pod failed")
-		}
-
-		time.Sleep(time.Second * 2)
+// getMigratorPodState reports the progress of a migrator pod
+func getMigratorPodState(ctx context.Context, c client.Client, podName, namespace string) (transferState, error) {
+	pod := &corev1.Pod{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: podName}, pod); err != nil {
+		return "", fmt.Errorf("failed to get migration pod status: %w", err)
 	}
 
-	return fmt.Errorf("timeout waiting for migration to complete")
+	switch pod.Status.Phase {
+	case corev1.PodSucceeded:
+		return transferSucceeded, nil
+	case corev1.PodFailed:
+		return transferFailed, nil
+	default:
+		return transferRunning, nil
+	}
 }
 
 // cleanupMigratorPod deletes the migration pod
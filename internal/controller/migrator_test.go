@@ -44,7 +44,7 @@ func TestBuildMigratorPodVolumeMounts(t *testing.T) {
 		NewSize: resource.MustParse("500Mi"),
 	}
 
-	pod := buildMigratorPod(vr, vol, 0, "data-test-sts-0", "data-test-sts-0-new")
+	pod := buildMigratorPod(vr, vol, 0, "data-test-sts-0", "data-test-sts-0-new", migratorPodOptions{})
 
 	// Verify volume mounts
 	require.Len(t, pod.Spec.Containers[0].VolumeMounts, 2)
@@ -71,7 +71,7 @@ func TestBuildMigratorPodEnvVars(t *testing.T) {
 		NewSize: resource.MustParse("500Mi"),
 	}
 
-	pod := buildMigratorPod(vr, vol, 0, "data-test-sts-0", "data-test-sts-0-new")
+	pod := buildMigratorPod(vr, vol, 0, "data-test-sts-0", "data-test-sts-0-new", migratorPodOptions{})
 
 	// Verify env vars
 	envVars := pod.Spec.Containers[0].Env
@@ -104,7 +104,7 @@ func TestBuildMigratorPodImage(t *testing.T) {
 		NewSize: resource.MustParse("500Mi"),
 	}
 
-	pod := buildMigratorPod(vr, vol, 0, "data-test-sts-0", "data-test-sts-0-new")
+	pod := buildMigratorPod(vr, vol, 0, "data-test-sts-0", "data-test-sts-0-new", migratorPodOptions{})
 
 	assert.Equal(t, DefaultMigratorImage, pod.Spec.Containers[0].Image)
 }
@@ -122,13 +122,101 @@ func TestBuildMigratorPodLabels(t *testing.T) {
 		NewSize: resource.MustParse("500Mi"),
 	}
 
-	pod := buildMigratorPod(vr, vol, 0, "data-test-sts-0", "data-test-sts-0-new")
+	pod := buildMigratorPod(vr, vol, 0, "data-test-sts-0", "data-test-sts-0-new", migratorPodOptions{})
 
 	assert.Equal(t, "test-resize", pod.Labels[LabelMigrationName])
 	assert.Equal(t, "0", pod.Labels[LabelReplica])
 	assert.Equal(t, "data", pod.Labels[LabelVolumeName])
 }
 
+func TestBuildMigratorPodBlockMode(t *testing.T) {
+	vr := &storagev1alpha1.VolumeResize{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-resize",
+			Namespace: "default",
+		},
+	}
+
+	length := resource.MustParse("200Mi")
+	vol := storagev1alpha1.VolumeResizeTarget{
+		Name:    "data",
+		NewSize: resource.MustParse("500Mi"),
+		Block: &storagev1alpha1.BlockCopySpec{
+			Mode:   BlockCopyModeLength,
+			Length: &length,
+		},
+	}
+
+	pod := buildMigratorPod(vr, vol, 0, "data-test-sts-0", "data-test-sts-0-new", migratorPodOptions{BlockMode: true})
+
+	container := pod.Spec.Containers[0]
+	assert.Empty(t, container.VolumeMounts)
+	require.Len(t, container.VolumeDevices, 2)
+	assert.Equal(t, "source", container.VolumeDevices[0].Name)
+	assert.Equal(t, "/dev/source", container.VolumeDevices[0].DevicePath)
+	assert.Equal(t, "dest", container.VolumeDevices[1].Name)
+	assert.Equal(t, "/dev/dest", container.VolumeDevices[1].DevicePath)
+
+	env := map[string]string{}
+	for _, e := range container.Env {
+		env[e.Name] = e.Value
+	}
+	assert.Equal(t, MigrationModeBlock, env["MIGRATION_MODE"])
+	assert.Equal(t, BlockCopyModeLength, env["BLOCK_COPY_MODE"])
+	assert.Equal(t, "209715200", env["BLOCK_COPY_LENGTH"])
+}
+
+func TestBuildMigratorPodBlockFilesystemScratch(t *testing.T) {
+	vr := &storagev1alpha1.VolumeResize{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-resize",
+			Namespace: "default",
+		},
+	}
+	vol := storagev1alpha1.VolumeResizeTarget{
+		Name:    "data",
+		NewSize: resource.MustParse("500Mi"),
+		Block:   &storagev1alpha1.BlockCopySpec{Mode: BlockCopyModeFilesystem},
+	}
+
+	pod := buildMigratorPod(vr, vol, 0, "data-test-sts-0", "data-test-sts-0-new", migratorPodOptions{BlockMode: true})
+
+	// The source filesystem is shrunk as an image on scratch space, never in place
+	container := pod.Spec.Containers[0]
+	require.Len(t, container.VolumeMounts, 1)
+	assert.Equal(t, "/scratch", container.VolumeMounts[0].MountPath)
+	require.Len(t, pod.Spec.Volumes, 3)
+	assert.NotNil(t, pod.Spec.Volumes[2].EmptyDir)
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "SCRATCH_DIR", Value: "/scratch"})
+}
+
+func TestBuildMigratorPodPinnedToNode(t *testing.T) {
+	vr := &storagev1alpha1.VolumeResize{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-resize",
+			Namespace: "default",
+		},
+	}
+
+	vol := storagev1alpha1.VolumeResizeTarget{
+		Name:    "data",
+		NewSize: resource.MustParse("500Mi"),
+	}
+
+	tolerations := []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "db", Effect: corev1.TaintEffectNoSchedule}}
+	placement := migrationPlacement(zonalPV("pv-1", "zone-b"), tolerations)
+	pod := buildMigratorPod(vr, vol, 0, "data-test-sts-0", "data-test-sts-0-new", migratorPodOptions{Placement: placement})
+
+	require.NotNil(t, pod.Spec.Affinity)
+	terms := pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	require.Len(t, terms, 1)
+	assert.Empty(t, terms[0].MatchFields, "pod must not be pinned to a single node")
+	assert.Equal(t, zoneLabel, terms[0].MatchExpressions[0].Key)
+	assert.Equal(t, []string{"zone-b"}, terms[0].MatchExpressions[0].Values)
+	assert.Equal(t, tolerations, pod.Spec.Tolerations)
+	assert.Empty(t, pod.Spec.NodeName, "pod must still go through the scheduler")
+}
+
 func TestMigratorPodNameFormat(t *testing.T) {
 	vr := &storagev1alpha1.VolumeResize{
 		ObjectMeta: metav1.ObjectMeta{
@@ -142,7 +230,7 @@ func TestMigratorPodNameFormat(t *testing.T) {
 		NewSize: resource.MustParse("500Mi"),
 	}
 
-	pod := buildMigratorPod(vr, vol, 1, "data-test-sts-1", "data-test-sts-1-new")
+	pod := buildMigratorPod(vr, vol, 1, "data-test-sts-1", "data-test-sts-1-new", migratorPodOptions{})
 
 	assert.Equal(t, "test-resize-migrator-1-data", pod.Name)
 }
@@ -193,7 +281,7 @@ func TestCreateMigratorPodIdempotent(t *testing.T) {
 	ctx := context.Background()
 
 	// Should return existing pod without error
-	pod, err := createMigratorPod(ctx, c, vr, vol, 0, "data-test-sts-0", "data-test-sts-0-new")
+	pod, err := createMigratorPod(ctx, c, vr, vol, 0, "data-test-sts-0", "data-test-sts-0-new", migratorPodOptions{})
 	require.NoError(t, err)
 	assert.NotNil(t, pod)
 	assert.Equal(t, "test-resize-migrator-0-data", pod.Name)
@@ -228,3 +316,30 @@ func TestCleanupMigratorPodNotFound(t *testing.T) {
 	err := cleanupMigratorPod(ctx, c, "nonexistent", "default")
 	require.NoError(t, err)
 }
+
+func TestGetMigratorPodState(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	tests := []struct {
+		phase    corev1.PodPhase
+		expected transferState
+	}{
+		{corev1.PodPending, transferRunning},
+		{corev1.PodRunning, transferRunning},
+		{corev1.PodSucceeded, transferSucceeded},
+		{corev1.PodFailed, transferFailed},
+	}
+
+	for _, tt := range tests {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: "default"},
+			Status:     corev1.PodStatus{Phase: tt.phase},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pod).Build()
+
+		state, err := getMigratorPodState(context.Background(), c, "test-pod", "default")
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, state, string(tt.phase))
+	}
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+// ValidateOrder checks replicas is only set for the Explicit order and leader only for LeaderLast, with
+// exactly one way to find the leader
+func ValidateOrder(order *storagev1alpha1.ReplicaOrder, path *field.Path) field.ErrorList {
+	if order == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	replicasPath := path.Child("replicas")
+	if order.Type == OrderExplicit {
+		if len(order.Replicas) == 0 {
+			allErrs = append(allErrs, field.Required(replicasPath, fmt.Sprintf("the %s order needs the ordinals", OrderExplicit)))
+		}
+		seen := map[int32]bool{}
+		for i, replica := range order.Replicas {
+			if replica < 0 {
+				allErrs = append(allErrs, field.Invalid(replicasPath.Index(i), replica, "must not be negative"))
+			}
+			if seen[replica] {
+				allErrs = append(allErrs, field.Duplicate(replicasPath.Index(i), replica))
+			}
+			seen[replica] = true
+		}
+	} else if len(order.Replicas) > 0 {
+		allErrs = append(allErrs, field.Forbidden(replicasPath, fmt.Sprintf("only used by the %s order", OrderExplicit)))
+	}
+
+	leaderPath := path.Child("leader")
+	leader := order.Leader
+	switch {
+	case order.Type != OrderLeaderLast:
+		if leader != nil {
+			allErrs = append(allErrs, field.Forbidden(leaderPath, fmt.Sprintf("only used by the %s order", OrderLeaderLast)))
+		}
+	case leader == nil:
+		allErrs = append(allErrs, field.Required(leaderPath, fmt.Sprintf("the %s order needs a way to find the leader", OrderLeaderLast)))
+	case (leader.Selector == nil) == (leader.Exec == nil):
+		allErrs = append(allErrs, field.Invalid(leaderPath, "", "exactly one of selector or exec must be set"))
+	case leader.Selector != nil:
+		if _, err := metav1.LabelSelectorAsSelector(leader.Selector); err != nil {
+			allErrs = append(allErrs, field.Invalid(leaderPath.Child("selector"), leader.Selector.String(), err.Error()))
+		}
+	case len(leader.Exec.Command) == 0:
+		allErrs = append(allErrs, field.Required(leaderPath.Child("exec", "command"), "a command is required"))
+	}
+	return allErrs
+}
+
+// orderReplicas sorts the pending replicas following spec.order. It is done again before every batch, so
+// LeaderLast follows the leader when it moves during the migration.
+func (r *VolumeResizeReconciler) orderReplicas(ctx context.Context, vr *storagev1alpha1.VolumeResize, pending []int32) ([]int32, error) {
+	order := vr.Spec.Order
+	if order == nil {
+		return pending, nil
+	}
+
+	switch order.Type {
+	case OrderDescending:
+		ordered := slices.Clone(pending)
+		slices.Reverse(ordered)
+		return ordered, nil
+	case OrderExplicit:
+		return explicitOrder(pending, order.Replicas), nil
+	case OrderLeaderLast:
+		leader, found, err := r.findLeader(ctx, vr, pending)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			logf.FromContext(ctx).Info("No leader among the pending replicas, migrating them in ascending order")
+			return pending, nil
+		}
+		ordered := slices.DeleteFunc(slices.Clone(pending), func(replica int32) bool { return replica == leader })
+		return append(ordered, leader), nil
+	}
+	return pending, nil
+}
+
+// explicitOrder puts the listed replicas first, in their order, followed by the others in ascending order
+func explicitOrder(pending, listed []int32) []int32 {
+	ordered := []int32{}
+	for _, replica := range listed {
+		if slices.Contains(pending, replica) {
+			ordered = append(ordered, replica)
+		}
+	}
+	for _, replica := range pending {
+		if !slices.Contains(ordered, replica) {
+			ordered = append(ordered, replica)
+		}
+	}
+	return ordered
+}
+
+// findLeader returns the pending replica whose pod matches spec.order.leader. Replicas without a pod cannot
+// lead, and a failing exec only means the pod is not the leader.
+func (r *VolumeResizeReconciler) findLeader(ctx context.Context, vr *storagev1alpha1.VolumeResize, pending []int32) (int32, bool, error) {
+	leader := vr.Spec.Order.Leader
+	if leader == nil {
+		return 0, false, nil
+	}
+	var selector labels.Selector
+	if leader.Selector != nil {
+		var err error
+		if selector, err = metav1.LabelSelectorAsSelector(leader.Selector); err != nil {
+			return 0, false, fmt.Errorf("invalid leader selector: %w", err)
+		}
+	}
+
+	for _, replica := range pending {
+		pod := &corev1.Pod{}
+		podName := getPodName(vr.Spec.StatefulSetName, replica)
+		if err := r.Get(ctx, types.NamespacedName{Namespace: vr.Namespace, Name: podName}, pod); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return 0, false, err
+		}
+
+		if selector != nil {
+			if selector.Matches(labels.Set(pod.Labels)) {
+				return replica, true, nil
+			}
+			continue
+		}
+		if leader.Exec != nil && r.isLeader(ctx, pod, leader.Exec) {
+			return replica, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// isLeader runs the leader probe in the pod, exit code 0 means it leads
+func (r *VolumeResizeReconciler) isLeader(ctx context.Context, pod *corev1.Pod, probe *storagev1alpha1.ExecHealthGate) bool {
+	ctx, cancel := context.WithTimeout(ctx, healthGateTimeout)
+	defer cancel()
+	return r.execInPod(ctx, pod, probe.Container, probe.Command) == nil
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+func orderTestPod(replica string, podLabels map[string]string) *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-sts-" + replica, Namespace: "default", Labels: podLabels}}
+}
+
+func TestValidateOrder(t *testing.T) {
+	path := field.NewPath("spec", "order")
+	assert.Empty(t, ValidateOrder(nil, path))
+	assert.Empty(t, ValidateOrder(&storagev1alpha1.ReplicaOrder{Type: OrderExplicit, Replicas: []int32{2, 0}}, path))
+	assert.Empty(t, ValidateOrder(&storagev1alpha1.ReplicaOrder{
+		Type:   OrderLeaderLast,
+		Leader: &storagev1alpha1.LeaderSelector{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"role": "master"}}},
+	}, path))
+
+	tests := []struct {
+		name     string
+		order    *storagev1alpha1.ReplicaOrder
+		expected string
+	}{
+		{"explicit without replicas", &storagev1alpha1.ReplicaOrder{Type: OrderExplicit}, "spec.order.replicas: Required value"},
+		{"replicas without explicit", &storagev1alpha1.ReplicaOrder{Type: OrderDescending, Replicas: []int32{1}}, "spec.order.replicas: Forbidden"},
+		{"leader last without leader", &storagev1alpha1.ReplicaOrder{Type: OrderLeaderLast}, "spec.order.leader: Required value"},
+		{"leader with both", &storagev1alpha1.ReplicaOrder{Type: OrderLeaderLast, Leader: &storagev1alpha1.LeaderSelector{
+			Selector: &metav1.LabelSelector{},
+			Exec:     &storagev1alpha1.ExecHealthGate{Command: []string{"is-leader"}},
+		}}, "exactly one of selector or exec"},
+		{"invalid selector", &storagev1alpha1.ReplicaOrder{Type: OrderLeaderLast, Leader: &storagev1alpha1.LeaderSelector{
+			Selector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "role", Operator: "Near"}}},
+		}}, "spec.order.leader.selector"},
+	}
+	for _, tt := range tests {
+		errs := ValidateOrder(tt.order, path)
+		require.NotEmpty(t, errs, tt.name)
+		assert.Contains(t, errs.ToAggregate().Error(), tt.expected, tt.name)
+	}
+
+	assert.False(t, validateOrder(&storagev1alpha1.ReplicaOrder{Type: OrderExplicit, Replicas: []int32{3}}, []int32{0, 1, 2}).Valid)
+	assert.True(t, validateOrder(&storagev1alpha1.ReplicaOrder{Type: OrderExplicit, Replicas: []int32{2}}, []int32{0, 1, 2}).Valid)
+}
+
+func TestOrderReplicas(t *testing.T) {
+	vr := controlsTestVR()
+	r, _ := newControlsTestReconciler(t, vr,
+		orderTestPod("0", map[string]string{"role": "replica"}),
+		orderTestPod("1", map[string]string{"role": "master"}),
+	)
+	ctx := context.Background()
+	pending := []int32{0, 1, 2}
+
+	ordered, err := r.orderReplicas(ctx, vr, pending)
+	require.NoError(t, err)
+	assert.Equal(t, []int32{0, 1, 2}, ordered)
+
+	vr.Spec.Order = &storagev1alpha1.ReplicaOrder{Type: OrderDescending}
+	ordered, err = r.orderReplicas(ctx, vr, pending)
+	require.NoError(t, err)
+	assert.Equal(t, []int32{2, 1, 0}, ordered)
+	assert.Equal(t, []int32{0, 1, 2}, pending)
+
+	// Replicas left out, or already migrated, keep their place
+	vr.Spec.Order = &storagev1alpha1.ReplicaOrder{Type: OrderExplicit, Replicas: []int32{2, 3}}
+	ordered, err = r.orderReplicas(ctx, vr, pending)
+	require.NoError(t, err)
+	assert.Equal(t, []int32{2, 0, 1}, ordered)
+
+	vr.Spec.Order = &storagev1alpha1.ReplicaOrder{Type: OrderLeaderLast, Leader: &storagev1alpha1.LeaderSelector{
+		Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"role": "master"}},
+	}}
+	ordered, err = r.orderReplicas(ctx, vr, pending)
+	require.NoError(t, err)
+	assert.Equal(t, []int32{0, 2, 1}, ordered)
+
+	// The exec probe only passes in the leader, a replica without a pod is skipped
+	executor := &fakeExecutor{}
+	r.Executor = executor
+	vr.Spec.Order.Leader = &storagev1alpha1.LeaderSelector{Exec: &storagev1alpha1.ExecHealthGate{Command: []string{"is-leader"}}}
+	ordered, err = r.orderReplicas(ctx, vr, pending)
+	require.NoError(t, err)
+	assert.Equal(t, []int32{1, 2, 0}, ordered)
+	assert.Len(t, executor.calls, 1)
+
+	executor.err = errors.New("command terminated with exit code 1")
+	ordered, err = r.orderReplicas(ctx, vr, pending)
+	require.NoError(t, err)
+	assert.Equal(t, []int32{0, 1, 2}, ordered)
+}
+
+func TestStartNextBatchLeaderLast(t *testing.T) {
+	vr := controlsTestVR()
+	for i := range vr.Status.VolumeStatuses {
+		vr.Status.VolumeStatuses[i].Phase = VolumeStatusPending
+	}
+	vr.Spec.Order = &storagev1alpha1.ReplicaOrder{Type: OrderLeaderLast, Leader: &storagev1alpha1.LeaderSelector{
+		Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"role": "master"}},
+	}}
+	r, _ := newControlsTestReconciler(t, vr, controlsTestSTS(), orderTestPod("0", map[string]string{"role": "master"}))
+	require.NoError(t, policyv1.AddToScheme(r.Scheme))
+	ctx := context.Background()
+
+	_, err := r.startNextBatch(ctx, vr)
+	require.NoError(t, err)
+	assert.Equal(t, []int32{1}, vr.Status.InFlightReplicas)
+}
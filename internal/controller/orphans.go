@@ -0,0 +1,258 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+	"github.com/thomas-maurice/migcontroller/internal/stsbackup"
+)
+
+// Kinds of artifacts left behind by a VolumeResize that no longer exists
+const (
+	OrphanKindBackupConfigMap    = "BackupConfigMap"
+	OrphanKindMissingStatefulSet = "MissingStatefulSet"
+	OrphanKindTempPVC            = "TempPVC"
+	OrphanKindRetainedPV         = "RetainedPV"
+)
+
+var orphanKinds = []string{
+	OrphanKindBackupConfigMap,
+	OrphanKindMissingStatefulSet,
+	OrphanKindTempPVC,
+	OrphanKindRetainedPV,
+}
+
+var orphanedArtifacts = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "volumeresize_orphaned_artifacts",
+		Help: "Number of migration artifacts whose VolumeResize no longer exists, found by the last scan",
+	},
+	[]string{"kind"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(orphanedArtifacts)
+}
+
+// orphanedArtifact is a leftover of a migration whose VolumeResize is gone
+type orphanedArtifact struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Migration string
+	// Object is the artifact the events are attached to; for a missing StatefulSet it is the backup ConfigMap
+	Object client.Object
+}
+
+// OrphanScanner looks for migration artifacts whose VolumeResize no longer exists, at startup and then
+// periodically. This happens when a finalizer was stripped by hand or the CRD was reinstalled.
+type OrphanScanner struct {
+	Client client.Client
+	// APIReader lists without going through the cache, so scanning does not watch every ConfigMap in the cluster
+	APIReader client.Reader
+	Recorder  events.EventRecorder
+	// Interval between scans after the startup one, zero scans only once
+	Interval time.Duration
+	// RecreateStatefulSets recreates a StatefulSet that is missing but still has a backup
+	RecreateStatefulSets bool
+}
+
+// SetupWithManager registers the scanner to run on the leader
+func (s *OrphanScanner) SetupWithManager(mgr ctrl.Manager) error {
+	return mgr.Add(s)
+}
+
+// NeedLeaderElection makes only the leader scan, so recreation is never attempted twice
+func (s *OrphanScanner) NeedLeaderElection() bool {
+	return true
+}
+
+// Start scans once, then on every interval until the context is cancelled
+func (s *OrphanScanner) Start(ctx context.Context) error {
+	log := logf.FromContext(ctx).WithName("orphan-scanner")
+	ctx = logf.IntoContext(ctx, log)
+
+	s.runScan(ctx)
+	if s.Interval <= 0 {
+		return nil
+	}
+
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			s.runScan(ctx)
+		}
+	}
+}
+
+func (s *OrphanScanner) runScan(ctx context.Context) {
+	log := logf.FromContext(ctx)
+
+	artifacts, err := s.Scan(ctx)
+	if err != nil {
+		log.Error(err, "Orphan scan failed")
+		return
+	}
+
+	counts := map[string]int{}
+	for _, a := range artifacts {
+		counts[a.Kind]++
+		log.Info("Found orphaned migration artifact", "kind", a.Kind, "namespace", a.Namespace, "name", a.Name, "migration", a.Migration)
+		s.Recorder.Eventf(a.Object, nil, corev1.EventTypeWarning, "OrphanedMigrationArtifact", "Scan",
+			"%s %s/%s belongs to VolumeResize %s which no longer exists", a.Kind, a.Namespace, a.Name, a.Migration)
+
+		if a.Kind == OrphanKindMissingStatefulSet && s.RecreateStatefulSets {
+			s.recreateStatefulSet(ctx, a)
+		}
+	}
+
+	for _, kind := range orphanKinds {
+		orphanedArtifacts.WithLabelValues(kind).Set(float64(counts[kind]))
+	}
+}
+
+// recreateStatefulSet restores a missing StatefulSet from the backup ConfigMap of the artifact
+func (s *OrphanScanner) recreateStatefulSet(ctx context.Context, a orphanedArtifact) {
+	log := logf.FromContext(ctx)
+
+	cm, ok := a.Object.(*corev1.ConfigMap)
+	if !ok {
+		return
+	}
+	sts, err := stsbackup.Read(ctx, s.APIReader, cm)
+	if err != nil {
+		log.Error(err, "Cannot recreate StatefulSet", "configmap", cm.Name)
+		return
+	}
+
+	if err := recreateSTS(ctx, s.Client, sts); err != nil && !apierrors.IsAlreadyExists(err) {
+		log.Error(err, "Failed to recreate StatefulSet", "statefulset", sts.Name)
+		s.Recorder.Eventf(cm, nil, corev1.EventTypeWarning, "RecreateFailed", "RecreateStatefulSet",
+			"Failed to recreate StatefulSet %s: %v", sts.Name, err)
+		return
+	}
+
+	log.Info("Recreated StatefulSet from orphaned backup", "namespace", sts.Namespace, "statefulset", sts.Name)
+	s.Recorder.Eventf(cm, nil, corev1.EventTypeNormal, "RecreatedStatefulSet", "RecreateStatefulSet",
+		"Recreated StatefulSet %s from backup", sts.Name)
+}
+
+// Scan lists every labelled artifact and returns those whose VolumeResize no longer exists. Deleting a
+// VolumeResize removes its backup, so a backup only outlives a migration that stopped part-way.
+func (s *OrphanScanner) Scan(ctx context.Context) ([]orphanedArtifact, error) {
+	vrList := &storagev1alpha1.VolumeResizeList{}
+	if err := s.APIReader.List(ctx, vrList); err != nil {
+		return nil, fmt.Errorf("failed to list VolumeResizes: %w", err)
+	}
+	live := map[types.NamespacedName]bool{}
+	liveNames := map[string]bool{}
+	for _, vr := range vrList.Items {
+		live[types.NamespacedName{Namespace: vr.Namespace, Name: vr.Name}] = true
+		liveNames[vr.Name] = true
+	}
+	isOrphan := func(namespace, migration string) bool {
+		return !live[types.NamespacedName{Namespace: namespace, Name: migration}]
+	}
+
+	artifacts := []orphanedArtifact{}
+
+	cmList := &corev1.ConfigMapList{}
+	if err := s.APIReader.List(ctx, cmList, client.HasLabels{LabelMigrationName}); err != nil {
+		return nil, fmt.Errorf("failed to list ConfigMaps: %w", err)
+	}
+	for i := range cmList.Items {
+		cm := &cmList.Items[i]
+		migration := cm.Labels[LabelMigrationName]
+		// Chunks are reported through their backup
+		if stsbackup.IsChunk(cm) || !isOrphan(cm.Namespace, migration) {
+			continue
+		}
+		artifacts = append(artifacts, orphanedArtifact{
+			Kind: OrphanKindBackupConfigMap, Namespace: cm.Namespace, Name: cm.Name, Migration: migration, Object: cm,
+		})
+
+		sts, err := stsbackup.Read(ctx, s.APIReader, cm)
+		if err != nil {
+			continue
+		}
+		err = s.APIReader.Get(ctx, types.NamespacedName{Namespace: sts.Namespace, Name: sts.Name}, &appsv1.StatefulSet{})
+		if apierrors.IsNotFound(err) {
+			artifacts = append(artifacts, orphanedArtifact{
+				Kind: OrphanKindMissingStatefulSet, Namespace: sts.Namespace, Name: sts.Name, Migration: migration, Object: cm,
+			})
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to get StatefulSet %s: %w", sts.Name, err)
+		}
+	}
+
+	pvcList := &corev1.PersistentVolumeClaimList{}
+	if err := s.APIReader.List(ctx, pvcList, client.HasLabels{LabelMigrationName}); err != nil {
+		return nil, fmt.Errorf("failed to list PVCs: %w", err)
+	}
+	for i := range pvcList.Items {
+		pvc := &pvcList.Items[i]
+		migration := pvc.Labels[LabelMigrationName]
+		if isOrphan(pvc.Namespace, migration) {
+			artifacts = append(artifacts, orphanedArtifact{
+				Kind: OrphanKindTempPVC, Namespace: pvc.Namespace, Name: pvc.Name, Migration: migration, Object: pvc,
+			})
+		}
+	}
+
+	pvList := &corev1.PersistentVolumeList{}
+	if err := s.APIReader.List(ctx, pvList, client.HasLabels{LabelMigrationName}); err != nil {
+		return nil, fmt.Errorf("failed to list PVs: %w", err)
+	}
+	for i := range pvList.Items {
+		pv := &pvList.Items[i]
+		if pv.Status.Phase == corev1.VolumeBound {
+			continue
+		}
+		migration := pv.Labels[LabelMigrationName]
+		// PVs are cluster scoped, the namespace comes from the claim they were last bound to
+		orphan := !liveNames[migration]
+		if pv.Spec.ClaimRef != nil {
+			orphan = isOrphan(pv.Spec.ClaimRef.Namespace, migration)
+		}
+		if orphan {
+			artifacts = append(artifacts, orphanedArtifact{
+				Kind: OrphanKindRetainedPV, Name: pv.Name, Migration: migration, Object: pv,
+			})
+		}
+	}
+
+	return artifacts, nil
+}
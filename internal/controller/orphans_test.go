@@ -0,0 +1,106 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+func orphanTestObjects() []client.Object {
+	labelled := func(migration string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Labels: map[string]string{LabelMigrationName: migration}}
+	}
+
+	tempPVC := &corev1.PersistentVolumeClaim{ObjectMeta: labelled("gone")}
+	tempPVC.Name, tempPVC.Namespace = "data-test-sts-0-new", "default"
+
+	liveTempPVC := &corev1.PersistentVolumeClaim{ObjectMeta: labelled("test-resize")}
+	liveTempPVC.Name, liveTempPVC.Namespace = "data-test-sts-1-new", "default"
+
+	releasedPV := &corev1.PersistentVolume{
+		ObjectMeta: labelled("gone"),
+		Spec:       corev1.PersistentVolumeSpec{ClaimRef: &corev1.ObjectReference{Namespace: "default", Name: "data-test-sts-0"}},
+		Status:     corev1.PersistentVolumeStatus{Phase: corev1.VolumeReleased},
+	}
+	releasedPV.Name = "pv-released"
+
+	boundPV := &corev1.PersistentVolume{
+		ObjectMeta: labelled("gone"),
+		Status:     corev1.PersistentVolumeStatus{Phase: corev1.VolumeBound},
+	}
+	boundPV.Name = "pv-bound"
+
+	return []client.Object{controlsTestVR(), tempPVC, liveTempPVC, releasedPV, boundPV}
+}
+
+func TestOrphanScannerFindsArtifacts(t *testing.T) {
+	_, c := newControlsTestReconciler(t, orphanTestObjects()...)
+	ctx := context.Background()
+
+	gone := &storagev1alpha1.VolumeResize{ObjectMeta: metav1.ObjectMeta{Name: "gone", Namespace: "default"}}
+	require.NoError(t, backupSTSToConfigMap(ctx, c, gone, controlsTestSTS()))
+	require.NoError(t, backupSTSToConfigMap(ctx, c, controlsTestVR(), controlsTestSTS()))
+
+	s := &OrphanScanner{Client: c, APIReader: c, Recorder: events.NewFakeRecorder(10)}
+	artifacts, err := s.Scan(ctx)
+	require.NoError(t, err)
+
+	found := map[string]string{}
+	for _, a := range artifacts {
+		assert.Equal(t, "gone", a.Migration)
+		found[a.Kind] = a.Name
+	}
+	assert.Equal(t, map[string]string{
+		OrphanKindBackupConfigMap:    "gone-sts-backup",
+		OrphanKindMissingStatefulSet: "test-sts",
+		OrphanKindTempPVC:            "data-test-sts-0-new",
+		OrphanKindRetainedPV:         "pv-released",
+	}, found)
+}
+
+func TestOrphanScannerRecreatesStatefulSet(t *testing.T) {
+	_, c := newControlsTestReconciler(t)
+	ctx := context.Background()
+
+	gone := &storagev1alpha1.VolumeResize{ObjectMeta: metav1.ObjectMeta{Name: "gone", Namespace: "default"}}
+	require.NoError(t, backupSTSToConfigMap(ctx, c, gone, controlsTestSTS()))
+
+	recorder := events.NewFakeRecorder(10)
+	s := &OrphanScanner{Client: c, APIReader: c, Recorder: recorder, RecreateStatefulSets: true}
+	s.runScan(ctx)
+
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-sts"}, &appsv1.StatefulSet{}))
+	assert.Len(t, recorder.Events, 3)
+
+	// Once recreated, only the backup is left over
+	artifacts, err := s.Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, artifacts, 1)
+	assert.Equal(t, OrphanKindBackupConfigMap, artifacts[0].Kind)
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+// knownParentPauses are the built-in hooks for operators that own StatefulSets, keyed by group/kind
+var knownParentPauses = map[string]storagev1alpha1.ParentPauseHook{
+	"kafka.strimzi.io/Kafka": {
+		Type:            ParentPauseTypeAnnotation,
+		AnnotationKey:   "strimzi.io/pause-reconciliation",
+		AnnotationValue: "true",
+	},
+	"elasticsearch.k8s.elastic.co/Elasticsearch": {
+		Type:            ParentPauseTypeAnnotation,
+		AnnotationKey:   "eck.k8s.elastic.co/managed",
+		AnnotationValue: "false",
+	},
+}
+
+// resolveParentPause returns the controller owner of the StatefulSet and the hook pausing it.
+// Both are nil when the StatefulSet has no controller. An explicit hook wins over the built-in ones.
+func resolveParentPause(sts *appsv1.StatefulSet, hook *storagev1alpha1.ParentPauseHook) (*metav1.OwnerReference, *storagev1alpha1.ParentPauseHook, error) {
+	owner := metav1.GetControllerOf(sts)
+	if owner == nil {
+		return nil, nil, nil
+	}
+
+	if hook == nil {
+		gv, err := schema.ParseGroupVersion(owner.APIVersion)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid apiVersion %q on the owner of StatefulSet %s: %w", owner.APIVersion, sts.Name, err)
+		}
+		known, ok := knownParentPauses[gv.Group+"/"+owner.Kind]
+		if !ok {
+			return nil, nil, fmt.Errorf("StatefulSet %s is controlled by %s %s which has no built-in pause hook; "+
+				"set spec.parentPause to pause it, or its type to None to migrate anyway", sts.Name, owner.Kind, owner.Name)
+		}
+		hook = &known
+	}
+
+	switch hook.Type {
+	case ParentPauseTypeAnnotation:
+		if hook.AnnotationKey == "" {
+			return nil, nil, fmt.Errorf("parentPause type Annotation requires annotationKey")
+		}
+	case ParentPauseTypeScaleDown:
+		if hook.Deployment == nil || hook.Deployment.Name == "" {
+			return nil, nil, fmt.Errorf("parentPause type ScaleDown requires deployment.name")
+		}
+	}
+	return owner, hook, nil
+}
+
+// pauseParent applies the parentPause hook before the StatefulSet is deleted, so its owner does not recreate it.
+// What was changed is persisted in the status first, so a crash cannot lose track of it.
+func (r *VolumeResizeReconciler) pauseParent(ctx context.Context, vr *storagev1alpha1.VolumeResize, sts *appsv1.StatefulSet) error {
+	if vr.Status.PausedParent != nil {
+		return nil
+	}
+	owner, hook, err := resolveParentPause(sts, vr.Spec.ParentPause)
+	if err != nil {
+		return err
+	}
+	if owner == nil || hook.Type == ParentPauseTypeNone {
+		return nil
+	}
+
+	var paused *storagev1alpha1.PausedParent
+	switch hook.Type {
+	case ParentPauseTypeAnnotation:
+		paused, err = annotateParent(ctx, r.Client, sts.Namespace, owner, hook)
+	case ParentPauseTypeScaleDown:
+		paused, err = scaleDownDeployment(ctx, r.Client, vr.Namespace, hook.Deployment)
+	}
+	if err != nil {
+		return err
+	}
+
+	logf.FromContext(ctx).Info("Paused StatefulSet owner", "type", paused.Type, "kind", paused.Kind, "name", paused.Name)
+	vr.Status.PausedParent = paused
+	return r.Status().Update(ctx, vr)
+}
+
+// annotateParent sets the pause annotation on the owner and records its previous value
+func annotateParent(ctx context.Context, c client.Client, namespace string, owner *metav1.OwnerReference, hook *storagev1alpha1.ParentPauseHook) (*storagev1alpha1.PausedParent, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(owner.APIVersion)
+	obj.SetKind(owner.Kind)
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: owner.Name}, obj); err != nil {
+		return nil, fmt.Errorf("failed to get owner %s %s: %w", owner.Kind, owner.Name, err)
+	}
+
+	paused := &storagev1alpha1.PausedParent{
+		Type:          ParentPauseTypeAnnotation,
+		APIVersion:    owner.APIVersion,
+		Kind:          owner.Kind,
+		Namespace:     namespace,
+		Name:          owner.Name,
+		AnnotationKey: hook.AnnotationKey,
+	}
+	annotations := obj.GetAnnotations()
+	if previous, ok := annotations[hook.AnnotationKey]; ok {
+		paused.PreviousAnnotationValue = &previous
+	}
+
+	patch := client.MergeFrom(obj.DeepCopy())
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[hook.AnnotationKey] = hook.AnnotationValue
+	obj.SetAnnotations(annotations)
+	if err := c.Patch(ctx, obj, patch); err != nil {
+		return nil, fmt.Errorf("failed to annotate owner %s %s: %w", owner.Kind, owner.Name, err)
+	}
+	return paused, nil
+}
+
+// scaleDownDeployment scales the operator Deployment to zero and records its replica count
+func scaleDownDeployment(ctx context.Context, c client.Client, defaultNamespace string, ref *storagev1alpha1.DeploymentReference) (*storagev1alpha1.PausedParent, error) {
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+
+	deploy := &appsv1.Deployment{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, deploy); err != nil {
+		return nil, fmt.Errorf("failed to get Deployment %s: %w", ref.Name, err)
+	}
+
+	previous := int32(1)
+	if deploy.Spec.Replicas != nil {
+		previous = *deploy.Spec.Replicas
+	}
+
+	patch := client.MergeFrom(deploy.DeepCopy())
+	deploy.Spec.Replicas = new(int32)
+	if err := c.Patch(ctx, deploy, patch); err != nil {
+		return nil, fmt.Errorf("failed to scale down Deployment %s: %w", ref.Name, err)
+	}
+
+	return &storagev1alpha1.PausedParent{
+		Type:             ParentPauseTypeScaleDown,
+		APIVersion:       "apps/v1",
+		Kind:             "Deployment",
+		Namespace:        namespace,
+		Name:             ref.Name,
+		PreviousReplicas: &previous,
+	}, nil
+}
+
+// resumeParent undoes pauseParent once the StatefulSet is back. The caller persists the status.
+func (r *VolumeResizeReconciler) resumeParent(ctx context.Context, vr *storagev1alpha1.VolumeResize) error {
+	paused := vr.Status.PausedParent
+	if paused == nil {
+		return nil
+	}
+
+	switch paused.Type {
+	case ParentPauseTypeAnnotation:
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(paused.APIVersion)
+		obj.SetKind(paused.Kind)
+		err := r.Get(ctx, types.NamespacedName{Namespace: paused.Namespace, Name: paused.Name}, obj)
+		if apierrors.IsNotFound(err) {
+			return r.forgetDeletedParent(ctx, vr)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get owner %s %s: %w", paused.Kind, paused.Name, err)
+		}
+		patch := client.MergeFrom(obj.DeepCopy())
+		annotations := obj.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		if paused.PreviousAnnotationValue != nil {
+			annotations[paused.AnnotationKey] = *paused.PreviousAnnotationValue
+		} else {
+			delete(annotations, paused.AnnotationKey)
+		}
+		obj.SetAnnotations(annotations)
+		if err := r.Patch(ctx, obj, patch); err != nil {
+			return fmt.Errorf("failed to remove pause annotation from %s %s: %w", paused.Kind, paused.Name, err)
+		}
+
+	case ParentPauseTypeScaleDown:
+		deploy := &appsv1.Deployment{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: paused.Namespace, Name: paused.Name}, deploy)
+		if apierrors.IsNotFound(err) {
+			return r.forgetDeletedParent(ctx, vr)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get Deployment %s: %w", paused.Name, err)
+		}
+		patch := client.MergeFrom(deploy.DeepCopy())
+		deploy.Spec.Replicas = paused.PreviousReplicas
+		if err := r.Patch(ctx, deploy, patch); err != nil {
+			return fmt.Errorf("failed to scale up Deployment %s: %w", paused.Name, err)
+		}
+	}
+
+	logf.FromContext(ctx).Info("Resumed StatefulSet owner", "type", paused.Type, "kind", paused.Kind, "name", paused.Name)
+	vr.Status.PausedParent = nil
+	return nil
+}
+
+// forgetDeletedParent clears the paused owner once it was deleted, there is nothing left to resume
+func (r *VolumeResizeReconciler) forgetDeletedParent(ctx context.Context, vr *storagev1alpha1.VolumeResize) error {
+	paused := vr.Status.PausedParent
+	logf.FromContext(ctx).Info("StatefulSet owner is gone, nothing to resume", "kind", paused.Kind, "name", paused.Name)
+	vr.Status.PausedParent = nil
+	return nil
+}
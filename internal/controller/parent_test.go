@@ -0,0 +1,190 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+var kafkaGVK = schema.GroupVersionKind{Group: "kafka.strimzi.io", Version: "v1beta2", Kind: "Kafka"}
+
+func ownedSTS(apiVersion, kind string) *appsv1.StatefulSet {
+	controller := true
+	sts := controlsTestSTS()
+	sts.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: apiVersion,
+		Kind:       kind,
+		Name:       "my-cluster",
+		UID:        "owner-uid",
+		Controller: &controller,
+	}}
+	return sts
+}
+
+func TestResolveParentPause(t *testing.T) {
+	owner, hook, err := resolveParentPause(controlsTestSTS(), nil)
+	require.NoError(t, err)
+	assert.Nil(t, owner)
+	assert.Nil(t, hook)
+
+	owner, hook, err = resolveParentPause(ownedSTS("kafka.strimzi.io/v1beta2", "Kafka"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "my-cluster", owner.Name)
+	assert.Equal(t, "strimzi.io/pause-reconciliation", hook.AnnotationKey)
+
+	_, _, err = resolveParentPause(ownedSTS("acid.zalan.do/v1", "postgresql"), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no built-in pause hook")
+
+	_, hook, err = resolveParentPause(ownedSTS("acid.zalan.do/v1", "postgresql"), &storagev1alpha1.ParentPauseHook{Type: ParentPauseTypeNone})
+	require.NoError(t, err)
+	assert.Equal(t, ParentPauseTypeNone, hook.Type)
+
+	_, _, err = resolveParentPause(ownedSTS("acid.zalan.do/v1", "postgresql"), &storagev1alpha1.ParentPauseHook{Type: ParentPauseTypeScaleDown})
+	require.Error(t, err)
+}
+
+func TestPauseAndResumeParentAnnotation(t *testing.T) {
+	kafka := &unstructured.Unstructured{}
+	kafka.SetGroupVersionKind(kafkaGVK)
+	kafka.SetName("my-cluster")
+	kafka.SetNamespace("default")
+
+	vr := controlsTestVR()
+	r, c := newControlsTestReconciler(t, vr)
+	r.Scheme.AddKnownTypeWithName(kafkaGVK, &unstructured.Unstructured{})
+	ctx := context.Background()
+	require.NoError(t, c.Create(ctx, kafka))
+
+	require.NoError(t, r.pauseParent(ctx, vr, ownedSTS("kafka.strimzi.io/v1beta2", "Kafka")))
+	require.NotNil(t, vr.Status.PausedParent)
+	assert.Nil(t, vr.Status.PausedParent.PreviousAnnotationValue)
+
+	got := &unstructured.Unstructured{}
+	got.SetGroupVersionKind(kafkaGVK)
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "my-cluster"}, got))
+	assert.Equal(t, "true", got.GetAnnotations()["strimzi.io/pause-reconciliation"])
+
+	require.NoError(t, r.resumeParent(ctx, vr))
+	assert.Nil(t, vr.Status.PausedParent)
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "my-cluster"}, got))
+	assert.NotContains(t, got.GetAnnotations(), "strimzi.io/pause-reconciliation")
+}
+
+func TestPauseAndResumeParentScaleDown(t *testing.T) {
+	operator := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "postgres-operator", Namespace: "operators"},
+		Spec:       appsv1.DeploymentSpec{Replicas: ptrInt32(2)},
+	}
+	vr := controlsTestVR()
+	vr.Spec.ParentPause = &storagev1alpha1.ParentPauseHook{
+		Type:       ParentPauseTypeScaleDown,
+		Deployment: &storagev1alpha1.DeploymentReference{Name: "postgres-operator", Namespace: "operators"},
+	}
+	r, c := newControlsTestReconciler(t, vr, operator)
+	ctx := context.Background()
+
+	require.NoError(t, r.pauseParent(ctx, vr, ownedSTS("acid.zalan.do/v1", "postgresql")))
+	got := &appsv1.Deployment{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "operators", Name: "postgres-operator"}, got))
+	assert.Equal(t, int32(0), *got.Spec.Replicas)
+
+	// Pausing again is a no-op, the recorded replica count is kept
+	require.NoError(t, r.pauseParent(ctx, vr, ownedSTS("acid.zalan.do/v1", "postgresql")))
+	assert.Equal(t, int32(2), *vr.Status.PausedParent.PreviousReplicas)
+
+	require.NoError(t, r.resumeParent(ctx, vr))
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "operators", Name: "postgres-operator"}, got))
+	assert.Equal(t, int32(2), *got.Spec.Replicas)
+}
+
+func TestResumeParentAlreadyDeleted(t *testing.T) {
+	vr := controlsTestVR()
+	vr.Spec.ParentPause = &storagev1alpha1.ParentPauseHook{
+		Type:       ParentPauseTypeScaleDown,
+		Deployment: &storagev1alpha1.DeploymentReference{Name: "postgres-operator", Namespace: "operators"},
+	}
+	vr.Status.PausedParent = &storagev1alpha1.PausedParent{
+		Type:             ParentPauseTypeScaleDown,
+		APIVersion:       "apps/v1",
+		Kind:             "Deployment",
+		Namespace:        "operators",
+		Name:             "postgres-operator",
+		PreviousReplicas: ptrInt32(2),
+	}
+	r, _ := newControlsTestReconciler(t, vr)
+	ctx := context.Background()
+
+	// The Deployment was deleted while paused, there is nothing to resume and the finalizer must not hang
+	require.NoError(t, r.resumeParent(ctx, vr))
+	assert.Nil(t, vr.Status.PausedParent)
+
+	vr.Status.PausedParent = &storagev1alpha1.PausedParent{
+		Type:          ParentPauseTypeAnnotation,
+		APIVersion:    "kafka.strimzi.io/v1beta2",
+		Kind:          "Kafka",
+		Namespace:     "default",
+		Name:          "my-cluster",
+		AnnotationKey: "strimzi.io/pause-reconciliation",
+	}
+	r.Scheme.AddKnownTypeWithName(kafkaGVK, &unstructured.Unstructured{})
+	require.NoError(t, r.resumeParent(ctx, vr))
+	assert.Nil(t, vr.Status.PausedParent)
+}
+
+func TestResumeParentWithoutAnnotations(t *testing.T) {
+	kafka := &unstructured.Unstructured{}
+	kafka.SetGroupVersionKind(kafkaGVK)
+	kafka.SetName("my-cluster")
+	kafka.SetNamespace("default")
+
+	vr := controlsTestVR()
+	previous := "false"
+	// The owner lost every annotation while the migration ran
+	vr.Status.PausedParent = &storagev1alpha1.PausedParent{
+		Type:                    ParentPauseTypeAnnotation,
+		APIVersion:              "kafka.strimzi.io/v1beta2",
+		Kind:                    "Kafka",
+		Namespace:               "default",
+		Name:                    "my-cluster",
+		AnnotationKey:           "strimzi.io/pause-reconciliation",
+		PreviousAnnotationValue: &previous,
+	}
+	r, c := newControlsTestReconciler(t, vr)
+	r.Scheme.AddKnownTypeWithName(kafkaGVK, &unstructured.Unstructured{})
+	ctx := context.Background()
+	require.NoError(t, c.Create(ctx, kafka))
+
+	require.NoError(t, r.resumeParent(ctx, vr))
+	assert.Nil(t, vr.Status.PausedParent)
+
+	got := &unstructured.Unstructured{}
+	got.SetGroupVersionKind(kafkaGVK)
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "my-cluster"}, got))
+	assert.Equal(t, "false", got.GetAnnotations()["strimzi.io/pause-reconciliation"])
+}
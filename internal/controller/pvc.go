@@ -52,8 +52,14 @@ func getPVC(ctx context.Context, c client.Client, namespace, name string) (*core
 	return pvc, nil
 }
 
-// createTempPVC creates a temporary PVC for migration with the new size
-func createTempPVC(ctx context.Context, c client.Client, vr *storagev1alpha1.VolumeResize, vol storagev1alpha1.VolumeResizeTarget, originalPVC *corev1.PersistentVolumeClaim, replica int32) (*corev1.PersistentVolumeClaim, error) {
+// isBlockPVC returns true if the PVC is attached as a raw block device
+func isBlockPVC(pvc *corev1.PersistentVolumeClaim) bool {
+	return pvc.Spec.VolumeMode != nil && *pvc.Spec.VolumeMode == corev1.PersistentVolumeBlock
+}
+
+// createTempPVC creates a temporary PVC for migration with the new size.
+// When selectedNode is set the claim is provisioned in that node's topology.
+func createTempPVC(ctx context.Context, c client.Client, vr *storagev1alpha1.VolumeResize, vol storagev1alpha1.VolumeResizeTarget, originalPVC *corev1.PersistentVolumeClaim, replica int32, selectedNode string) (*corev1.PersistentVolumeClaim, error) {
 	tempPVCName := getTempPVCName(vol.Name, vr.Spec.StatefulSetName, replica)
 
 	// Check if temp PVC already exists (idempotency)
@@ -88,6 +94,7 @@ func createTempPVC(ctx context.Context, c client.Client, vr *storagev1alpha1.Vol
 		Spec: corev1.PersistentVolumeClaimSpec{
 			AccessModes:      originalPVC.Spec.AccessModes,
 			StorageClassName: storageClassName,
+			VolumeMode:       originalPVC.Spec.VolumeMode,
 			Resources: corev1.VolumeResourceRequirements{
 				Requests: corev1.ResourceList{
 					corev1.ResourceStorage: vol.NewSize,
@@ -96,6 +103,12 @@ func createTempPVC(ctx context.Context, c client.Client, vr *storagev1alpha1.Vol
 		},
 	}
 
+	// Same hint the scheduler gives WaitForFirstConsumer claims, so the new volume
+	// lands where the migrator pod can attach the old one
+	if selectedNode != "" {
+		tempPVC.Annotations[AnnotationSelectedNode] = selectedNode
+	}
+
 	if err := c.Create(ctx, tempPVC); err != nil {
 		return nil, fmt.Errorf("failed to create temp PVC: %w", err)
 	}
@@ -123,6 +136,30 @@ func setRetainOnPV(ctx context.Context, c client.Client, pvName string) error {
 	return nil
 }
 
+// labelPVForMigration tags a retained PV with the migration it belongs to, so leftovers can be found later
+func labelPVForMigration(ctx context.Context, c client.Client, pvName, vrName string) error {
+	pv := &corev1.PersistentVolume{}
+	if err := c.Get(ctx, types.NamespacedName{Name: pvName}, pv); err != nil {
+		return fmt.Errorf("failed to get PV %s: %w", pvName, err)
+	}
+	if pv.Labels[LabelMigrationName] == vrName {
+		return nil
+	}
+
+	if pv.Labels == nil {
+		pv.Labels = map[string]string{}
+	}
+	if pv.Annotations == nil {
+		pv.Annotations = map[string]string{}
+	}
+	pv.Labels[LabelMigrationName] = vrName
+	pv.Annotations[AnnotationManagedBy] = "volume-resize-operator"
+	if err := c.Update(ctx, pv); err != nil {
+		return fmt.Errorf("failed to label PV %s: %w", pvName, err)
+	}
+	return nil
+}
+
 // replacePVC replaces the original PVC with a new one bound to the new PV
 func replacePVC(ctx context.Context, c client.Client, vr *storagev1alpha1.VolumeResize, vol storagev1alpha1.VolumeResizeTarget, replica int32) error {
 	originalPVCName := getOriginalPVCName(vol.Name, vr.Spec.StatefulSetName, replica)
@@ -153,6 +190,14 @@ func replacePVC(ctx context.Context, c client.Client, vr *storagev1alpha1.Volume
 		return fmt.Errorf("failed to get new PV: %w", err)
 	}
 	newPV.Spec.PersistentVolumeReclaimPolicy = corev1.PersistentVolumeReclaimRetain
+	if newPV.Labels == nil {
+		newPV.Labels = map[string]string{}
+	}
+	if newPV.Annotations == nil {
+		newPV.Annotations = map[string]string{}
+	}
+	newPV.Labels[LabelMigrationName] = vr.Name
+	newPV.Annotations[AnnotationManagedBy] = "volume-resize-operator"
 	if err := c.Update(ctx, newPV); err != nil {
 		return fmt.Errorf("failed to set retain policy on new PV: %w", err)
 	}
@@ -218,6 +263,7 @@ func replacePVC(ctx context.Context, c client.Client, vr *storagev1alpha1.Volume
 		Spec: corev1.PersistentVolumeClaimSpec{
 			AccessModes:      tempPVC.Spec.AccessModes,
 			StorageClassName: tempPVC.Spec.StorageClassName,
+			VolumeMode:       tempPVC.Spec.VolumeMode,
 			VolumeName:       newPVName,
 			Resources: corev1.VolumeResourceRequirements{
 				Requests: corev1.ResourceList{
@@ -233,3 +279,84 @@ func replacePVC(ctx context.Context, c client.Client, vr *storagev1alpha1.Volume
 
 	return nil
 }
+
+// bindPVCToPV creates a PVC bound to an existing PV, releasing the PV from its previous claim first.
+// The claim mirrors the PV so the binder accepts it as a match.
+func bindPVCToPV(ctx context.Context, c client.Client, namespace, pvcName string, pv *corev1.PersistentVolume) error {
+	if pv.Spec.ClaimRef != nil {
+		pv.Spec.ClaimRef = nil
+		if err := c.Update(ctx, pv); err != nil {
+			return fmt.Errorf("failed to clear claimRef on PV %s: %w", pv.Name, err)
+		}
+	}
+
+	var storageClassName *string
+	if pv.Spec.StorageClassName != "" {
+		storageClassName = &pv.Spec.StorageClassName
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pvcName,
+			Namespace: namespace,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      pv.Spec.AccessModes,
+			StorageClassName: storageClassName,
+			VolumeMode:       pv.Spec.VolumeMode,
+			VolumeName:       pv.Name,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: pv.Spec.Capacity[corev1.ResourceStorage],
+				},
+			},
+		},
+	}
+
+	if err := c.Create(ctx, pvc); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create PVC %s bound to PV %s: %w", pvcName, pv.Name, err)
+	}
+	return nil
+}
+
+// restoreOldPVC binds a migrated PVC back to the PV it had before the migration. The PV holding the
+// copied data was set to Retain by replacePVC and is left behind, labeled with the migration.
+func restoreOldPVC(ctx context.Context, c client.Client, namespace string, vs storagev1alpha1.VolumeStatus) error {
+	if vs.OldPVName == "" {
+		return fmt.Errorf("no old PV is recorded for PVC %s", vs.OldPVCName)
+	}
+
+	pvc, err := getPVC(ctx, c, namespace, vs.OldPVCName)
+	switch {
+	case err == nil && pvc.Spec.VolumeName == vs.OldPVName:
+		return nil
+	case err == nil:
+		if err := c.Delete(ctx, pvc); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete PVC %s: %w", vs.OldPVCName, err)
+		}
+	case !apierrors.IsNotFound(err):
+		return fmt.Errorf("failed to get PVC %s: %w", vs.OldPVCName, err)
+	}
+
+	// Wait for the PVC to be fully deleted
+	for range 60 {
+		_, err := getPVC(ctx, c, namespace, vs.OldPVCName)
+		if apierrors.IsNotFound(err) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("error checking PVC deletion: %w", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+
+	pv, err := getPV(ctx, c, vs.OldPVName)
+	if err != nil {
+		return fmt.Errorf("failed to get old PV %s: %w", vs.OldPVName, err)
+	}
+	return bindPVCToPV(ctx, c, namespace, vs.OldPVCName, pv)
+}
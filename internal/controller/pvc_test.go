@@ -108,7 +108,7 @@ func TestCreateTempPVC(t *testing.T) {
 	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(originalPVC).Build()
 	ctx := context.Background()
 
-	tempPVC, err := createTempPVC(ctx, c, vr, vol, originalPVC, 0)
+	tempPVC, err := createTempPVC(ctx, c, vr, vol, originalPVC, 0, "")
 	require.NoError(t, err)
 	assert.NotNil(t, tempPVC)
 	assert.Equal(t, "data-test-sts-0-new", tempPVC.Name)
@@ -117,6 +117,88 @@ func TestCreateTempPVC(t *testing.T) {
 	assert.Equal(t, "test-resize", tempPVC.Labels[LabelMigrationName])
 }
 
+func TestCreateTempPVCKeepsBlockVolumeMode(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, storagev1alpha1.AddToScheme(scheme))
+
+	blockMode := corev1.PersistentVolumeBlock
+	originalPVC := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "data-test-sts-0",
+			Namespace: "default",
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			VolumeMode:  &blockMode,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse("1Gi"),
+				},
+			},
+		},
+	}
+
+	vr := &storagev1alpha1.VolumeResize{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-resize",
+			Namespace: "default",
+		},
+		Spec: storagev1alpha1.VolumeResizeSpec{
+			StatefulSetName: "test-sts",
+		},
+	}
+
+	vol := storagev1alpha1.VolumeResizeTarget{
+		Name:    "data",
+		NewSize: resource.MustParse("500Mi"),
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(originalPVC).Build()
+	ctx := context.Background()
+
+	tempPVC, err := createTempPVC(ctx, c, vr, vol, originalPVC, 0, "")
+	require.NoError(t, err)
+	require.NotNil(t, tempPVC.Spec.VolumeMode)
+	assert.Equal(t, corev1.PersistentVolumeBlock, *tempPVC.Spec.VolumeMode)
+	assert.True(t, isBlockPVC(tempPVC))
+}
+
+func TestCreateTempPVCSelectedNode(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, storagev1alpha1.AddToScheme(scheme))
+
+	originalPVC := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "data-test-sts-0",
+			Namespace: "default",
+		},
+	}
+
+	vr := &storagev1alpha1.VolumeResize{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-resize",
+			Namespace: "default",
+		},
+		Spec: storagev1alpha1.VolumeResizeSpec{
+			StatefulSetName: "test-sts",
+		},
+	}
+
+	vol := storagev1alpha1.VolumeResizeTarget{
+		Name:    "data",
+		NewSize: resource.MustParse("500Mi"),
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(originalPVC).Build()
+	ctx := context.Background()
+
+	tempPVC, err := createTempPVC(ctx, c, vr, vol, originalPVC, 0, "node-a")
+	require.NoError(t, err)
+	assert.Equal(t, "node-a", tempPVC.Annotations[AnnotationSelectedNode])
+}
+
 func TestCreateTempPVCIdempotent(t *testing.T) {
 	scheme := runtime.NewScheme()
 	require.NoError(t, corev1.AddToScheme(scheme))
@@ -164,7 +246,7 @@ func TestCreateTempPVCIdempotent(t *testing.T) {
 	ctx := context.Background()
 
 	// Should return existing PVC without error
-	tempPVC, err := createTempPVC(ctx, c, vr, vol, originalPVC, 0)
+	tempPVC, err := createTempPVC(ctx, c, vr, vol, originalPVC, 0, "")
 	require.NoError(t, err)
 	assert.NotNil(t, tempPVC)
 	assert.Equal(t, "data-test-sts-0-new", tempPVC.Name)
@@ -0,0 +1,189 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+// maxReplicaOrdinal bounds spec.replicas, so a range cannot make the webhook or the controller expand
+// billions of ordinals
+const maxReplicaOrdinal = 9999
+
+// parseReplicaRange parses an ordinal like "2" or an inclusive range like "3-5"
+func parseReplicaRange(spec string) (int64, int64, error) {
+	first, last, isRange := strings.Cut(spec, "-")
+	from, err := strconv.ParseInt(first, 10, 32)
+	if err != nil || from < 0 {
+		return 0, 0, fmt.Errorf("%q is not an ordinal or a range of ordinals", spec)
+	}
+	to := from
+	if isRange {
+		to, err = strconv.ParseInt(last, 10, 32)
+		if err != nil || to < 0 {
+			return 0, 0, fmt.Errorf("%q is not an ordinal or a range of ordinals", spec)
+		}
+		if to < from {
+			return 0, 0, fmt.Errorf("range %q ends before it starts", spec)
+		}
+	}
+	if to > maxReplicaOrdinal {
+		return 0, 0, fmt.Errorf("%q selects ordinals above %d", spec, maxReplicaOrdinal)
+	}
+	return from, to, nil
+}
+
+// ParseReplicas returns the ordinals selected by spec.replicas, sorted and without duplicates
+func ParseReplicas(specs []string) ([]int32, error) {
+	ordinals := []int32{}
+	for _, spec := range specs {
+		from, to, err := parseReplicaRange(spec)
+		if err != nil {
+			return nil, err
+		}
+		for replica := from; replica <= to; replica++ {
+			ordinals = append(ordinals, int32(replica))
+		}
+	}
+	slices.Sort(ordinals)
+	return slices.Compact(ordinals), nil
+}
+
+// ValidateReplicas checks every entry of spec.replicas is an ordinal or a range, and that no ordinal is
+// selected twice
+func ValidateReplicas(specs []string, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	seen := map[int64]bool{}
+	for i, spec := range specs {
+		from, to, err := parseReplicaRange(spec)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Index(i), spec, err.Error()))
+			continue
+		}
+		for replica := from; replica <= to; replica++ {
+			if seen[replica] {
+				allErrs = append(allErrs, field.Invalid(path.Index(i), spec, fmt.Sprintf("replica %d is already selected", replica)))
+				break
+			}
+			seen[replica] = true
+		}
+	}
+	return allErrs
+}
+
+// selectedReplicas returns the ordinals to migrate, every replica when spec.replicas is empty
+func selectedReplicas(specs []string, replicas int32) ([]int32, error) {
+	if len(specs) == 0 {
+		ordinals := make([]int32, replicas)
+		for i := range replicas {
+			ordinals[i] = i
+		}
+		return ordinals, nil
+	}
+	return ParseReplicas(specs)
+}
+
+// oldSizeReplicas returns, for each volume, the ordinals whose PVC still has the old size: replicas of
+// the subset not migrated yet, and replicas left out of it with a PVC bigger than newSize. A missing
+// PVC belongs to a replica that was never started and does not count. Returns nil for a migration of
+// every replica, whose templates follow the migrated volumes.
+func (r *VolumeResizeReconciler) oldSizeReplicas(ctx context.Context, vr *storagev1alpha1.VolumeResize, sts *appsv1.StatefulSet) (map[string][]int32, error) {
+	if len(vr.Spec.Replicas) == 0 {
+		return nil, nil
+	}
+
+	count := replicaCount(vr.Status.VolumeStatuses)
+	if sts.Spec.Replicas != nil {
+		count = max(count, *sts.Spec.Replicas)
+	}
+
+	oldSize := map[string][]int32{}
+	for _, vol := range vr.Spec.Volumes {
+		for replica := range count {
+			phase := getVolumePhase(vr.Status.VolumeStatuses, vol.Name, replica)
+			if phase != "" {
+				if phase != VolumeStatusCompleted {
+					oldSize[vol.Name] = append(oldSize[vol.Name], replica)
+				}
+				continue
+			}
+
+			pvc, err := getPVC(ctx, r.Client, vr.Namespace, getOriginalPVCName(vol.Name, vr.Spec.StatefulSetName, replica))
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if size := pvc.Spec.Resources.Requests.Storage(); size != nil && size.Cmp(vol.NewSize) > 0 {
+				oldSize[vol.Name] = append(oldSize[vol.Name], replica)
+			}
+		}
+	}
+	return oldSize, nil
+}
+
+// setPartiallyResized records which ordinals still carry the old size of each volume, for migrations of
+// a subset of the replicas
+func setPartiallyResized(vr *storagev1alpha1.VolumeResize, oldSize map[string][]int32) {
+	if len(vr.Spec.Replicas) == 0 {
+		return
+	}
+
+	parts := []string{}
+	for _, vol := range vr.Spec.Volumes {
+		if replicas := oldSize[vol.Name]; len(replicas) > 0 {
+			parts = append(parts, fmt.Sprintf("%s: replicas %s still have the old size", vol.Name, FormatReplicas(replicas)))
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:               ConditionTypePartiallyResized,
+		Status:             metav1.ConditionFalse,
+		Reason:             "AllResized",
+		Message:            "every replica is at or below the new size",
+		ObservedGeneration: vr.Generation,
+	}
+	if len(parts) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "OldSizeReplicas"
+		condition.Message = strings.Join(parts, "; ")
+	}
+	meta.SetStatusCondition(&vr.Status.Conditions, condition)
+}
+
+// updatePartiallyResized refreshes the PartiallyResized condition from the PVCs of the StatefulSet
+func (r *VolumeResizeReconciler) updatePartiallyResized(ctx context.Context, vr *storagev1alpha1.VolumeResize, sts *appsv1.StatefulSet) error {
+	oldSize, err := r.oldSizeReplicas(ctx, vr, sts)
+	if err != nil {
+		return err
+	}
+	setPartiallyResized(vr, oldSize)
+	return nil
+}
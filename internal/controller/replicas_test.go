@@ -0,0 +1,148 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+func replicasTestPVC(replica int32, size string) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: getOriginalPVCName("data", "test-sts", replica), Namespace: "default"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+			},
+		},
+	}
+}
+
+func TestParseReplicas(t *testing.T) {
+	ordinals, err := ParseReplicas([]string{"3-4", "0", "1-1"})
+	require.NoError(t, err)
+	assert.Equal(t, []int32{0, 1, 3, 4}, ordinals)
+
+	for _, spec := range []string{"", "a", "-1", "1-", "3-1", "10000", "0-2147483647", "0-99999999999"} {
+		_, err := ParseReplicas([]string{spec})
+		assert.Error(t, err, spec)
+	}
+
+	ordinals, err = selectedReplicas(nil, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []int32{0, 1, 2}, ordinals)
+}
+
+func TestValidateReplicas(t *testing.T) {
+	path := field.NewPath("spec", "replicas")
+	assert.Empty(t, ValidateReplicas(nil, path))
+	assert.Empty(t, ValidateReplicas([]string{"0", "2-3"}, path))
+
+	errs := ValidateReplicas([]string{"x", "1-2", "2"}, path)
+	require.Len(t, errs, 2)
+	assert.Equal(t, "spec.replicas[0]", errs[0].Field)
+	assert.Equal(t, "spec.replicas[2]", errs[1].Field)
+	assert.Contains(t, errs[1].Detail, "replica 2 is already selected")
+
+	// A huge range is refused before anything is expanded
+	errs = ValidateReplicas([]string{"0-2147483647"}, path)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Detail, "above 9999")
+}
+
+func TestPartiallyResized(t *testing.T) {
+	vr := controlsTestVR()
+	vr.Spec.Replicas = []string{"0-2"}
+	sts := controlsTestSTS()
+	stsReplicas := int32(5)
+	sts.Spec.Replicas = &stsReplicas
+	// Replica 4 was resized by an earlier migration, replica 3 still has the old size
+	r, c := newControlsTestReconciler(t, vr, replicasTestPVC(3, "1Gi"), replicasTestPVC(4, "500Mi"))
+	ctx := context.Background()
+
+	oldSize, err := r.oldSizeReplicas(ctx, vr, sts)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]int32{"data": {1, 2, 3}}, oldSize)
+
+	applyMigratedTemplates(vr, sts, oldSize)
+	assert.Equal(t, "1Gi", sts.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests.Storage().String())
+
+	setPartiallyResized(vr, oldSize)
+	cond := meta.FindStatusCondition(vr.Status.Conditions, ConditionTypePartiallyResized)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Equal(t, "data: replicas 1, 2, 3 still have the old size", cond.Message)
+
+	// Once every replica is at or below the new size, the template follows
+	for i := range vr.Status.VolumeStatuses {
+		vr.Status.VolumeStatuses[i].Phase = VolumeStatusCompleted
+	}
+	require.NoError(t, c.Update(ctx, replicasTestPVC(3, "400Mi")))
+	require.NoError(t, r.updatePartiallyResized(ctx, vr, sts))
+	cond = meta.FindStatusCondition(vr.Status.Conditions, ConditionTypePartiallyResized)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, "AllResized", cond.Reason)
+
+	oldSize, err = r.oldSizeReplicas(ctx, vr, sts)
+	require.NoError(t, err)
+	applyMigratedTemplates(vr, sts, oldSize)
+	assert.Equal(t, "500Mi", sts.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests.Storage().String())
+
+	// A migration of every replica has no condition
+	vr.Spec.Replicas = nil
+	vr.Status.Conditions = nil
+	require.NoError(t, r.updatePartiallyResized(ctx, vr, sts))
+	assert.Empty(t, vr.Status.Conditions)
+}
+
+func TestValidatingChecksEverySelectedReplica(t *testing.T) {
+	vr := controlsTestVR()
+	vr.Spec.Replicas = []string{"1-2"}
+	vr.Spec.Strategy = &storagev1alpha1.MigrationStrategy{Type: StrategyTypeOffline}
+	vr.Status = storagev1alpha1.VolumeResizeStatus{Phase: PhaseValidating}
+	sts := controlsTestSTS()
+	stsReplicas := int32(3)
+	sts.Spec.Replicas = &stsReplicas
+	// Replica 2 is already below the new size, only replica 1 would pass
+	r, c := newControlsTestReconciler(t, vr, sts, replicasTestPVC(1, "1Gi"), replicasTestPVC(2, "400Mi"))
+	ctx := context.Background()
+
+	_, err := r.handleValidating(ctx, vr)
+	require.NoError(t, err)
+
+	updated := &storagev1alpha1.VolumeResize{}
+	require.NoError(t, c.Get(ctx, ctrlRequest("test-resize").NamespacedName, updated))
+	assert.Equal(t, PhaseFailed, updated.Status.Phase)
+	assert.Contains(t, updated.Status.Message, "must be smaller")
+
+	require.NoError(t, c.Update(ctx, replicasTestPVC(2, "1Gi")))
+	_, err = r.handleValidating(ctx, updated)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, ctrlRequest("test-resize").NamespacedName, updated))
+	assert.Equal(t, PhaseSyncing, updated.Status.Phase, updated.Status.Message)
+	assert.Equal(t, int32(3), updated.Status.StatefulSetReplicas, "maxUnavailable is resolved against the StatefulSet")
+}
@@ -0,0 +1,269 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+// cronSchedule is a parsed five-field cron expression, each field a bitmask of the allowed values
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// Like cron, a day matches either field when both day-of-month and day-of-week are restricted
+	domStar, dowStar bool
+}
+
+// cronField describes the values one cron field accepts
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronMinute = cronField{name: "minute", min: 0, max: 59}
+	cronHour   = cronField{name: "hour", min: 0, max: 23}
+	cronDom    = cronField{name: "day-of-month", min: 1, max: 31}
+	cronMonth  = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6, "jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 7 is Sunday too
+	cronDow = cronField{name: "day-of-week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// parseCron parses a minute hour day-of-month month day-of-week expression. Fields accept *, values,
+// ranges, lists and steps, and month and day-of-week accept three-letter names.
+func parseCron(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields, got %d", expr, len(fields))
+	}
+
+	c := &cronSchedule{domStar: fields[2] == "*", dowStar: fields[4] == "*"}
+	var err error
+	for i, f := range []struct {
+		spec  cronField
+		value *uint64
+	}{{cronMinute, &c.minute}, {cronHour, &c.hour}, {cronDom, &c.dom}, {cronMonth, &c.month}, {cronDow, &c.dow}} {
+		if *f.value, err = parseCronField(fields[i], f.spec); err != nil {
+			return nil, fmt.Errorf("cron expression %q: %w", expr, err)
+		}
+	}
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+	return c, nil
+}
+
+// parseCronField parses one comma-separated cron field into a bitmask
+func parseCronField(value string, spec cronField) (uint64, error) {
+	var bits uint64
+	for part := range strings.SplitSeq(value, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid %s step %q", spec.name, stepPart)
+			}
+		}
+
+		low, high := spec.min, spec.max
+		if rangePart != "*" {
+			lowPart, highPart, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if low, err = parseCronValue(lowPart, spec); err != nil {
+				return 0, err
+			}
+			high = low
+			if isRange {
+				if high, err = parseCronValue(highPart, spec); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				// 5/15 means from 5 to the end, every 15
+				high = spec.max
+			}
+			if low > high {
+				return 0, fmt.Errorf("invalid %s range %q", spec.name, rangePart)
+			}
+		}
+
+		for v := low; v <= high; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func parseCronValue(value string, spec cronField) (int, error) {
+	if v, ok := spec.names[strings.ToLower(value)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(value)
+	if err != nil || v < spec.min || v > spec.max {
+		return 0, fmt.Errorf("invalid %s %q, expected %d-%d", spec.name, value, spec.min, spec.max)
+	}
+	return v, nil
+}
+
+func (c *cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := c.dom&(1<<t.Day()) != 0
+	dowMatch := c.dow&(1<<int(t.Weekday())) != 0
+	if c.domStar || c.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// next returns the first time strictly after t matching the schedule, in the location of t,
+// or the zero time if it never matches within five years, e.g. on February 30th
+func (c *cronSchedule) next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if c.month&(1<<int(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if c.hour&(1<<t.Hour()) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if c.minute&(1<<t.Minute()) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// scheduleLocation returns the time zone of the maintenance windows
+func scheduleLocation(schedule *storagev1alpha1.ScheduleSpec) (*time.Location, error) {
+	if schedule.TimeZone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(schedule.TimeZone)
+}
+
+// ValidateSchedule checks the time zone and the maintenance windows of a schedule
+func ValidateSchedule(schedule *storagev1alpha1.ScheduleSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if schedule == nil {
+		return allErrs
+	}
+	if _, err := scheduleLocation(schedule); err != nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("timeZone"), schedule.TimeZone, err.Error()))
+	}
+	for i, w := range schedule.Windows {
+		windowPath := path.Child("windows").Index(i)
+		if c, err := parseCron(w.Start); err != nil {
+			allErrs = append(allErrs, field.Invalid(windowPath.Child("start"), w.Start, err.Error()))
+		} else if c.next(time.Now()).IsZero() {
+			allErrs = append(allErrs, field.Invalid(windowPath.Child("start"), w.Start, "never matches"))
+		}
+		if w.Duration.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(windowPath.Child("duration"), w.Duration.String(), "must be greater than zero"))
+		}
+	}
+	return allErrs
+}
+
+// nextScheduledStart returns when replicas may start migrating, or the zero time if they may start now.
+// A window is open from each time its cron expression matches for its duration.
+func nextScheduledStart(schedule *storagev1alpha1.ScheduleSpec, now time.Time) (time.Time, error) {
+	if schedule == nil {
+		return time.Time{}, nil
+	}
+	loc, err := scheduleLocation(schedule)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	t := now.In(loc)
+	if schedule.NotBefore != nil && now.Before(schedule.NotBefore.Time) {
+		t = schedule.NotBefore.In(loc)
+	}
+
+	earliest := t
+	if len(schedule.Windows) > 0 {
+		earliest = time.Time{}
+		for _, w := range schedule.Windows {
+			c, err := parseCron(w.Start)
+			if err != nil {
+				return time.Time{}, err
+			}
+			// The last opening before t, if the window is still open at t
+			start := c.next(t.Add(-w.Duration.Duration))
+			if start.IsZero() {
+				continue
+			}
+			if !start.After(t) {
+				start = t
+			}
+			if earliest.IsZero() || start.Before(earliest) {
+				earliest = start
+			}
+		}
+		if earliest.IsZero() {
+			return time.Time{}, fmt.Errorf("no maintenance window opens in the next five years")
+		}
+	}
+
+	if !earliest.After(now) {
+		return time.Time{}, nil
+	}
+	return earliest, nil
+}
+
+// waitForSchedule holds the next batch until replicas may start, reporting when in the status
+func (r *VolumeResizeReconciler) waitForSchedule(ctx context.Context, vr *storagev1alpha1.VolumeResize, next time.Time) (ctrl.Result, error) {
+	message := fmt.Sprintf("Waiting for maintenance window (next: %s)", next.Format(time.RFC3339))
+	if len(vr.Spec.Schedule.Windows) == 0 {
+		message = fmt.Sprintf("Scheduled to start at %s", next.Format(time.RFC3339))
+	}
+
+	if vr.Status.Message != message {
+		logf.FromContext(ctx).Info("Waiting for the schedule to allow replicas to start", "next", next)
+		r.recordEvent(vr, corev1.EventTypeNormal, "WaitingForSchedule", "Schedule", "%s", message)
+		vr.Status.Message = message
+		if err := r.Status().Update(ctx, vr); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: time.Until(next)}, nil
+}
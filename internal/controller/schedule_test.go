@@ -0,0 +1,133 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+func TestCronNext(t *testing.T) {
+	from := time.Date(2026, time.March, 13, 10, 30, 0, 0, time.UTC) // a Friday
+
+	tests := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{"0 1 * * *", time.Date(2026, time.March, 14, 1, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, time.March, 13, 10, 45, 0, 0, time.UTC)},
+		{"30 10 * * *", time.Date(2026, time.March, 14, 10, 30, 0, 0, time.UTC)},
+		{"0 2 * * mon-wed", time.Date(2026, time.March, 16, 2, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 3 * * 7", time.Date(2026, time.March, 15, 3, 0, 0, 0, time.UTC)},
+		// Both day fields restricted, either matches
+		{"0 4 20 * 6", time.Date(2026, time.March, 14, 4, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		c, err := parseCron(tt.expr)
+		require.NoError(t, err, tt.expr)
+		assert.Equal(t, tt.expected, c.next(from), tt.expr)
+	}
+
+	c, err := parseCron("0 0 30 feb *")
+	require.NoError(t, err)
+	assert.True(t, c.next(from).IsZero())
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expr := range []string{"", "0 1 * *", "60 * * * *", "* 24 * * *", "0 0 0 * *", "0 0 * 13 *", "0 0 * * 8", "*/0 * * * *", "5-1 * * * *", "0 0 * foo *"} {
+		_, err := parseCron(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestNextScheduledStart(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	schedule := &storagev1alpha1.ScheduleSpec{
+		TimeZone: "Europe/Paris",
+		Windows:  []storagev1alpha1.MaintenanceWindow{{Start: "0 1 * * *", Duration: metav1.Duration{Duration: 4 * time.Hour}}},
+	}
+
+	// Inside the window
+	next, err := nextScheduledStart(schedule, time.Date(2026, time.June, 1, 3, 0, 0, 0, paris))
+	require.NoError(t, err)
+	assert.True(t, next.IsZero())
+
+	// The window closed at 05:00
+	next, err = nextScheduledStart(schedule, time.Date(2026, time.June, 1, 5, 0, 0, 0, paris))
+	require.NoError(t, err)
+	assert.True(t, next.Equal(time.Date(2026, time.June, 2, 1, 0, 0, 0, paris)), next.String())
+
+	// notBefore moves the start past tonight's window
+	schedule.NotBefore = &metav1.Time{Time: time.Date(2026, time.June, 2, 4, 30, 0, 0, paris)}
+	next, err = nextScheduledStart(schedule, time.Date(2026, time.June, 1, 12, 0, 0, 0, paris))
+	require.NoError(t, err)
+	assert.True(t, next.Equal(schedule.NotBefore.Time), next.String())
+
+	// Without windows, only notBefore holds the start
+	schedule.Windows = nil
+	next, err = nextScheduledStart(schedule, time.Date(2026, time.June, 3, 0, 0, 0, 0, paris))
+	require.NoError(t, err)
+	assert.True(t, next.IsZero())
+
+	next, err = nextScheduledStart(nil, time.Now())
+	require.NoError(t, err)
+	assert.True(t, next.IsZero())
+}
+
+func TestValidateSchedule(t *testing.T) {
+	schedule := &storagev1alpha1.ScheduleSpec{
+		TimeZone: "Mars/Olympus_Mons",
+		Windows: []storagev1alpha1.MaintenanceWindow{
+			{Start: "0 1 * * *"},
+			{Start: "0 0 31 feb *", Duration: metav1.Duration{Duration: time.Hour}},
+		},
+	}
+	errs := ValidateSchedule(schedule, field.NewPath("spec", "schedule"))
+	require.Len(t, errs, 3)
+	assert.Equal(t, "spec.schedule.timeZone", errs[0].Field)
+	assert.Equal(t, "spec.schedule.windows[0].duration", errs[1].Field)
+	assert.Equal(t, "spec.schedule.windows[1].start", errs[2].Field)
+}
+
+func TestScheduleHoldsBetweenBatches(t *testing.T) {
+	vr := controlsTestVR()
+	notBefore := metav1.NewTime(time.Now().Add(time.Hour).Truncate(time.Second))
+	vr.Spec.Schedule = &storagev1alpha1.ScheduleSpec{NotBefore: &notBefore}
+	r, c := newControlsTestReconciler(t, vr)
+	ctx := context.Background()
+
+	result, err := r.startNextBatch(ctx, vr)
+	require.NoError(t, err)
+	assert.InDelta(t, time.Hour.Seconds(), result.RequeueAfter.Seconds(), 5)
+
+	updated := &storagev1alpha1.VolumeResize{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-resize"}, updated))
+	assert.Equal(t, PhaseSyncing, updated.Status.Phase)
+	assert.Empty(t, updated.Status.InFlightReplicas)
+	assert.Contains(t, updated.Status.Message, "Scheduled to start at")
+}
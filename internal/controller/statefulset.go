@@ -18,7 +18,6 @@ package controller
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"time"
 
@@ -28,8 +27,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+	"github.com/thomas-maurice/migcontroller/internal/stsbackup"
 )
 
 // deepCopySTSSpec creates a deep copy of the StatefulSet for later recreation
@@ -98,7 +99,7 @@ func getSTSBackupConfigMapName(vrName string) string {
 	return fmt.Sprintf("%s-sts-backup", vrName)
 }
 
-// backupSTSToConfigMap creates a ConfigMap containing the StatefulSet spec for safe recovery
+// backupSTSToConfigMap stores the StatefulSet as the first revision of its backup, for safe recovery
 func backupSTSToConfigMap(ctx context.Context, c client.Client, vr *storagev1alpha1.VolumeResize, sts *appsv1.StatefulSet) error {
 	cmName := getSTSBackupConfigMapName(vr.Name)
 
@@ -113,37 +114,20 @@ func backupSTSToConfigMap(ctx context.Context, c client.Client, vr *storagev1alp
 		return fmt.Errorf("failed to check for existing backup ConfigMap: %w", err)
 	}
 
-	// Serialize the STS
-	stsJSON, err := json.Marshal(sts)
-	if err != nil {
-		return fmt.Errorf("failed to serialize StatefulSet: %w", err)
-	}
-
-	// Create ConfigMap with the backup
-	cm := &corev1.ConfigMap{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      cmName,
-			Namespace: vr.Namespace,
-			Labels: map[string]string{
-				LabelMigrationName: vr.Name,
-			},
-			Annotations: map[string]string{
-				AnnotationManagedBy: "volume-resize-operator",
-			},
+	_, err = stsbackup.Write(ctx, c, metav1.ObjectMeta{
+		Name:      cmName,
+		Namespace: vr.Namespace,
+		Labels: map[string]string{
+			LabelMigrationName: vr.Name,
 		},
-		Data: map[string]string{
-			ConfigMapKeySTSSpec: string(stsJSON),
+		Annotations: map[string]string{
+			AnnotationManagedBy: "volume-resize-operator",
 		},
-	}
-
-	if err := c.Create(ctx, cm); err != nil {
-		return fmt.Errorf("failed to create backup ConfigMap: %w", err)
-	}
-
-	return nil
+	}, sts)
+	return err
 }
 
-// getSTSFromBackupConfigMap retrieves the StatefulSet spec from the backup ConfigMap
+// getSTSFromBackupConfigMap retrieves the latest revision of the StatefulSet from the backup ConfigMap
 func getSTSFromBackupConfigMap(ctx context.Context, c client.Client, namespace, vrName string) (*appsv1.StatefulSet, error) {
 	cmName := getSTSBackupConfigMapName(vrName)
 
@@ -152,17 +136,45 @@ func getSTSFromBackupConfigMap(ctx context.Context, c client.Client, namespace,
 		return nil, fmt.Errorf("failed to get backup ConfigMap: %w", err)
 	}
 
-	stsJSON, ok := cm.Data[ConfigMapKeySTSSpec]
-	if !ok {
-		return nil, fmt.Errorf("backup ConfigMap missing %s key", ConfigMapKeySTSSpec)
+	return stsbackup.Read(ctx, c, cm)
+}
+
+// statefulSetTolerations returns the tolerations of the backed up StatefulSet pods, none if there is no backup
+func (r *VolumeResizeReconciler) statefulSetTolerations(ctx context.Context, vr *storagev1alpha1.VolumeResize) ([]corev1.Toleration, error) {
+	sts, err := getSTSFromBackupConfigMap(ctx, r.Client, vr.Namespace, vr.Name)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
 	}
+	if err != nil {
+		return nil, err
+	}
+	return sts.Spec.Template.Spec.Tolerations, nil
+}
 
-	sts := &appsv1.StatefulSet{}
-	if err := json.Unmarshal([]byte(stsJSON), sts); err != nil {
-		return nil, fmt.Errorf("failed to deserialize StatefulSet: %w", err)
+// deleteSTSBackup removes the backup ConfigMap and its chunks once the migration no longer needs them:
+// it ended, or the StatefulSet exists again. A backup left behind then marks a migration that stopped
+// part-way, which is what the orphan scanner reports.
+func (r *VolumeResizeReconciler) deleteSTSBackup(ctx context.Context, vr *storagev1alpha1.VolumeResize) error {
+	cm := &corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: vr.Namespace, Name: getSTSBackupConfigMapName(vr.Name)}, cm)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get backup ConfigMap: %w", err)
 	}
 
-	return sts, nil
+	if vr.Status.Phase != PhaseCompleted && vr.Status.Phase != PhaseAborted {
+		err := r.Get(ctx, types.NamespacedName{Namespace: vr.Namespace, Name: vr.Spec.StatefulSetName}, &appsv1.StatefulSet{})
+		if apierrors.IsNotFound(err) {
+			logf.FromContext(ctx).Info("Keeping the StatefulSet backup, the StatefulSet is missing", "configmap", cm.Name)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return stsbackup.Delete(ctx, r.Client, cm)
 }
 
 // recreateSTS recreates a StatefulSet from a stored spec
@@ -174,6 +186,8 @@ func recreateSTS(ctx context.Context, c client.Client, stsSpec *appsv1.StatefulS
 			Namespace:   stsSpec.Namespace,
 			Labels:      stsSpec.Labels,
 			Annotations: stsSpec.Annotations,
+			// Keep the StatefulSet attached to the operator or chart that owns it
+			OwnerReferences: stsSpec.OwnerReferences,
 		},
 		Spec: stsSpec.Spec,
 	}
@@ -191,3 +205,106 @@ func recreateSTS(ctx context.Context, c client.Client, stsSpec *appsv1.StatefulS
 
 	return nil
 }
+
+// deletesPVCsWithSTS returns true if the StatefulSet controller garbage-collects the PVCs when the StatefulSet is deleted
+func deletesPVCsWithSTS(sts *appsv1.StatefulSet) bool {
+	policy := sts.Spec.PersistentVolumeClaimRetentionPolicy
+	return policy != nil && policy.WhenDeleted == appsv1.DeletePersistentVolumeClaimRetentionPolicyType
+}
+
+// getSTSPVCNames returns the names of the PVCs of every volumeClaimTemplate and replica of a StatefulSet
+func getSTSPVCNames(sts *appsv1.StatefulSet) []string {
+	replicas := int32(1)
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+
+	names := []string{}
+	for i := range replicas {
+		for _, vct := range sts.Spec.VolumeClaimTemplates {
+			names = append(names, getOriginalPVCName(vct.Name, sts.Name, i))
+		}
+	}
+	return names
+}
+
+// retainPVCsOnDelete switches a whenDeleted=Delete retention policy to Retain and strips the owner references
+// the StatefulSet controller put on the PVCs, so deleting the StatefulSet cannot garbage-collect them
+func retainPVCsOnDelete(ctx context.Context, c client.Client, sts *appsv1.StatefulSet) error {
+	if !deletesPVCsWithSTS(sts) {
+		return nil
+	}
+
+	patch := client.MergeFrom(sts.DeepCopy())
+	sts.Spec.PersistentVolumeClaimRetentionPolicy.WhenDeleted = appsv1.RetainPersistentVolumeClaimRetentionPolicyType
+	if err := c.Patch(ctx, sts, patch); err != nil {
+		return fmt.Errorf("failed to set retention policy to Retain on StatefulSet %s: %w", sts.Name, err)
+	}
+
+	for _, pvcName := range getSTSPVCNames(sts) {
+		pvc, err := getPVC(ctx, c, sts.Namespace, pvcName)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		refs := []metav1.OwnerReference{}
+		for _, ref := range pvc.OwnerReferences {
+			if ref.UID != sts.UID {
+				refs = append(refs, ref)
+			}
+		}
+		if len(refs) == len(pvc.OwnerReferences) {
+			continue
+		}
+
+		pvcPatch := client.MergeFrom(pvc.DeepCopy())
+		pvc.OwnerReferences = refs
+		if err := c.Patch(ctx, pvc, pvcPatch); err != nil {
+			return fmt.Errorf("failed to remove StatefulSet owner reference from PVC %s: %w", pvcName, err)
+		}
+	}
+	return nil
+}
+
+// restorePVCOwnerRefs points the PVCs back at a recreated StatefulSet whose policy deletes them with it,
+// undoing retainPVCsOnDelete. The references carry the UID of the new StatefulSet.
+func restorePVCOwnerRefs(ctx context.Context, c client.Client, stsSpec *appsv1.StatefulSet) error {
+	if !deletesPVCsWithSTS(stsSpec) {
+		return nil
+	}
+
+	sts := &appsv1.StatefulSet{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: stsSpec.Namespace, Name: stsSpec.Name}, sts); err != nil {
+		return fmt.Errorf("failed to get recreated StatefulSet: %w", err)
+	}
+	ref := *metav1.NewControllerRef(sts, appsv1.SchemeGroupVersion.WithKind("StatefulSet"))
+
+	for _, pvcName := range getSTSPVCNames(sts) {
+		pvc, err := getPVC(ctx, c, sts.Namespace, pvcName)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if metav1.IsControlledBy(pvc, sts) {
+			continue
+		}
+
+		pvcRef := ref
+		if metav1.GetControllerOf(pvc) != nil {
+			// Only one controller is allowed, another owner such as a condemned pod keeps that role
+			pvcRef.Controller = new(bool)
+		}
+
+		patch := client.MergeFrom(pvc.DeepCopy())
+		pvc.OwnerReferences = append(pvc.OwnerReferences, pvcRef)
+		if err := c.Patch(ctx, pvc, patch); err != nil {
+			return fmt.Errorf("failed to restore StatefulSet owner reference on PVC %s: %w", pvcName, err)
+		}
+	}
+	return nil
+}
@@ -159,3 +159,58 @@ func TestDeletePod(t *testing.T) {
 	err := deletePod(ctx, c, "default", "test-sts", 0)
 	require.NoError(t, err)
 }
+
+func TestRetainAndRestorePVCOwnerRefs(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sts", Namespace: "default", UID: "old-uid"},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: ptrInt32(2),
+			PersistentVolumeClaimRetentionPolicy: &appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+				WhenDeleted: appsv1.DeletePersistentVolumeClaimRetentionPolicyType,
+				WhenScaled:  appsv1.RetainPersistentVolumeClaimRetentionPolicyType,
+			},
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: "data"}}},
+		},
+	}
+	original := sts.DeepCopy()
+	ownedPVC := func(name string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       "default",
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(sts, appsv1.SchemeGroupVersion.WithKind("StatefulSet"))},
+			},
+		}
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(sts, ownedPVC("data-test-sts-0"), ownedPVC("data-test-sts-1")).Build()
+	ctx := context.Background()
+
+	require.NoError(t, retainPVCsOnDelete(ctx, c, sts))
+
+	updated := &appsv1.StatefulSet{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(sts), updated))
+	assert.Equal(t, appsv1.RetainPersistentVolumeClaimRetentionPolicyType, updated.Spec.PersistentVolumeClaimRetentionPolicy.WhenDeleted)
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	for _, name := range []string{"data-test-sts-0", "data-test-sts-1"} {
+		require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "default", Name: name}, pvc))
+		assert.Empty(t, pvc.OwnerReferences, name)
+	}
+
+	// The StatefulSet is recreated from the backup with a new UID
+	require.NoError(t, c.Delete(ctx, updated))
+	recreated := original.DeepCopy()
+	recreated.UID = "new-uid"
+	recreated.ResourceVersion = ""
+	require.NoError(t, c.Create(ctx, recreated))
+
+	require.NoError(t, restorePVCOwnerRefs(ctx, c, original))
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "data-test-sts-1"}, pvc))
+	require.Len(t, pvc.OwnerReferences, 1)
+	assert.Equal(t, "new-uid", string(pvc.OwnerReferences[0].UID))
+}
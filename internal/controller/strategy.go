@@ -0,0 +1,188 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+// isOfflineStrategy returns true if the whole StatefulSet is taken down for a single copy window
+func isOfflineStrategy(strategy *storagev1alpha1.MigrationStrategy) bool {
+	return strategy != nil && strategy.Type == StrategyTypeOffline
+}
+
+// countReplicaPVCs returns the number of consecutive ordinals, starting at 0, that have a PVC for the volume.
+// Used for StatefulSets scaled to zero, whose PVCs outlive the pods.
+func countReplicaPVCs(ctx context.Context, c client.Client, namespace, stsName, volName string) (int32, error) {
+	count := int32(0)
+	for {
+		_, err := getPVC(ctx, c, namespace, getOriginalPVCName(volName, stsName, count))
+		if apierrors.IsNotFound(err) {
+			return count, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to get PVC: %w", err)
+		}
+		count++
+	}
+}
+
+// resolveMaxUnavailable returns how many replicas may be migrated at once, at least 1
+func resolveMaxUnavailable(strategy *storagev1alpha1.MigrationStrategy, replicas int32) (int32, error) {
+	if strategy == nil || strategy.MaxUnavailable == nil {
+		return 1, nil
+	}
+
+	value, err := intstr.GetScaledValueFromIntOrPercent(strategy.MaxUnavailable, int(replicas), false)
+	if err != nil {
+		return 0, fmt.Errorf("invalid maxUnavailable %q: %w", strategy.MaxUnavailable.String(), err)
+	}
+	if value < 0 {
+		return 0, fmt.Errorf("invalid maxUnavailable %q: must not be negative", strategy.MaxUnavailable.String())
+	}
+	if value < 1 {
+		return 1, nil
+	}
+	return int32(value), nil
+}
+
+// replicaCount returns the number of replicas tracked in the volume statuses
+func replicaCount(statuses []storagev1alpha1.VolumeStatus) int32 {
+	count := int32(0)
+	for _, vs := range statuses {
+		if vs.Replica+1 > count {
+			count = vs.Replica + 1
+		}
+	}
+	return count
+}
+
+// maxUnavailableBase returns the replica count a percentage maxUnavailable was resolved against at validation.
+// Migrations validated before it was recorded fall back to the replicas in the volume statuses.
+func maxUnavailableBase(vr *storagev1alpha1.VolumeResize) int32 {
+	if vr.Status.StatefulSetReplicas > 0 {
+		return vr.Status.StatefulSetReplicas
+	}
+	return replicaCount(vr.Status.VolumeStatuses)
+}
+
+// pendingReplicas returns the replicas with at least one volume not migrated yet, in ascending order
+func pendingReplicas(statuses []storagev1alpha1.VolumeStatus) []int32 {
+	pending := []int32{}
+	for _, vs := range statuses {
+		if vs.Phase != VolumeStatusCompleted && !slices.Contains(pending, vs.Replica) {
+			pending = append(pending, vs.Replica)
+		}
+	}
+	slices.Sort(pending)
+	return pending
+}
+
+// getVolumePhase returns the phase of a volume on a replica, or an empty string if it is not tracked
+func getVolumePhase(statuses []storagev1alpha1.VolumeStatus, volName string, replica int32) string {
+	for _, vs := range statuses {
+		if vs.VolumeName == volName && vs.Replica == replica {
+			return vs.Phase
+		}
+	}
+	return ""
+}
+
+// hasCompletedVolume returns true if the volume was migrated on at least one replica
+func hasCompletedVolume(statuses []storagev1alpha1.VolumeStatus, volName string) bool {
+	for _, vs := range statuses {
+		if vs.VolumeName == volName && vs.Phase == VolumeStatusCompleted {
+			return true
+		}
+	}
+	return false
+}
+
+// failedVolumes returns "<volume>@<replica>" for every failed volume of the given replicas
+func failedVolumes(statuses []storagev1alpha1.VolumeStatus, replicas []int32) []string {
+	failed := []string{}
+	for _, vs := range statuses {
+		if vs.Phase == VolumeStatusFailed && slices.Contains(replicas, vs.Replica) {
+			failed = append(failed, fmt.Sprintf("%s@%d", vs.VolumeName, vs.Replica))
+		}
+	}
+	return failed
+}
+
+// FormatReplicas renders a list of replica indexes for status messages and the volmig CLI
+func FormatReplicas(replicas []int32) string {
+	parts := make([]string, len(replicas))
+	for i, replica := range replicas {
+		parts[i] = fmt.Sprintf("%d", replica)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// matchingPDBs returns the PDBs whose selector, matchLabels and matchExpressions alike, selects the
+// StatefulSet pods. A PDB with an invalid selector protects nothing and is left out.
+func matchingPDBs(ctx context.Context, c client.Client, sts *appsv1.StatefulSet) ([]policyv1.PodDisruptionBudget, error) {
+	pdbList := &policyv1.PodDisruptionBudgetList{}
+	if err := c.List(ctx, pdbList, client.InNamespace(sts.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list PodDisruptionBudgets: %w", err)
+	}
+
+	podLabels := labels.Set(sts.Spec.Template.Labels)
+	matching := []policyv1.PodDisruptionBudget{}
+	for _, pdb := range pdbList.Items {
+		if pdb.Spec.Selector == nil {
+			continue
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || !selector.Matches(podLabels) {
+			continue
+		}
+		matching = append(matching, pdb)
+	}
+	return matching, nil
+}
+
+// getPDBDisruptionsAllowed returns the lowest disruptionsAllowed among the PDBs selecting the StatefulSet pods.
+// limited is false when no PDB applies.
+func getPDBDisruptionsAllowed(ctx context.Context, c client.Client, sts *appsv1.StatefulSet) (allowed int32, limited bool, err error) {
+	pdbs, err := matchingPDBs(ctx, c, sts)
+	if err != nil {
+		return 0, false, err
+	}
+
+	for _, pdb := range pdbs {
+		if !limited || pdb.Status.DisruptionsAllowed < allowed {
+			allowed = pdb.Status.DisruptionsAllowed
+		}
+		limited = true
+	}
+
+	return allowed, limited, nil
+}
@@ -0,0 +1,305 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+func TestResolveMaxUnavailable(t *testing.T) {
+	intVal := intstr.FromInt32(3)
+	percent := intstr.FromString("25%")
+	smallPercent := intstr.FromString("1%")
+	invalid := intstr.FromString("lots")
+
+	tests := []struct {
+		name     string
+		strategy *storagev1alpha1.MigrationStrategy
+		replicas int32
+		expected int32
+		wantErr  bool
+	}{
+		{"default", nil, 10, 1, false},
+		{"absolute", &storagev1alpha1.MigrationStrategy{MaxUnavailable: &intVal}, 10, 3, false},
+		{"percent rounds down", &storagev1alpha1.MigrationStrategy{MaxUnavailable: &percent}, 30, 7, false},
+		{"percent at least one", &storagev1alpha1.MigrationStrategy{MaxUnavailable: &smallPercent}, 3, 1, false},
+		{"invalid", &storagev1alpha1.MigrationStrategy{MaxUnavailable: &invalid}, 3, 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := resolveMaxUnavailable(tt.strategy, tt.replicas)
+		if tt.wantErr {
+			assert.Error(t, err, tt.name)
+			continue
+		}
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.expected, got, tt.name)
+	}
+}
+
+func TestPendingReplicas(t *testing.T) {
+	statuses := []storagev1alpha1.VolumeStatus{
+		{VolumeName: "data", Replica: 0, Phase: VolumeStatusCompleted},
+		{VolumeName: "logs", Replica: 0, Phase: VolumeStatusCompleted},
+		{VolumeName: "data", Replica: 2, Phase: VolumeStatusPending},
+		{VolumeName: "data", Replica: 1, Phase: VolumeStatusCompleted},
+		{VolumeName: "logs", Replica: 1, Phase: VolumeStatusSyncing},
+	}
+
+	assert.Equal(t, []int32{1, 2}, pendingReplicas(statuses))
+	assert.Equal(t, int32(3), replicaCount(statuses))
+}
+
+func TestMaxUnavailableBase(t *testing.T) {
+	vr := &storagev1alpha1.VolumeResize{
+		Status: storagev1alpha1.VolumeResizeStatus{
+			VolumeStatuses: []storagev1alpha1.VolumeStatus{{VolumeName: "data", Replica: 1, Phase: VolumeStatusPending}},
+		},
+	}
+	assert.Equal(t, int32(2), maxUnavailableBase(vr))
+
+	// A subset is resolved against the StatefulSet replicas, as at validation
+	vr.Status.StatefulSetReplicas = 10
+	assert.Equal(t, int32(10), maxUnavailableBase(vr))
+}
+
+func TestFailedVolumes(t *testing.T) {
+	statuses := []storagev1alpha1.VolumeStatus{
+		{VolumeName: "data", Replica: 0, Phase: VolumeStatusFailed},
+		{VolumeName: "data", Replica: 1, Phase: VolumeStatusCompleted},
+		{VolumeName: "data", Replica: 2, Phase: VolumeStatusFailed},
+	}
+
+	assert.Equal(t, []string{"data@0"}, failedVolumes(statuses, []int32{0, 1}))
+}
+
+func TestGetPDBDisruptionsAllowed(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, policyv1.AddToScheme(scheme))
+
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sts", Namespace: "default"},
+		Spec: appsv1.StatefulSetSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "test"}},
+			},
+		},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	ctx := context.Background()
+
+	_, limited, err := getPDBDisruptionsAllowed(ctx, c, sts)
+	require.NoError(t, err)
+	assert.False(t, limited)
+
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pdb", Namespace: "default"},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test"}},
+		},
+		Status: policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 2},
+	}
+	c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(pdb).Build()
+
+	allowed, limited, err := getPDBDisruptionsAllowed(ctx, c, sts)
+	require.NoError(t, err)
+	assert.True(t, limited)
+	assert.Equal(t, int32(2), allowed)
+}
+
+func TestPDBMatchExpressions(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, policyv1.AddToScheme(scheme))
+
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sts", Namespace: "default"},
+		Spec: appsv1.StatefulSetSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "test"}},
+			},
+		},
+	}
+	// Only expressions, selecting another application
+	unrelated := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "other-pdb", Namespace: "default"},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "app", Operator: metav1.LabelSelectorOpIn, Values: []string{"other"}},
+			}},
+		},
+	}
+	ctx := context.Background()
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(unrelated).Build()
+	_, limited, err := getPDBDisruptionsAllowed(ctx, c, sts)
+	require.NoError(t, err)
+	assert.False(t, limited)
+	assert.True(t, validatePDBAllowsDisruption(ctx, c, sts).Valid)
+
+	// Mixed labels and expressions must all match
+	mixed := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pdb", Namespace: "default"},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "test"},
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "tier", Operator: metav1.LabelSelectorOpExists},
+				},
+			},
+		},
+	}
+	c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(mixed).Build()
+	_, limited, err = getPDBDisruptionsAllowed(ctx, c, sts)
+	require.NoError(t, err)
+	assert.False(t, limited)
+
+	sts.Spec.Template.Labels["tier"] = "db"
+	_, limited, err = getPDBDisruptionsAllowed(ctx, c, sts)
+	require.NoError(t, err)
+	assert.True(t, limited)
+	assert.False(t, validatePDBAllowsDisruption(ctx, c, sts).Valid)
+}
+
+func TestStartNextBatchRespectsPDB(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, storagev1alpha1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, policyv1.AddToScheme(scheme))
+
+	maxUnavailable := intstr.FromInt32(5)
+	vr := &storagev1alpha1.VolumeResize{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-resize", Namespace: "default"},
+		Spec: storagev1alpha1.VolumeResizeSpec{
+			StatefulSetName: "test-sts",
+			Volumes:         []storagev1alpha1.VolumeResizeTarget{{Name: "data"}},
+			Strategy:        &storagev1alpha1.MigrationStrategy{MaxUnavailable: &maxUnavailable},
+		},
+		Status: storagev1alpha1.VolumeResizeStatus{Phase: PhaseSyncing},
+	}
+	for i := range int32(6) {
+		phase := VolumeStatusPending
+		if i == 0 {
+			phase = VolumeStatusCompleted
+		}
+		vr.Status.VolumeStatuses = append(vr.Status.VolumeStatuses, storagev1alpha1.VolumeStatus{VolumeName: "data", Replica: i, Phase: phase})
+	}
+
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sts", Namespace: "default"},
+		Spec: appsv1.StatefulSetSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "test"}},
+			},
+		},
+	}
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pdb", Namespace: "default"},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test"}},
+		},
+		Status: policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 2},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(vr, sts, pdb).
+		WithStatusSubresource(&storagev1alpha1.VolumeResize{}).
+		Build()
+	r := &VolumeResizeReconciler{Client: c, Scheme: scheme}
+	ctx := context.Background()
+
+	_, err := r.startNextBatch(ctx, vr)
+	require.NoError(t, err)
+
+	updated := &storagev1alpha1.VolumeResize{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-resize"}, updated))
+	assert.Equal(t, []int32{1, 2}, updated.Status.InFlightReplicas)
+	require.NotNil(t, updated.Status.CurrentReplica)
+	assert.Equal(t, int32(1), *updated.Status.CurrentReplica)
+}
+
+func TestCountReplicaPVCs(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	pvc := func(name string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		pvc("data-test-sts-0"), pvc("data-test-sts-1"), pvc("data-test-sts-3"),
+	).Build()
+
+	count, err := countReplicaPVCs(context.Background(), c, "default", "test-sts", "data")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), count)
+}
+
+func TestStartNextBatchOfflineIgnoresPDB(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, storagev1alpha1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, policyv1.AddToScheme(scheme))
+
+	vr := &storagev1alpha1.VolumeResize{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-resize", Namespace: "default"},
+		Spec: storagev1alpha1.VolumeResizeSpec{
+			StatefulSetName: "test-sts",
+			Volumes:         []storagev1alpha1.VolumeResizeTarget{{Name: "data"}},
+			Strategy:        &storagev1alpha1.MigrationStrategy{Type: StrategyTypeOffline},
+		},
+		Status: storagev1alpha1.VolumeResizeStatus{Phase: PhaseSyncing},
+	}
+	for i := range int32(3) {
+		vr.Status.VolumeStatuses = append(vr.Status.VolumeStatuses, storagev1alpha1.VolumeStatus{VolumeName: "data", Replica: i, Phase: VolumeStatusPending})
+	}
+
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pdb", Namespace: "default"},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test"}},
+		},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(vr, pdb).
+		WithStatusSubresource(&storagev1alpha1.VolumeResize{}).
+		Build()
+	r := &VolumeResizeReconciler{Client: c, Scheme: scheme}
+	ctx := context.Background()
+
+	_, err := r.startNextBatch(ctx, vr)
+	require.NoError(t, err)
+
+	updated := &storagev1alpha1.VolumeResize{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-resize"}, updated))
+	assert.Equal(t, []int32{0, 1, 2}, updated.Status.InFlightReplicas)
+	assert.Contains(t, updated.Status.Message, "offline")
+}
@@ -0,0 +1,334 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+// getPV retrieves a PV by name
+func getPV(ctx context.Context, c client.Client, name string) (*corev1.PersistentVolume, error) {
+	pv := &corev1.PersistentVolume{}
+	if err := c.Get(ctx, types.NamespacedName{Name: name}, pv); err != nil {
+		return nil, err
+	}
+	return pv, nil
+}
+
+// getPVNodeSelector returns the required node selector of a PV, or nil if the PV is reachable from any node
+func getPVNodeSelector(pv *corev1.PersistentVolume) *corev1.NodeSelector {
+	if pv.Spec.NodeAffinity == nil || pv.Spec.NodeAffinity.Required == nil {
+		return nil
+	}
+	if len(pv.Spec.NodeAffinity.Required.NodeSelectorTerms) == 0 {
+		return nil
+	}
+	return pv.Spec.NodeAffinity.Required
+}
+
+// nodeMatchesSelector checks a node against a node selector: terms are ORed, requirements within a term are ANDed
+func nodeMatchesSelector(node *corev1.Node, selector *corev1.NodeSelector) bool {
+	if selector == nil {
+		return true
+	}
+	for _, term := range selector.NodeSelectorTerms {
+		if nodeMatchesTerm(node, term) {
+			return true
+		}
+	}
+	return false
+}
+
+func nodeMatchesTerm(node *corev1.Node, term corev1.NodeSelectorTerm) bool {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false
+	}
+	for _, req := range term.MatchExpressions {
+		value, ok := node.Labels[req.Key]
+		if !matchNodeSelectorRequirement(req, value, ok) {
+			return false
+		}
+	}
+	for _, req := range term.MatchFields {
+		if req.Key != "metadata.name" || !matchNodeSelectorRequirement(req, node.Name, true) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchNodeSelectorRequirement(req corev1.NodeSelectorRequirement, value string, present bool) bool {
+	switch req.Operator {
+	case corev1.NodeSelectorOpIn:
+		return present && slices.Contains(req.Values, value)
+	case corev1.NodeSelectorOpNotIn:
+		return !present || !slices.Contains(req.Values, value)
+	case corev1.NodeSelectorOpExists:
+		return present
+	case corev1.NodeSelectorOpDoesNotExist:
+		return !present
+	case corev1.NodeSelectorOpGt, corev1.NodeSelectorOpLt:
+		if !present || len(req.Values) != 1 {
+			return false
+		}
+		got, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return false
+		}
+		want, err := strconv.ParseInt(req.Values[0], 10, 64)
+		if err != nil {
+			return false
+		}
+		if req.Operator == corev1.NodeSelectorOpGt {
+			return got > want
+		}
+		return got < want
+	default:
+		return false
+	}
+}
+
+// toleratesTaint checks a taint against tolerations the way the scheduler does: an empty effect matches
+// every effect, and an empty key with the Exists operator tolerates every taint
+func toleratesTaint(tolerations []corev1.Toleration, taint *corev1.Taint) bool {
+	for _, t := range tolerations {
+		if t.Effect != "" && t.Effect != taint.Effect {
+			continue
+		}
+		if t.Key != "" && t.Key != taint.Key {
+			continue
+		}
+		switch t.Operator {
+		case corev1.TolerationOpExists:
+			return true
+		case "", corev1.TolerationOpEqual:
+			if t.Key != "" && t.Value == taint.Value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isNodeSchedulable returns true if new pods with the given tolerations can be placed on the node
+func isNodeSchedulable(node *corev1.Node, tolerations []corev1.Toleration) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect != corev1.TaintEffectPreferNoSchedule && !toleratesTaint(tolerations, taint) {
+			return false
+		}
+	}
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// storageClassAllowsNode checks that a StorageClass can provision volumes in the topology of the node
+func storageClassAllowsNode(sc *storagev1.StorageClass, node *corev1.Node) bool {
+	if sc == nil || len(sc.AllowedTopologies) == 0 {
+		return true
+	}
+	for _, term := range sc.AllowedTopologies {
+		matches := true
+		for _, expr := range term.MatchLabelExpressions {
+			if !slices.Contains(expr.Values, node.Labels[expr.Key]) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// selectMigrationNode picks a node that can attach the PV and accepts pods with the given tolerations.
+// It only checks such a node exists and hints where an Immediate temp PVC is provisioned, the migrator
+// pods themselves are placed by the scheduler through migrationPlacement.
+// Returns an empty name when the PV carries no node affinity and the scheduler is free to choose.
+func selectMigrationNode(ctx context.Context, c client.Client, pv *corev1.PersistentVolume, tolerations []corev1.Toleration) (string, error) {
+	selector := getPVNodeSelector(pv)
+	if selector == nil {
+		return "", nil
+	}
+
+	nodeList := &corev1.NodeList{}
+	if err := c.List(ctx, nodeList); err != nil {
+		return "", fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	candidates := []string{}
+	for i := range nodeList.Items {
+		node := &nodeList.Items[i]
+		if isNodeSchedulable(node, tolerations) && nodeMatchesSelector(node, selector) {
+			candidates = append(candidates, node.Name)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("no schedulable node satisfies the node affinity of PV %s", pv.Name)
+	}
+
+	// Sort so repeated reconciles pick the same node
+	sort.Strings(candidates)
+	return candidates[0], nil
+}
+
+// podPlacement constrains where migrator, sender and receiver pods are scheduled
+type podPlacement struct {
+	// Affinity requires the nodes that can attach the old PV
+	Affinity *corev1.Affinity
+
+	// Tolerations are the StatefulSet's, so the pods can run where its replicas did
+	Tolerations []corev1.Toleration
+}
+
+// migrationPlacement copies the node affinity of the old PV, rather than pinning a node, and the
+// tolerations of the StatefulSet pods. The scheduler then picks among every node that fits.
+func migrationPlacement(pv *corev1.PersistentVolume, tolerations []corev1.Toleration) podPlacement {
+	placement := podPlacement{Tolerations: tolerations}
+	if selector := getPVNodeSelector(pv); selector != nil {
+		placement.Affinity = &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{RequiredDuringSchedulingIgnoredDuringExecution: selector.DeepCopy()},
+		}
+	}
+	return placement
+}
+
+// getTargetStorageClass returns the StorageClass the temp PVC will be provisioned with, or nil if none is set
+func getTargetStorageClass(ctx context.Context, c client.Client, vol storagev1alpha1.VolumeResizeTarget, pvc *corev1.PersistentVolumeClaim) (*storagev1.StorageClass, error) {
+	scName := pvc.Spec.StorageClassName
+	if vol.StorageClass != nil {
+		scName = vol.StorageClass
+	}
+	if scName == nil || *scName == "" {
+		return nil, nil
+	}
+
+	sc := &storagev1.StorageClass{}
+	if err := c.Get(ctx, types.NamespacedName{Name: *scName}, sc); err != nil {
+		return nil, fmt.Errorf("failed to get StorageClass %s: %w", *scName, err)
+	}
+	return sc, nil
+}
+
+// validateTopology checks that for every replica a node exists that can attach the old PV and, when
+// colocated, a new volume from the target StorageClass, so the migrator pod never sits Pending on a zone mismatch
+func validateTopology(ctx context.Context, c client.Client, sts *appsv1.StatefulSet, vol storagev1alpha1.VolumeResizeTarget, ordinals []int32, colocated bool) ValidationResult {
+	for _, i := range ordinals {
+		pvcName := getOriginalPVCName(vol.Name, sts.Name, i)
+		pvc, err := getPVC(ctx, c, sts.Namespace, pvcName)
+		if err != nil {
+			return ValidationResult{
+				Valid:   false,
+				Message: fmt.Sprintf("failed to get PVC %s: %v", pvcName, err),
+			}
+		}
+
+		if pvc.Spec.VolumeName == "" {
+			continue
+		}
+
+		pv, err := getPV(ctx, c, pvc.Spec.VolumeName)
+		if err != nil {
+			return ValidationResult{
+				Valid:   false,
+				Message: fmt.Sprintf("failed to get PV %s: %v", pvc.Spec.VolumeName, err),
+			}
+		}
+
+		nodeName, err := selectMigrationNode(ctx, c, pv, sts.Spec.Template.Spec.Tolerations)
+		if err != nil {
+			return ValidationResult{
+				Valid:   false,
+				Message: fmt.Sprintf("no compatible topology for replica %d volume %s: %v", i, vol.Name, err),
+			}
+		}
+		if nodeName == "" || !colocated {
+			continue
+		}
+
+		sc, err := getTargetStorageClass(ctx, c, vol, pvc)
+		if err != nil {
+			return ValidationResult{
+				Valid:   false,
+				Message: err.Error(),
+			}
+		}
+
+		node := &corev1.Node{}
+		if err := c.Get(ctx, types.NamespacedName{Name: nodeName}, node); err != nil {
+			return ValidationResult{
+				Valid:   false,
+				Message: fmt.Sprintf("failed to get node %s: %v", nodeName, err),
+			}
+		}
+
+		if !storageClassAllowsNode(sc, node) {
+			return ValidationResult{
+				Valid: false,
+				Message: fmt.Sprintf("no compatible topology for replica %d volume %s: StorageClass %s cannot provision on node %s where PV %s is attached",
+					i, vol.Name, sc.Name, nodeName, pv.Name),
+			}
+		}
+	}
+
+	return ValidationResult{Valid: true}
+}
+
+// validateTempPVTopology checks that a bound temp PV is reachable from the node its provisioning was hinted to,
+// which can also attach the old PV
+func validateTempPVTopology(ctx context.Context, c client.Client, tempPVC *corev1.PersistentVolumeClaim, nodeName string) error {
+	if nodeName == "" || tempPVC.Spec.VolumeName == "" {
+		return nil
+	}
+
+	pv, err := getPV(ctx, c, tempPVC.Spec.VolumeName)
+	if err != nil {
+		return fmt.Errorf("failed to get PV %s: %w", tempPVC.Spec.VolumeName, err)
+	}
+
+	node := &corev1.Node{}
+	if err := c.Get(ctx, types.NamespacedName{Name: nodeName}, node); err != nil {
+		return fmt.Errorf("failed to get node %s: %w", nodeName, err)
+	}
+
+	if !nodeMatchesSelector(node, getPVNodeSelector(pv)) {
+		return fmt.Errorf("temp PV %s was provisioned outside the topology of node %s, use a StorageClass with volumeBindingMode WaitForFirstConsumer",
+			pv.Name, nodeName)
+	}
+	return nil
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+const zoneLabel = "topology.kubernetes.io/zone"
+
+func testNode(name, zone string, ready bool) *corev1.Node {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{zoneLabel: zone},
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: status},
+			},
+		},
+	}
+}
+
+func zonalPV(name, zone string) *corev1.PersistentVolume {
+	return &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: corev1.PersistentVolumeSpec{
+			NodeAffinity: &corev1.VolumeNodeAffinity{
+				Required: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{
+						{
+							MatchExpressions: []corev1.NodeSelectorRequirement{
+								{Key: zoneLabel, Operator: corev1.NodeSelectorOpIn, Values: []string{zone}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestNodeMatchesSelector(t *testing.T) {
+	node := testNode("node-a", "zone-a", true)
+	node.Labels["disk"] = "ssd"
+	node.Labels["cpus"] = "8"
+
+	tests := []struct {
+		name     string
+		req      corev1.NodeSelectorRequirement
+		expected bool
+	}{
+		{"in match", corev1.NodeSelectorRequirement{Key: zoneLabel, Operator: corev1.NodeSelectorOpIn, Values: []string{"zone-a"}}, true},
+		{"in mismatch", corev1.NodeSelectorRequirement{Key: zoneLabel, Operator: corev1.NodeSelectorOpIn, Values: []string{"zone-b"}}, false},
+		{"not in", corev1.NodeSelectorRequirement{Key: zoneLabel, Operator: corev1.NodeSelectorOpNotIn, Values: []string{"zone-b"}}, true},
+		{"exists", corev1.NodeSelectorRequirement{Key: "disk", Operator: corev1.NodeSelectorOpExists}, true},
+		{"does not exist", corev1.NodeSelectorRequirement{Key: "disk", Operator: corev1.NodeSelectorOpDoesNotExist}, false},
+		{"gt", corev1.NodeSelectorRequirement{Key: "cpus", Operator: corev1.NodeSelectorOpGt, Values: []string{"4"}}, true},
+		{"lt", corev1.NodeSelectorRequirement{Key: "cpus", Operator: corev1.NodeSelectorOpLt, Values: []string{"4"}}, false},
+	}
+
+	for _, tt := range tests {
+		selector := &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{tt.req}},
+			},
+		}
+		assert.Equal(t, tt.expected, nodeMatchesSelector(node, selector), tt.name)
+	}
+
+	assert.True(t, nodeMatchesSelector(node, nil))
+}
+
+func TestSelectMigrationNodeNoAffinity(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	ctx := context.Background()
+
+	nodeName, err := selectMigrationNode(ctx, c, &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-1"}}, nil)
+	require.NoError(t, err)
+	assert.Empty(t, nodeName)
+}
+
+func TestSelectMigrationNodePicksCompatibleNode(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		testNode("node-a", "zone-a", true),
+		testNode("node-b1", "zone-b", false),
+		testNode("node-b2", "zone-b", true),
+	).Build()
+	ctx := context.Background()
+
+	nodeName, err := selectMigrationNode(ctx, c, zonalPV("pv-1", "zone-b"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "node-b2", nodeName)
+}
+
+func TestSelectMigrationNodeNoCompatibleNode(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(testNode("node-a", "zone-a", true)).Build()
+	ctx := context.Background()
+
+	_, err := selectMigrationNode(ctx, c, zonalPV("pv-1", "zone-c"), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pv-1")
+}
+
+func TestSelectMigrationNodeHonoursTaints(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	tainted := testNode("node-b1", "zone-b", true)
+	tainted.Spec.Taints = []corev1.Taint{{Key: "dedicated", Value: "db", Effect: corev1.TaintEffectNoSchedule}}
+	preferred := testNode("node-b2", "zone-b", true)
+	preferred.Spec.Taints = []corev1.Taint{{Key: "spot", Effect: corev1.TaintEffectPreferNoSchedule}}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tainted, preferred).Build()
+	ctx := context.Background()
+
+	// PreferNoSchedule does not keep pods off a node
+	nodeName, err := selectMigrationNode(ctx, c, zonalPV("pv-1", "zone-b"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "node-b2", nodeName)
+
+	require.NoError(t, c.Delete(ctx, preferred))
+	_, err = selectMigrationNode(ctx, c, zonalPV("pv-1", "zone-b"), nil)
+	require.Error(t, err)
+
+	tolerations := []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "db", Effect: corev1.TaintEffectNoSchedule}}
+	nodeName, err = selectMigrationNode(ctx, c, zonalPV("pv-1", "zone-b"), tolerations)
+	require.NoError(t, err)
+	assert.Equal(t, "node-b1", nodeName)
+}
+
+func TestToleratesTaint(t *testing.T) {
+	taint := &corev1.Taint{Key: "dedicated", Value: "db", Effect: corev1.TaintEffectNoSchedule}
+
+	tests := []struct {
+		name       string
+		toleration corev1.Toleration
+		expected   bool
+	}{
+		{"equal", corev1.Toleration{Key: "dedicated", Value: "db", Effect: corev1.TaintEffectNoSchedule}, true},
+		{"other value", corev1.Toleration{Key: "dedicated", Value: "web", Effect: corev1.TaintEffectNoSchedule}, false},
+		{"exists", corev1.Toleration{Key: "dedicated", Operator: corev1.TolerationOpExists}, true},
+		{"other effect", corev1.Toleration{Key: "dedicated", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute}, false},
+		{"wildcard", corev1.Toleration{Operator: corev1.TolerationOpExists}, true},
+		{"other key", corev1.Toleration{Key: "gpu", Operator: corev1.TolerationOpExists}, false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, toleratesTaint([]corev1.Toleration{tt.toleration}, taint), tt.name)
+	}
+}
+
+func TestMigrationPlacementWithoutAffinity(t *testing.T) {
+	placement := migrationPlacement(&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-1"}}, nil)
+	assert.Nil(t, placement.Affinity)
+}
+
+func TestValidateTopologyStorageClassMismatch(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, storagev1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+
+	scName := "zone-a-only"
+	sc := &storagev1.StorageClass{
+		ObjectMeta:  metav1.ObjectMeta{Name: scName},
+		Provisioner: "ebs.csi.aws.com",
+		AllowedTopologies: []corev1.TopologySelectorTerm{
+			{
+				MatchLabelExpressions: []corev1.TopologySelectorLabelRequirement{
+					{Key: zoneLabel, Values: []string{"zone-a"}},
+				},
+			},
+		},
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data-test-sts-0", Namespace: "default"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: &scName,
+			VolumeName:       "pv-1",
+		},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		sc, pvc, zonalPV("pv-1", "zone-b"), testNode("node-b", "zone-b", true),
+	).Build()
+	ctx := context.Background()
+
+	sts := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "test-sts", Namespace: "default"}}
+	vol := storagev1alpha1.VolumeResizeTarget{Name: "data", NewSize: resource.MustParse("500Mi")}
+
+	result := validateTopology(ctx, c, sts, vol, []int32{0}, true)
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Message, "no compatible topology")
+
+	// Network transfers only need a node for the sender
+	result = validateTopology(ctx, c, sts, vol, []int32{0}, false)
+	assert.True(t, result.Valid)
+}
+
+func TestValidateTempPVTopology(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		zonalPV("pv-new", "zone-a"), testNode("node-b", "zone-b", true),
+	).Build()
+	ctx := context.Background()
+
+	tempPVC := &corev1.PersistentVolumeClaim{Spec: corev1.PersistentVolumeClaimSpec{VolumeName: "pv-new"}}
+
+	err := validateTempPVTopology(ctx, c, tempPVC, "node-b")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "WaitForFirstConsumer")
+
+	require.NoError(t, validateTempPVTopology(ctx, c, tempPVC, ""))
+}
@@ -0,0 +1,311 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+// transferState is the combined progress of a sender/receiver pair
+type transferState string
+
+const (
+	transferRunning   transferState = "Running"
+	transferSucceeded transferState = "Succeeded"
+	transferFailed    transferState = "Failed"
+)
+
+// getTransferName returns the name shared by the Secret and Service of a network transfer
+func getTransferName(vrName, volName string, replica int32) string {
+	return fmt.Sprintf("%s-transfer-%d-%s", vrName, replica, volName)
+}
+
+// getSenderPodName returns the name of the pod streaming the old volume
+func getSenderPodName(vrName, volName string, replica int32) string {
+	return fmt.Sprintf("%s-sender-%d-%s", vrName, replica, volName)
+}
+
+// getReceiverPodName returns the name of the pod writing the new volume
+func getReceiverPodName(vrName, volName string, replica int32) string {
+	return fmt.Sprintf("%s-receiver-%d-%s", vrName, replica, volName)
+}
+
+// transferLabels returns the labels put on every object of a network transfer
+func transferLabels(vr *storagev1alpha1.VolumeResize, volName string, replica int32) map[string]string {
+	return map[string]string{
+		LabelMigrationName: vr.Name,
+		LabelReplica:       fmt.Sprintf("%d", replica),
+		LabelVolumeName:    volName,
+	}
+}
+
+// generateTransferToken returns a random token the receiver uses to authenticate the sender
+func generateTransferToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate transfer token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// buildTransferSecret creates the Secret holding the per-migration transfer token
+func buildTransferSecret(vr *storagev1alpha1.VolumeResize, volName string, replica int32, token string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getTransferName(vr.Name, volName, replica),
+			Namespace: vr.Namespace,
+			Labels:    transferLabels(vr, volName, replica),
+			Annotations: map[string]string{
+				AnnotationManagedBy: "volume-resize-operator",
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		StringData: map[string]string{
+			SecretKeyTransferToken: token,
+		},
+	}
+}
+
+// buildTransferService creates the Service the sender connects to
+func buildTransferService(vr *storagev1alpha1.VolumeResize, volName string, replica int32) *corev1.Service {
+	selector := transferLabels(vr, volName, replica)
+	selector[LabelTransferRole] = TransferRoleReceiver
+
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getTransferName(vr.Name, volName, replica),
+			Namespace: vr.Namespace,
+			Labels:    transferLabels(vr, volName, replica),
+			Annotations: map[string]string{
+				AnnotationManagedBy: "volume-resize-operator",
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: selector,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "transfer",
+					Protocol:   corev1.ProtocolTCP,
+					Port:       DefaultTransferPort,
+					TargetPort: intstr.FromInt32(DefaultTransferPort),
+				},
+			},
+		},
+	}
+}
+
+// tokenEnvVar exposes the transfer token from the Secret to a container
+func tokenEnvVar(secretName string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: "TRANSFER_TOKEN",
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  SecretKeyTransferToken,
+			},
+		},
+	}
+}
+
+// buildTransferPod creates a sender or receiver pod with a single PVC mounted
+func buildTransferPod(vr *storagev1alpha1.VolumeResize, volName string, replica int32, role, pvcName string) *corev1.Pod {
+	transferName := getTransferName(vr.Name, volName, replica)
+	labels := transferLabels(vr, volName, replica)
+	labels[LabelTransferRole] = role
+
+	name := getReceiverPodName(vr.Name, volName, replica)
+	mountPath := "/dest"
+	env := []corev1.EnvVar{
+		{Name: "MIGRATION_MODE", Value: MigrationModeReceive},
+		{Name: "DEST_PATH", Value: mountPath},
+		{Name: "TRANSFER_PORT", Value: fmt.Sprintf("%d", DefaultTransferPort)},
+		tokenEnvVar(transferName),
+	}
+	if role == TransferRoleSender {
+		name = getSenderPodName(vr.Name, volName, replica)
+		mountPath = "/source"
+		env = []corev1.EnvVar{
+			{Name: "MIGRATION_MODE", Value: MigrationModeSend},
+			{Name: "SOURCE_PATH", Value: mountPath},
+			{Name: "TRANSFER_HOST", Value: fmt.Sprintf("%s.%s.svc", transferName, vr.Namespace)},
+			{Name: "TRANSFER_PORT", Value: fmt.Sprintf("%d", DefaultTransferPort)},
+			tokenEnvVar(transferName),
+		}
+	}
+
+	container := corev1.Container{
+		Name:            "migrator",
+		Image:           DefaultMigratorImage,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Env:             env,
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      "data",
+				MountPath: mountPath,
+				ReadOnly:  role == TransferRoleSender,
+			},
+		},
+	}
+	if role == TransferRoleReceiver {
+		container.Ports = []corev1.ContainerPort{
+			{Name: "transfer", ContainerPort: DefaultTransferPort, Protocol: corev1.ProtocolTCP},
+		}
+	}
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: vr.Namespace,
+			Labels:    labels,
+			Annotations: map[string]string{
+				AnnotationManagedBy: "volume-resize-operator",
+			},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			// Run as root to be able to read and restore files owned by any user
+			SecurityContext: &corev1.PodSecurityContext{
+				RunAsUser:  new(int64), // 0 = root
+				RunAsGroup: new(int64), // 0 = root
+			},
+			Containers: []corev1.Container{container},
+			Volumes: []corev1.Volume{
+				{
+					Name: "data",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+							ClaimName: pvcName,
+							ReadOnly:  role == TransferRoleSender,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// createIfNotExists creates an object unless one with the same name already exists (idempotency)
+func createIfNotExists(ctx context.Context, c client.Client, obj client.Object) error {
+	existing := obj.DeepCopyObject().(client.Object)
+	err := c.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}, existing)
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to check for existing %s: %w", obj.GetName(), err)
+	}
+	if err := c.Create(ctx, obj); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create %s: %w", obj.GetName(), err)
+	}
+	return nil
+}
+
+// startNetworkTransfer creates the token Secret, the receiver pod and its Service, and the sender pod once
+// the receiver is Ready. The receiver may wait a long time for its volume or node, and the sender only
+// retries its connection for a few minutes.
+// The sender gets the placement so it can attach a node-local or zonal source volume, both pods get its tolerations.
+func startNetworkTransfer(ctx context.Context, c client.Client, vr *storagev1alpha1.VolumeResize, volName string, replica int32, oldPVCName, newPVCName string, placement podPlacement) error {
+	secretName := getTransferName(vr.Name, volName, replica)
+	if err := c.Get(ctx, types.NamespacedName{Namespace: vr.Namespace, Name: secretName}, &corev1.Secret{}); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to check for transfer secret: %w", err)
+		}
+		token, err := generateTransferToken()
+		if err != nil {
+			return err
+		}
+		if err := createIfNotExists(ctx, c, buildTransferSecret(vr, volName, replica, token)); err != nil {
+			return err
+		}
+	}
+
+	receiver := buildTransferPod(vr, volName, replica, TransferRoleReceiver, newPVCName)
+	receiver.Spec.Tolerations = placement.Tolerations
+	if err := createIfNotExists(ctx, c, receiver); err != nil {
+		return err
+	}
+	if err := createIfNotExists(ctx, c, buildTransferService(vr, volName, replica)); err != nil {
+		return err
+	}
+
+	// The Service only routes to a Ready receiver
+	current := &corev1.Pod{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: vr.Namespace, Name: receiver.Name}, current); err != nil {
+		return fmt.Errorf("failed to get receiver pod: %w", err)
+	}
+	if podReadyCondition(current) == nil {
+		return nil
+	}
+
+	sender := buildTransferPod(vr, volName, replica, TransferRoleSender, oldPVCName)
+	sender.Spec.Affinity = placement.Affinity
+	sender.Spec.Tolerations = placement.Tolerations
+	return createIfNotExists(ctx, c, sender)
+}
+
+// getNetworkTransferState reports the progress of a transfer. The receiver verifies the checksums,
+// so only its success counts; a failure of either side fails the transfer. A missing sender is still
+// waiting for the receiver to be Ready.
+func getNetworkTransferState(ctx context.Context, c client.Client, namespace, vrName, volName string, replica int32) (transferState, error) {
+	receiver := &corev1.Pod{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: getReceiverPodName(vrName, volName, replica)}, receiver); err != nil {
+		return "", fmt.Errorf("failed to get receiver pod: %w", err)
+	}
+	sender := &corev1.Pod{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: getSenderPodName(vrName, volName, replica)}, sender); err != nil && !apierrors.IsNotFound(err) {
+		return "", fmt.Errorf("failed to get sender pod: %w", err)
+	}
+
+	switch {
+	case receiver.Status.Phase == corev1.PodFailed || sender.Status.Phase == corev1.PodFailed:
+		return transferFailed, nil
+	case receiver.Status.Phase == corev1.PodSucceeded:
+		return transferSucceeded, nil
+	default:
+		return transferRunning, nil
+	}
+}
+
+// cleanupNetworkTransfer deletes the pods, Service and Secret of a transfer
+func cleanupNetworkTransfer(ctx context.Context, c client.Client, namespace, vrName, volName string, replica int32) error {
+	transferName := getTransferName(vrName, volName, replica)
+	objects := []client.Object{
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: getSenderPodName(vrName, volName, replica)}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: getReceiverPodName(vrName, volName, replica)}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: transferName}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: transferName}},
+	}
+
+	for _, obj := range objects {
+		if err := c.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete %s: %w", obj.GetName(), err)
+		}
+	}
+	return nil
+}
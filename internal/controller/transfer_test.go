@@ -0,0 +1,186 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+)
+
+func envValue(env []corev1.EnvVar, name string) string {
+	for _, e := range env {
+		if e.Name == name {
+			return e.Value
+		}
+	}
+	return ""
+}
+
+func TestBuildTransferPodReceiver(t *testing.T) {
+	vr := &storagev1alpha1.VolumeResize{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-resize", Namespace: "default"},
+	}
+
+	pod := buildTransferPod(vr, "data", 0, TransferRoleReceiver, "data-test-sts-0-new")
+
+	assert.Equal(t, "test-resize-receiver-0-data", pod.Name)
+	assert.Equal(t, TransferRoleReceiver, pod.Labels[LabelTransferRole])
+
+	container := pod.Spec.Containers[0]
+	assert.Equal(t, MigrationModeReceive, envValue(container.Env, "MIGRATION_MODE"))
+	assert.Equal(t, "/dest", envValue(container.Env, "DEST_PATH"))
+	require.Len(t, container.Ports, 1)
+	assert.Equal(t, DefaultTransferPort, container.Ports[0].ContainerPort)
+	assert.False(t, container.VolumeMounts[0].ReadOnly)
+	assert.Equal(t, "data-test-sts-0-new", pod.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+}
+
+func TestBuildTransferPodSender(t *testing.T) {
+	vr := &storagev1alpha1.VolumeResize{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-resize", Namespace: "default"},
+	}
+
+	pod := buildTransferPod(vr, "data", 0, TransferRoleSender, "data-test-sts-0")
+
+	assert.Equal(t, "test-resize-sender-0-data", pod.Name)
+
+	container := pod.Spec.Containers[0]
+	assert.Equal(t, MigrationModeSend, envValue(container.Env, "MIGRATION_MODE"))
+	assert.Equal(t, "test-resize-transfer-0-data.default.svc", envValue(container.Env, "TRANSFER_HOST"))
+	assert.Empty(t, container.Ports)
+	assert.True(t, container.VolumeMounts[0].ReadOnly)
+	assert.True(t, pod.Spec.Volumes[0].PersistentVolumeClaim.ReadOnly)
+
+	// Token comes from the Secret, never inline
+	for _, e := range container.Env {
+		if e.Name == "TRANSFER_TOKEN" {
+			require.NotNil(t, e.ValueFrom)
+			assert.Equal(t, "test-resize-transfer-0-data", e.ValueFrom.SecretKeyRef.Name)
+			assert.Empty(t, e.Value)
+		}
+	}
+}
+
+func TestBuildTransferServiceSelectsReceiver(t *testing.T) {
+	vr := &storagev1alpha1.VolumeResize{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-resize", Namespace: "default"},
+	}
+
+	svc := buildTransferService(vr, "data", 0)
+	receiver := buildTransferPod(vr, "data", 0, TransferRoleReceiver, "new")
+	sender := buildTransferPod(vr, "data", 0, TransferRoleSender, "old")
+
+	matches := func(labels map[string]string) bool {
+		for k, v := range svc.Spec.Selector {
+			if labels[k] != v {
+				return false
+			}
+		}
+		return true
+	}
+	assert.True(t, matches(receiver.Labels))
+	assert.False(t, matches(sender.Labels))
+}
+
+func TestGenerateTransferToken(t *testing.T) {
+	a, err := generateTransferToken()
+	require.NoError(t, err)
+	b, err := generateTransferToken()
+	require.NoError(t, err)
+
+	assert.Len(t, a, 64)
+	assert.NotEqual(t, a, b)
+}
+
+func TestNetworkTransferLifecycle(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	ctx := context.Background()
+
+	vr := &storagev1alpha1.VolumeResize{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-resize", Namespace: "default"},
+	}
+
+	require.NoError(t, startNetworkTransfer(ctx, c, vr, "data", 0, "data-test-sts-0", "data-test-sts-0-new", migrationPlacement(zonalPV("pv-1", "zone-a"), nil)))
+
+	secret := &corev1.Secret{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-resize-transfer-0-data"}, secret))
+	token := secret.StringData[SecretKeyTransferToken]
+	assert.NotEmpty(t, token)
+
+	// The sender waits for the receiver, however long it stays Pending
+	sender := &corev1.Pod{}
+	err := c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-resize-sender-0-data"}, sender)
+	assert.True(t, apierrors.IsNotFound(err))
+	state, err := getNetworkTransferState(ctx, c, "default", "test-resize", "data", 0)
+	require.NoError(t, err)
+	assert.Equal(t, transferRunning, state)
+
+	receiver := &corev1.Pod{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-resize-receiver-0-data"}, receiver))
+	receiver.Status.Phase = corev1.PodRunning
+	receiver.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+	require.NoError(t, c.Status().Update(ctx, receiver))
+
+	// Starting again keeps the existing token
+	require.NoError(t, startNetworkTransfer(ctx, c, vr, "data", 0, "data-test-sts-0", "data-test-sts-0-new", migrationPlacement(zonalPV("pv-1", "zone-a"), nil)))
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-resize-transfer-0-data"}, secret))
+	assert.Equal(t, token, secret.StringData[SecretKeyTransferToken])
+
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-resize-sender-0-data"}, sender))
+	require.NotNil(t, sender.Spec.Affinity)
+
+	state, err = getNetworkTransferState(ctx, c, "default", "test-resize", "data", 0)
+	require.NoError(t, err)
+	assert.Equal(t, transferRunning, state)
+
+	// Sender finishing alone is not enough, the receiver verifies the checksums
+	sender.Status.Phase = corev1.PodSucceeded
+	require.NoError(t, c.Status().Update(ctx, sender))
+	state, err = getNetworkTransferState(ctx, c, "default", "test-resize", "data", 0)
+	require.NoError(t, err)
+	assert.Equal(t, transferRunning, state)
+
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-resize-receiver-0-data"}, receiver))
+	receiver.Status.Phase = corev1.PodFailed
+	require.NoError(t, c.Status().Update(ctx, receiver))
+	state, err = getNetworkTransferState(ctx, c, "default", "test-resize", "data", 0)
+	require.NoError(t, err)
+	assert.Equal(t, transferFailed, state)
+
+	require.NoError(t, cleanupNetworkTransfer(ctx, c, "default", "test-resize", "data", 0))
+	err = c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-resize-transfer-0-data"}, &corev1.Service{})
+	assert.True(t, apierrors.IsNotFound(err))
+	err = c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-resize-sender-0-data"}, &corev1.Pod{})
+	assert.True(t, apierrors.IsNotFound(err))
+
+	// Cleanup is idempotent
+	require.NoError(t, cleanupNetworkTransfer(ctx, c, "default", "test-resize", "data", 0))
+}
@@ -19,10 +19,9 @@ package controller
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	appsv1 "k8s.io/api/apps/v1"
-	policyv1 "k8s.io/api/policy/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -67,10 +66,80 @@ func validateVolumeTargets(sts *appsv1.StatefulSet, volumes []storagev1alpha1.Vo
 	return ValidationResult{Valid: true}
 }
 
+// validateRetentionPolicy refuses a StatefulSet that garbage-collects its PVCs on deletion, since the
+// controller deletes it during the migration, unless the policy may be overridden for the duration
+func validateRetentionPolicy(sts *appsv1.StatefulSet, override bool) ValidationResult {
+	if !deletesPVCsWithSTS(sts) || override {
+		return ValidationResult{Valid: true}
+	}
+	return ValidationResult{
+		Valid: false,
+		Message: fmt.Sprintf("StatefulSet %s has persistentVolumeClaimRetentionPolicy.whenDeleted=Delete, its PVCs would be deleted with it; "+
+			"set spec.overrideRetentionPolicy to switch it to Retain during the migration", sts.Name),
+	}
+}
+
+// validateParentOwner refuses a StatefulSet controlled by another object unless that owner can be paused
+func validateParentOwner(sts *appsv1.StatefulSet, hook *storagev1alpha1.ParentPauseHook) ValidationResult {
+	if _, _, err := resolveParentPause(sts, hook); err != nil {
+		return ValidationResult{Valid: false, Message: err.Error()}
+	}
+	return ValidationResult{Valid: true}
+}
+
+// validateReplicas checks the replicas to migrate exist
+func validateReplicas(ordinals []int32, replicas int32) ValidationResult {
+	if len(ordinals) == 0 {
+		return ValidationResult{Valid: false, Message: "no replica to migrate"}
+	}
+	for _, replica := range ordinals {
+		if replica >= replicas {
+			return ValidationResult{
+				Valid:   false,
+				Message: fmt.Sprintf("replica %d does not exist, the StatefulSet has %d replicas", replica, replicas),
+			}
+		}
+	}
+	return ValidationResult{Valid: true}
+}
+
+// validateOrder checks the replicas of an explicit order are migrated
+func validateOrder(order *storagev1alpha1.ReplicaOrder, ordinals []int32) ValidationResult {
+	if order == nil || order.Type != OrderExplicit {
+		return ValidationResult{Valid: true}
+	}
+	for _, replica := range order.Replicas {
+		if !slices.Contains(ordinals, replica) {
+			return ValidationResult{
+				Valid:   false,
+				Message: fmt.Sprintf("ordered replica %d does not exist or is left out of spec.replicas", replica),
+			}
+		}
+	}
+	return ValidationResult{Valid: true}
+}
+
+// validateCanary checks the canary is one of the migrated replicas, and that replicas are not all migrated at once
+func validateCanary(canary *storagev1alpha1.CanarySpec, offline bool, ordinals []int32) ValidationResult {
+	if canary == nil {
+		return ValidationResult{Valid: true}
+	}
+	if offline {
+		return ValidationResult{Valid: false, Message: fmt.Sprintf("spec.canary is not supported by the %s strategy", StrategyTypeOffline)}
+	}
+	if canary.Replica != nil && !slices.Contains(ordinals, *canary.Replica) {
+		return ValidationResult{
+			Valid:   false,
+			Message: fmt.Sprintf("canary replica %d does not exist or is left out of spec.replicas", *canary.Replica),
+		}
+	}
+	return ValidationResult{Valid: true}
+}
+
 // validateSizeReduction checks that newSize is smaller than the current PVC size
-func validateSizeReduction(ctx context.Context, c client.Client, namespace, stsName string, vol storagev1alpha1.VolumeResizeTarget) ValidationResult {
-	// Get the PVC for replica 0 to check current size
-	pvcName := getOriginalPVCName(vol.Name, stsName, 0)
+func validateSizeReduction(ctx context.Context, c client.Client, namespace, stsName string, vol storagev1alpha1.VolumeResizeTarget, replica int32) ValidationResult {
+	// Get the PVC of the replica to check current size
+	pvcName := getOriginalPVCName(vol.Name, stsName, replica)
 	pvc, err := getPVC(ctx, c, namespace, pvcName)
 	if err != nil {
 		return ValidationResult{
@@ -100,38 +169,94 @@ func validateSizeReduction(ctx context.Context, c client.Client, namespace, stsN
 
 // validatePDBAllowsDisruption checks that no PDB blocks pod disruption for the StatefulSet
 func validatePDBAllowsDisruption(ctx context.Context, c client.Client, sts *appsv1.StatefulSet) ValidationResult {
-	// List all PDBs in the namespace
-	pdbList := &policyv1.PodDisruptionBudgetList{}
-	if err := c.List(ctx, pdbList, client.InNamespace(sts.Namespace)); err != nil {
+	pdbs, err := matchingPDBs(ctx, c, sts)
+	if err != nil {
 		return ValidationResult{
 			Valid:   false,
-			Message: fmt.Sprintf("failed to list PodDisruptionBudgets: %v", err),
+			Message: err.Error(),
 		}
 	}
 
-	// Get StatefulSet pod labels
-	podLabels := sts.Spec.Template.Labels
+	for _, pdb := range pdbs {
+		if pdb.Status.DisruptionsAllowed < 1 {
+			return ValidationResult{
+				Valid:   false,
+				Message: fmt.Sprintf("PodDisruptionBudget %s does not allow disruptions (disruptionsAllowed=%d)", pdb.Name, pdb.Status.DisruptionsAllowed),
+			}
+		}
+	}
 
-	for _, pdb := range pdbList.Items {
-		if pdb.Spec.Selector == nil {
-			continue
+	return ValidationResult{Valid: true}
+}
+
+// validateBlockMode checks that block-mode volumes come with a block copy strategy that fits the new size
+func validateBlockMode(ctx context.Context, c client.Client, namespace, stsName string, vol storagev1alpha1.VolumeResizeTarget, replica int32) ValidationResult {
+	pvcName := getOriginalPVCName(vol.Name, stsName, replica)
+	pvc, err := getPVC(ctx, c, namespace, pvcName)
+	if err != nil {
+		return ValidationResult{
+			Valid:   false,
+			Message: fmt.Sprintf("failed to get PVC %s: %v", pvcName, err),
 		}
+	}
 
-		selector, err := labels.ValidatedSelectorFromSet(pdb.Spec.Selector.MatchLabels)
-		if err != nil {
-			continue
+	if !isBlockPVC(pvc) {
+		return ValidationResult{Valid: true}
+	}
+
+	if vol.Block == nil {
+		return ValidationResult{
+			Valid: false,
+			Message: fmt.Sprintf("volume %s uses volumeMode Block: set block.mode to Filesystem (ext2/3/4 on the device) "+
+				"or Length (bytes of application data) so the data can be verified to fit", vol.Name),
 		}
+	}
 
-		// Check if PDB selector matches StatefulSet pods
-		if selector.Matches(labels.Set(podLabels)) {
-			// Check if disruptions are allowed
-			if pdb.Status.DisruptionsAllowed < 1 {
-				return ValidationResult{
-					Valid:   false,
-					Message: fmt.Sprintf("PodDisruptionBudget %s does not allow disruptions (disruptionsAllowed=%d)", pdb.Name, pdb.Status.DisruptionsAllowed),
-				}
+	switch vol.Block.Mode {
+	case BlockCopyModeFilesystem:
+		return ValidationResult{Valid: true}
+	case BlockCopyModeLength:
+		if vol.Block.Length == nil || vol.Block.Length.Sign() <= 0 {
+			return ValidationResult{
+				Valid:   false,
+				Message: fmt.Sprintf("volume %s: block.length must be set to a positive size when block.mode is Length", vol.Name),
+			}
+		}
+		if vol.Block.Length.Cmp(vol.NewSize) > 0 {
+			return ValidationResult{
+				Valid:   false,
+				Message: fmt.Sprintf("volume %s: block.length (%s) does not fit in newSize (%s)", vol.Name, vol.Block.Length.String(), vol.NewSize.String()),
 			}
 		}
+		return ValidationResult{Valid: true}
+	default:
+		return ValidationResult{
+			Valid:   false,
+			Message: fmt.Sprintf("volume %s: unsupported block.mode %q", vol.Name, vol.Block.Mode),
+		}
+	}
+}
+
+// validateTransferMode checks that the volume can be copied with the requested transfer mode
+func validateTransferMode(ctx context.Context, c client.Client, namespace, stsName, transferMode string, vol storagev1alpha1.VolumeResizeTarget, replica int32) ValidationResult {
+	if transferMode != TransferModeNetwork {
+		return ValidationResult{Valid: true}
+	}
+
+	pvcName := getOriginalPVCName(vol.Name, stsName, replica)
+	pvc, err := getPVC(ctx, c, namespace, pvcName)
+	if err != nil {
+		return ValidationResult{
+			Valid:   false,
+			Message: fmt.Sprintf("failed to get PVC %s: %v", pvcName, err),
+		}
+	}
+
+	if isBlockPVC(pvc) {
+		return ValidationResult{
+			Valid:   false,
+			Message: fmt.Sprintf("volume %s uses volumeMode Block, which is only supported with transferMode Local", vol.Name),
+		}
 	}
 
 	return ValidationResult{Valid: true}
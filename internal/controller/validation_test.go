@@ -135,7 +135,7 @@ func TestValidateSizeReductionInvalid(t *testing.T) {
 		NewSize: resource.MustParse("1Gi"),
 	}
 
-	result := validateSizeReduction(ctx, c, "default", "test-sts", vol)
+	result := validateSizeReduction(ctx, c, "default", "test-sts", vol, 0)
 	assert.False(t, result.Valid)
 	assert.Contains(t, result.Message, "must be smaller")
 }
@@ -167,7 +167,7 @@ func TestValidateSizeReductionValid(t *testing.T) {
 		NewSize: resource.MustParse("500Mi"),
 	}
 
-	result := validateSizeReduction(ctx, c, "default", "test-sts", vol)
+	result := validateSizeReduction(ctx, c, "default", "test-sts", vol, 0)
 	assert.True(t, result.Valid)
 }
 
@@ -279,3 +279,122 @@ func TestValidateNoPDBPresent(t *testing.T) {
 	result := validatePDBAllowsDisruption(ctx, c, sts)
 	assert.True(t, result.Valid)
 }
+
+func blockPVCForValidation(name string) *corev1.PersistentVolumeClaim {
+	blockMode := corev1.PersistentVolumeBlock
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			VolumeMode: &blockMode,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse("1Gi"),
+				},
+			},
+		},
+	}
+}
+
+func TestValidateBlockModeRequiresStrategy(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(blockPVCForValidation("data-test-sts-0")).Build()
+	ctx := context.Background()
+
+	vol := storagev1alpha1.VolumeResizeTarget{
+		Name:    "data",
+		NewSize: resource.MustParse("500Mi"),
+	}
+
+	result := validateBlockMode(ctx, c, "default", "test-sts", vol, 0)
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Message, "volumeMode Block")
+}
+
+func TestValidateBlockModeLengthTooLarge(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(blockPVCForValidation("data-test-sts-0")).Build()
+	ctx := context.Background()
+
+	length := resource.MustParse("600Mi")
+	vol := storagev1alpha1.VolumeResizeTarget{
+		Name:    "data",
+		NewSize: resource.MustParse("500Mi"),
+		Block:   &storagev1alpha1.BlockCopySpec{Mode: BlockCopyModeLength, Length: &length},
+	}
+
+	result := validateBlockMode(ctx, c, "default", "test-sts", vol, 0)
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Message, "does not fit")
+}
+
+func TestValidateBlockModeValid(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(blockPVCForValidation("data-test-sts-0")).Build()
+	ctx := context.Background()
+
+	vol := storagev1alpha1.VolumeResizeTarget{
+		Name:    "data",
+		NewSize: resource.MustParse("500Mi"),
+		Block:   &storagev1alpha1.BlockCopySpec{Mode: BlockCopyModeFilesystem},
+	}
+
+	result := validateBlockMode(ctx, c, "default", "test-sts", vol, 0)
+	assert.True(t, result.Valid)
+}
+
+func TestValidateTransferModeRejectsBlock(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(blockPVCForValidation("data-test-sts-0")).Build()
+	ctx := context.Background()
+
+	vol := storagev1alpha1.VolumeResizeTarget{
+		Name:    "data",
+		NewSize: resource.MustParse("500Mi"),
+		Block:   &storagev1alpha1.BlockCopySpec{Mode: BlockCopyModeFilesystem},
+	}
+
+	result := validateTransferMode(ctx, c, "default", "test-sts", TransferModeNetwork, vol, 0)
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Message, "transferMode Local")
+
+	result = validateTransferMode(ctx, c, "default", "test-sts", TransferModeLocal, vol, 0)
+	assert.True(t, result.Valid)
+}
+
+func TestValidateRetentionPolicy(t *testing.T) {
+	sts := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "test-sts"}}
+	assert.True(t, validateRetentionPolicy(sts, false).Valid)
+
+	sts.Spec.PersistentVolumeClaimRetentionPolicy = &appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+		WhenDeleted: appsv1.DeletePersistentVolumeClaimRetentionPolicyType,
+	}
+	result := validateRetentionPolicy(sts, false)
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Message, "overrideRetentionPolicy")
+
+	assert.True(t, validateRetentionPolicy(sts, true).Valid)
+}
+
+func TestValidateCanary(t *testing.T) {
+	assert.True(t, validateCanary(nil, true, []int32{0, 1, 2}).Valid)
+
+	canary := &storagev1alpha1.CanarySpec{}
+	assert.True(t, validateCanary(canary, false, []int32{0, 1, 2}).Valid)
+	assert.False(t, validateCanary(canary, true, []int32{0, 1, 2}).Valid)
+
+	canary.Replica = ptrInt32(3)
+	result := validateCanary(canary, false, []int32{0, 1, 2})
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Message, "canary replica 3 does not exist")
+}
@@ -19,6 +19,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -28,6 +29,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -39,20 +42,34 @@ import (
 // VolumeResizeReconciler reconciles a VolumeResize object
 type VolumeResizeReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder events.EventRecorder
+	// Executor runs the commands of exec health gates
+	Executor PodExecutor
 }
 
 // +kubebuilder:rbac:groups=storage.maurice.fr,resources=volumeresizes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=storage.maurice.fr,resources=volumeresizes/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=storage.maurice.fr,resources=volumeresizes/finalizers,verbs=update
-// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;delete;create
+// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;delete;create;patch
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;delete;create
-// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;delete;create
+// +kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;delete;create;patch
 // +kubebuilder:rbac:groups="",resources=persistentvolumes,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;patch
+// +kubebuilder:rbac:groups=kafka.strimzi.io,resources=kafkas,verbs=get;patch
+// +kubebuilder:rbac:groups=elasticsearch.k8s.elastic.co,resources=elasticsearches,verbs=get;patch
+// +kubebuilder:rbac:groups=argoproj.io,resources=applications,verbs=get;patch
+// +kubebuilder:rbac:groups=kustomize.toolkit.fluxcd.io,resources=kustomizations,verbs=get;patch
+// +kubebuilder:rbac:groups=helm.toolkit.fluxcd.io,resources=helmreleases,verbs=get;patch
 // +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch
 // +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
-// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=services;secrets,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
 
 // Reconcile is the main reconciliation loop
 func (r *VolumeResizeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -81,6 +98,15 @@ func (r *VolumeResizeReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{Requeue: true}, nil
 	}
 
+	// Abort takes precedence over every phase that is not final yet
+	if vr.Spec.Abort && vr.Status.Phase != PhaseCompleted && vr.Status.Phase != PhaseAborted {
+		return r.handleAbort(ctx, vr)
+	}
+
+	if vr.Spec.RetryGeneration != vr.Status.ObservedRetryGeneration {
+		return r.handleRetry(ctx, vr)
+	}
+
 	// Route to phase handler
 	switch vr.Status.Phase {
 	case "", PhasePending:
@@ -91,7 +117,9 @@ func (r *VolumeResizeReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return r.handleSyncing(ctx, vr)
 	case PhaseReplacing:
 		return r.handleReplacing(ctx, vr)
-	case PhaseCompleted, PhaseFailed:
+	case PhasePaused:
+		return r.handlePaused(ctx, vr)
+	case PhaseCompleted, PhaseFailed, PhaseAborted:
 		// Terminal states, no action needed
 		return ctrl.Result{}, nil
 	default:
@@ -130,29 +158,123 @@ func (r *VolumeResizeReconciler) handleValidating(ctx context.Context, vr *stora
 		return r.setFailed(ctx, vr, result.Message)
 	}
 
-	// Validate size reduction for each volume
-	for _, vol := range vr.Spec.Volumes {
-		result = validateSizeReduction(ctx, r.Client, vr.Namespace, vr.Spec.StatefulSetName, vol)
+	// Validate the maintenance windows, so a typo does not hold the migration forever
+	if errs := ValidateSchedule(vr.Spec.Schedule, field.NewPath("spec", "schedule")); len(errs) > 0 {
+		return r.setFailed(ctx, vr, errs.ToAggregate().Error())
+	}
+	if errs := ValidateHealthGates(vr.Spec.HealthGates, field.NewPath("spec", "healthGates")); len(errs) > 0 {
+		return r.setFailed(ctx, vr, errs.ToAggregate().Error())
+	}
+	if errs := ValidateHooks(vr.Spec.Hooks, field.NewPath("spec", "hooks")); len(errs) > 0 {
+		return r.setFailed(ctx, vr, errs.ToAggregate().Error())
+	}
+	if errs := ValidateOrder(vr.Spec.Order, field.NewPath("spec", "order")); len(errs) > 0 {
+		return r.setFailed(ctx, vr, errs.ToAggregate().Error())
+	}
+	if errs := ValidateReplicas(vr.Spec.Replicas, field.NewPath("spec", "replicas")); len(errs) > 0 {
+		return r.setFailed(ctx, vr, errs.ToAggregate().Error())
+	}
+
+	// Validate the PVCs survive the StatefulSet being deleted
+	result = validateRetentionPolicy(sts, vr.Spec.OverrideRetentionPolicy)
+	if !result.Valid {
+		return r.setFailed(ctx, vr, result.Message)
+	}
+
+	// Validate the controller owning the StatefulSet can be kept from recreating it
+	result = validateParentOwner(sts, vr.Spec.ParentPause)
+	if !result.Valid {
+		return r.setFailed(ctx, vr, result.Message)
+	}
+
+	// Argo CD or Flux may recreate the StatefulSet from Git while it is gone
+	r.warnGitOpsTracked(ctx, vr, sts)
+
+	// Validate PDB allows disruption, the Offline strategy takes the StatefulSet down on purpose
+	offline := isOfflineStrategy(vr.Spec.Strategy)
+	if !offline {
+		result = validatePDBAllowsDisruption(ctx, r.Client, sts)
 		if !result.Valid {
 			return r.setFailed(ctx, vr, result.Message)
 		}
 	}
 
-	// Validate PDB allows disruption
-	result = validatePDBAllowsDisruption(ctx, r.Client, sts)
+	replicas := int32(1)
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+
+	// A StatefulSet scaled to zero still has PVCs, migrate every ordinal that has one
+	if replicas == 0 {
+		if !offline {
+			return r.setFailed(ctx, vr, fmt.Sprintf("StatefulSet %s is scaled to zero, use strategy type %s", sts.Name, StrategyTypeOffline))
+		}
+		count, err := countReplicaPVCs(ctx, r.Client, vr.Namespace, sts.Name, vr.Spec.Volumes[0].Name)
+		if err != nil {
+			return r.setFailed(ctx, vr, err.Error())
+		}
+		replicas = count
+	}
+
+	// Validate the subset only names existing replicas
+	ordinals, err := selectedReplicas(vr.Spec.Replicas, replicas)
+	if err != nil {
+		return r.setFailed(ctx, vr, err.Error())
+	}
+	result = validateReplicas(ordinals, replicas)
 	if !result.Valid {
 		return r.setFailed(ctx, vr, result.Message)
 	}
 
+	// Validate the canary can be migrated on its own
+	result = validateCanary(vr.Spec.Canary, offline, ordinals)
+	if !result.Valid {
+		return r.setFailed(ctx, vr, result.Message)
+	}
+
+	// Validate the explicit order only names existing replicas
+	result = validateOrder(vr.Spec.Order, ordinals)
+	if !result.Valid {
+		return r.setFailed(ctx, vr, result.Message)
+	}
+
+	// Validate the strategy resolves to a usable batch size
+	if _, err := resolveMaxUnavailable(vr.Spec.Strategy, replicas); err != nil {
+		return r.setFailed(ctx, vr, err.Error())
+	}
+
+	// Validate every migrated replica can be copied and shrunk, and that a node can attach both its old
+	// and new volume. Replicas left out are not checked, they may already have the new size.
+	colocated := vr.Spec.TransferMode != TransferModeNetwork
+	for _, vol := range vr.Spec.Volumes {
+		for _, replica := range ordinals {
+			result = validateBlockMode(ctx, r.Client, vr.Namespace, vr.Spec.StatefulSetName, vol, replica)
+			if !result.Valid {
+				return r.setFailed(ctx, vr, result.Message)
+			}
+
+			result = validateTransferMode(ctx, r.Client, vr.Namespace, vr.Spec.StatefulSetName, vr.Spec.TransferMode, vol, replica)
+			if !result.Valid {
+				return r.setFailed(ctx, vr, result.Message)
+			}
+
+			result = validateSizeReduction(ctx, r.Client, vr.Namespace, vr.Spec.StatefulSetName, vol, replica)
+			if !result.Valid {
+				return r.setFailed(ctx, vr, result.Message)
+			}
+		}
+
+		result = validateTopology(ctx, r.Client, sts, vol, ordinals, colocated)
+		if !result.Valid {
+			return r.setFailed(ctx, vr, result.Message)
+		}
+	}
+
 	log.Info("Validation passed, starting sync phase")
 
 	// Initialize volume statuses
-	replicas := int32(1)
-	if sts.Spec.Replicas != nil {
-		replicas = *sts.Spec.Replicas
-	}
 
-	for i := int32(0); i < replicas; i++ {
+	for _, i := range ordinals {
 		for _, vol := range vr.Spec.Volumes {
 			vr.Status.VolumeStatuses = append(vr.Status.VolumeStatuses, storagev1alpha1.VolumeStatus{
 				VolumeName: vol.Name,
@@ -162,11 +284,14 @@ func (r *VolumeResizeReconciler) handleValidating(ctx context.Context, vr *stora
 		}
 	}
 
-	// Transition to Syncing
+	// Transition to Syncing, the first batch is picked on the next reconcile
 	vr.Status.Phase = PhaseSyncing
-	vr.Status.CurrentReplica = ptrInt32(0)
-	vr.Status.CurrentVolume = vr.Spec.Volumes[0].Name
+	vr.Status.InFlightReplicas = nil
+	vr.Status.StatefulSetReplicas = replicas
 	vr.Status.Message = "Validation complete, starting sync"
+	if err := r.updatePartiallyResized(ctx, vr, sts); err != nil {
+		return ctrl.Result{}, err
+	}
 
 	if err := r.Status().Update(ctx, vr); err != nil {
 		return ctrl.Result{}, err
@@ -174,62 +299,405 @@ func (r *VolumeResizeReconciler) handleValidating(ctx context.Context, vr *stora
 	return ctrl.Result{Requeue: true}, nil
 }
 
-// handleSyncing handles the data synchronization phase
-//
-//nolint:gocyclo // Complex state machine with multiple phases - refactoring would increase risk
+// handleSyncing migrates the in-flight replicas and starts the next batch once they are back online
 func (r *VolumeResizeReconciler) handleSyncing(ctx context.Context, vr *storagev1alpha1.VolumeResize) (ctrl.Result, error) {
 	log := logf.FromContext(ctx)
 
-	replica := *vr.Status.CurrentReplica
-	volName := vr.Status.CurrentVolume
-
-	// Check if current volume/replica is already completed - if so, advance to next
-	for _, vs := range vr.Status.VolumeStatuses {
-		if vs.VolumeName == volName && vs.Replica == replica && vs.Phase == VolumeStatusCompleted {
-			log.Info("Volume already completed, advancing to next", "replica", replica, "volume", volName)
-			nextReplica, nextVol, done := r.getNextVolumeReplica(vr, replica, volName)
-			if done {
-				vr.Status.Phase = PhaseCompleted
-				now := metav1.Now()
-				vr.Status.CompletionTime = &now
-				vr.Status.Message = MessageMigrationCompleted
-				vr.Status.CurrentReplica = nil
-				vr.Status.CurrentVolume = ""
-			} else {
-				vr.Status.CurrentReplica = &nextReplica
-				vr.Status.CurrentVolume = nextVol
-				vr.Status.Message = fmt.Sprintf("Migrating replica %d volume %s", nextReplica, nextVol)
+	if len(vr.Status.InFlightReplicas) == 0 {
+		return r.startNextBatch(ctx, vr)
+	}
+	batch := vr.Status.InFlightReplicas
+
+	// Prepare the temp PVCs while the replicas are still serving
+	migrations := []*volumeMigration{}
+	for _, replica := range batch {
+		for _, vol := range vr.Spec.Volumes {
+			phase := getVolumePhase(vr.Status.VolumeStatuses, vol.Name, replica)
+			if phase == VolumeStatusCompleted || phase == VolumeStatusFailed {
+				continue
+			}
+
+			m, ready, err := r.prepareVolume(ctx, vr, vol, replica)
+			if err != nil {
+				return r.setFailed(ctx, vr, err.Error())
 			}
-			if err := r.Status().Update(ctx, vr); err != nil {
+			if !ready {
+				log.Info("Waiting for temp PVC to be bound", "pvc", getTempPVCName(vol.Name, vr.Spec.StatefulSetName, replica))
+				return ctrl.Result{RequeueAfter: time.Second * 5}, nil
+			}
+			migrations = append(migrations, m)
+		}
+	}
+
+	if len(migrations) > 0 {
+		// Persist the prepared volumes before touching the StatefulSet
+		if err := r.Status().Update(ctx, vr); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		// The preStop hooks still reach the pods, they only run before the StatefulSet goes away
+		if vr.Annotations[AnnotationSTSDeleted] != "true" {
+			done, err := r.runHooks(ctx, vr, HookStagePreStop, batch)
+			if err != nil {
 				return ctrl.Result{}, err
 			}
-			return ctrl.Result{Requeue: true}, nil
+			if !done {
+				return ctrl.Result{RequeueAfter: hookPollInterval}, nil
+			}
+			if hs := failedHook(vr.Status.Hooks, batch); hs != nil {
+				return r.setFailed(ctx, vr, hookFailureMessage(hs))
+			}
+		}
+
+		if err := r.ensureSTSDeleted(ctx, vr); err != nil {
+			return r.setFailed(ctx, vr, err.Error())
+		}
+
+		// Delete the batch's pods and wait for them to terminate
+		for _, replica := range batch {
+			if err := deletePod(ctx, r.Client, vr.Namespace, vr.Spec.StatefulSetName, replica); err != nil {
+				log.Error(err, "Failed to delete pod", "pod", getPodName(vr.Spec.StatefulSetName, replica))
+			}
+		}
+		for _, replica := range batch {
+			podName := getPodName(vr.Spec.StatefulSetName, replica)
+			if err := waitForPodTermination(ctx, r.Client, vr.Namespace, podName, time.Minute*2); err != nil {
+				log.Info("Waiting for pod termination", "pod", podName)
+				return ctrl.Result{RequeueAfter: time.Second * 5}, nil
+			}
+		}
+
+		// Copy every volume of the batch, a failure stops the migration once the batch is back online
+		running := false
+		for _, m := range migrations {
+			state, err := r.copyVolume(ctx, vr, m)
+			if err != nil {
+				return r.setFailed(ctx, vr, err.Error())
+			}
+
+			switch state {
+			case transferRunning:
+				log.Info("Migration in progress", "replica", m.replica, "volume", m.vol.Name)
+				running = true
+			case transferFailed:
+				log.Info("Migration failed, no further replicas will be started", "replica", m.replica, "volume", m.vol.Name)
+				r.updateVolumeStatus(vr, m.vol.Name, m.replica, VolumeStatusFailed, "Data copy failed, check the migrator pod logs")
+			case transferSucceeded:
+				r.updateVolumeStatus(vr, m.vol.Name, m.replica, VolumeStatusSynced, "Migration complete")
+
+				// Record where the data lives before swapping, so a deletion mid-swap can rebind it
+				tempPVC, err := getPVC(ctx, r.Client, vr.Namespace, m.tempPVCName)
+				if err != nil {
+					return r.setFailed(ctx, vr, fmt.Sprintf("failed to get temp PVC: %v", err))
+				}
+				vr.Status.VolumeStatuses = updateVolumeStatusInList(vr.Status.VolumeStatuses, m.vol.Name, m.replica, func(vs *storagev1alpha1.VolumeStatus) {
+					vs.NewPVName = tempPVC.Spec.VolumeName
+				})
+				if err := r.Status().Update(ctx, vr); err != nil {
+					return ctrl.Result{}, err
+				}
+
+				log.Info("Replacing PVC for replica", "replica", m.replica, "volume", m.vol.Name)
+				if err := replacePVC(ctx, r.Client, vr, m.vol, m.replica); err != nil {
+					return r.setFailed(ctx, vr, fmt.Sprintf("failed to replace PVC: %v", err))
+				}
+				r.updateVolumeStatus(vr, m.vol.Name, m.replica, VolumeStatusCompleted, "PVC replaced")
+			}
+		}
+
+		// IMPORTANT: Persist the status NOW so a replaced volume is never processed again
+		if err := r.Status().Update(ctx, vr); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		if running {
+			return ctrl.Result{RequeueAfter: time.Second * 10}, nil
+		}
+	}
+
+	// The postCopy hooks run while the pods are still down, a failure stops the migration once they are back
+	failed := failedVolumes(vr.Status.VolumeStatuses, batch)
+	if len(failed) == 0 {
+		done, err := r.runHooks(ctx, vr, HookStagePostCopy, batch)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !done {
+			return ctrl.Result{RequeueAfter: hookPollInterval}, nil
+		}
+	}
+
+	// Every volume of the batch is done, recreate the STS to bring the replicas back online
+	// (replicas outside the batch are still running with old PVCs - that's fine)
+	if err := r.restoreSTS(ctx, vr); err != nil {
+		return r.setFailed(ctx, vr, err.Error())
+	}
+
+	// Wait for the pods to have been ready for minReadySeconds before proceeding. Ordinals above the STS
+	// replicas (a StatefulSet scaled to zero) only had their volumes migrated and have no pod to wait for.
+	sts := &appsv1.StatefulSet{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: vr.Namespace, Name: vr.Spec.StatefulSetName}, sts); err != nil {
+		return ctrl.Result{}, err
+	}
+	desiredReplicas := int32(1)
+	if sts.Spec.Replicas != nil {
+		desiredReplicas = *sts.Spec.Replicas
+	}
+	minReady := minReadySeconds(vr, sts.Spec.MinReadySeconds)
+	for _, replica := range batch {
+		if replica >= desiredReplicas {
+			continue
+		}
+		podName := getPodName(vr.Spec.StatefulSetName, replica)
+		pod := &corev1.Pod{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: vr.Namespace, Name: podName}, pod); err != nil {
+			if apierrors.IsNotFound(err) {
+				log.Info("Waiting for pod to be recreated", "pod", podName)
+				return ctrl.Result{RequeueAfter: time.Second * 5}, nil
+			}
+			return ctrl.Result{}, err
+		}
+		if available, left := podAvailable(pod, minReady, time.Now()); !available {
+			log.Info("Waiting for pod to be ready", "pod", podName, "phase", pod.Status.Phase, "minReadySeconds", minReady)
+			return ctrl.Result{RequeueAfter: max(left, time.Second*5)}, nil
+		}
+	}
+
+	// The postStart hooks let the replicas rejoin, then the health gates tell when the next ones can go
+	// since a quorum-based workload may still be recovering
+	hookFailed := failedHook(vr.Status.Hooks, batch)
+	if len(failed) == 0 && hookFailed == nil {
+		done, err := r.runHooks(ctx, vr, HookStagePostStart, batch)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !done {
+			return ctrl.Result{RequeueAfter: hookPollInterval}, nil
+		}
+		hookFailed = failedHook(vr.Status.Hooks, batch)
+	}
+	if len(failed) == 0 && hookFailed == nil {
+		passed, err := r.checkHealthGates(ctx, vr, batch, desiredReplicas)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !passed {
+			return ctrl.Result{RequeueAfter: healthGatePollInterval}, nil
+		}
+	}
+
+	vr.Status.InFlightReplicas = nil
+	vr.Status.CurrentReplica = nil
+
+	if len(failed) > 0 {
+		return r.setFailed(ctx, vr, fmt.Sprintf("migration failed for %s, the old volumes are retained", strings.Join(failed, ", ")))
+	}
+	if hookFailed != nil {
+		return r.setFailed(ctx, vr, hookFailureMessage(hookFailed))
+	}
+
+	log.Info("Batch migration complete, pods are back online", "replicas", batch)
+	vr.Status.Message = fmt.Sprintf("Replicas %s migrated", FormatReplicas(batch))
+	startCanarySoak(vr, batch)
+	if err := r.updatePartiallyResized(ctx, vr, sts); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.Status().Update(ctx, vr); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// startNextBatch picks the next replicas to migrate, bounded by maxUnavailable and the PodDisruptionBudget
+func (r *VolumeResizeReconciler) startNextBatch(ctx context.Context, vr *storagev1alpha1.VolumeResize) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// The canary soaks before anything else, even the completion of a single-replica migration
+	if result, waiting, err := r.soakCanary(ctx, vr); err != nil || waiting {
+		return result, err
+	}
+
+	pending := pendingReplicas(vr.Status.VolumeStatuses)
+	if len(pending) == 0 {
+		// All replicas done - go to Completed (skip Replacing phase)
+		vr.Status.Phase = PhaseCompleted
+		now := metav1.Now()
+		vr.Status.CompletionTime = &now
+		vr.Status.Message = MessageMigrationCompleted
+		vr.Status.CurrentReplica = nil
+		vr.Status.CurrentVolume = ""
+		if err := r.resumeParent(ctx, vr); err != nil {
+			return ctrl.Result{}, err
 		}
+		if err := r.resumeGitOps(ctx, vr); err != nil {
+			return ctrl.Result{}, err
+		}
+		// Hook Jobs are kept for their logs while a hook can still fail the migration
+		if err := r.deleteMigrationJobs(ctx, vr, LabelHook); err != nil {
+			return ctrl.Result{}, err
+		}
+		log.Info(MessageMigrationCompleted)
+		if err := r.Status().Update(ctx, vr); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
 	}
 
-	// Find the volume target
-	var vol storagev1alpha1.VolumeResizeTarget
-	for _, v := range vr.Spec.Volumes {
-		if v.Name == volName {
-			vol = v
-			break
+	// Pausing only takes effect between batches, so no replica is left half-migrated
+	if vr.Spec.Paused {
+		log.Info("Migration paused")
+		vr.Status.Phase = PhasePaused
+		vr.Status.Message = fmt.Sprintf("Paused, replicas %s remaining", FormatReplicas(pending))
+		if err := r.Status().Update(ctx, vr); err != nil {
+			return ctrl.Result{}, err
 		}
+		return ctrl.Result{}, nil
 	}
 
-	log.Info("Processing volume", "replica", replica, "volume", volName)
+	// The order is decided again before every batch, the leader may have moved since the last one
+	pending, err := r.orderReplicas(ctx, vr, pending)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Approval is asked before the schedule is checked, so it can be given ahead of the window
+	approved, err := r.checkApproval(ctx, vr, pending[0])
+	if err != nil || !approved {
+		return ctrl.Result{}, err
+	}
+
+	// A replica in flight is finished when its window closes, new ones wait for the next
+	next, err := nextScheduledStart(vr.Spec.Schedule, time.Now())
+	if err != nil {
+		return r.setFailed(ctx, vr, err.Error())
+	}
+	if !next.IsZero() {
+		return r.waitForSchedule(ctx, vr, next)
+	}
+
+	maxUnavailable, err := resolveMaxUnavailable(vr.Spec.Strategy, maxUnavailableBase(vr))
+	if err != nil {
+		return r.setFailed(ctx, vr, err.Error())
+	}
+	size := min(maxUnavailable, int32(len(pending)))
+
+	// An approval covers a single replica, so PerReplica ignores maxUnavailable and migrates one at a time
+	if vr.Spec.Approval == ApprovalPerReplica {
+		size = 1
+	}
+
+	// Offline migrations take every replica down in one go, regardless of the PDB
+	offline := isOfflineStrategy(vr.Spec.Strategy)
+	if offline {
+		size = int32(len(pending))
+	}
+
+	// The STS is still deleted if the operator restarted mid-batch, its pods are already down then
+	if !offline && vr.Annotations[AnnotationSTSDeleted] != "true" {
+		sts := &appsv1.StatefulSet{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: vr.Namespace, Name: vr.Spec.StatefulSetName}, sts); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		allowed, limited, err := getPDBDisruptionsAllowed(ctx, r.Client, sts)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if limited {
+			if allowed < 1 {
+				log.Info("Waiting for the PodDisruptionBudget to allow disruptions")
+				vr.Status.Message = "Waiting for the PodDisruptionBudget to allow disruptions"
+				if err := r.Status().Update(ctx, vr); err != nil {
+					return ctrl.Result{}, err
+				}
+				return ctrl.Result{RequeueAfter: time.Second * 30}, nil
+			}
+			size = min(size, allowed)
+		}
+	}
+
+	batch := pending[:size]
+
+	// The canary goes alone, the other replicas wait for its soak
+	if replica, ok := canaryReplica(vr, pending); ok {
+		batch = []int32{replica}
+		vr.Status.Canary = &storagev1alpha1.CanaryStatus{Replica: replica, Phase: CanaryPhaseMigrating}
+	}
+	clearHookStatuses(vr, batch)
+	vr.Status.InFlightReplicas = batch
+	vr.Status.CurrentReplica = ptrInt32(batch[0])
+	vr.Status.CurrentVolume = ""
+	vr.Status.Message = fmt.Sprintf("Migrating replicas %s", FormatReplicas(batch))
+	if offline {
+		vr.Status.Message = fmt.Sprintf("Migrating replicas %s offline", FormatReplicas(batch))
+	}
+	log.Info("Starting batch", "replicas", batch)
+
+	if err := r.Status().Update(ctx, vr); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// volumeMigration holds what is needed to copy one volume of one replica
+type volumeMigration struct {
+	vol             storagev1alpha1.VolumeResizeTarget
+	replica         int32
+	originalPVC     *corev1.PersistentVolumeClaim
+	originalPVCName string
+	tempPVCName     string
+	placement       podPlacement
+}
+
+// prepareVolume creates the temp PVC of a volume and retains the old PV.
+// Returns ready once the temp PVC can be used by the migrator.
+func (r *VolumeResizeReconciler) prepareVolume(ctx context.Context, vr *storagev1alpha1.VolumeResize, vol storagev1alpha1.VolumeResizeTarget, replica int32) (*volumeMigration, bool, error) {
+	log := logf.FromContext(ctx)
+	log.Info("Processing volume", "replica", replica, "volume", vol.Name)
 
 	// Get original PVC
-	originalPVCName := getOriginalPVCName(volName, vr.Spec.StatefulSetName, replica)
+	originalPVCName := getOriginalPVCName(vol.Name, vr.Spec.StatefulSetName, replica)
 	originalPVC, err := getPVC(ctx, r.Client, vr.Namespace, originalPVCName)
 	if err != nil {
-		return r.setFailed(ctx, vr, fmt.Sprintf("failed to get original PVC: %v", err))
+		return nil, false, fmt.Errorf("failed to get original PVC: %w", err)
+	}
+
+	// The pods go wherever the old PV can be attached and the StatefulSet's pods are tolerated
+	oldPV, err := getPV(ctx, r.Client, originalPVC.Spec.VolumeName)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get original PV: %w", err)
+	}
+	tolerations, err := r.statefulSetTolerations(ctx, vr)
+	if err != nil {
+		return nil, false, err
+	}
+	migrationNode, err := selectMigrationNode(ctx, r.Client, oldPV, tolerations)
+	if err != nil {
+		return nil, false, fmt.Errorf("no compatible topology for replica %d: %w", replica, err)
+	}
+
+	// A WaitForFirstConsumer temp PVC is provisioned where the scheduler puts the migrator pod, an Immediate
+	// one is hinted to the topology of a fitting node. In network mode the new volume can live anywhere.
+	sc, err := getTargetStorageClass(ctx, r.Client, vol, originalPVC)
+	if err != nil {
+		return nil, false, err
+	}
+	tempPVCNode := migrationNode
+	if vr.Spec.TransferMode == TransferModeNetwork ||
+		(sc != nil && sc.VolumeBindingMode != nil && *sc.VolumeBindingMode == storagev1.VolumeBindingWaitForFirstConsumer) {
+		tempPVCNode = ""
 	}
 
 	// Create temp PVC if not exists
-	tempPVCName := getTempPVCName(volName, vr.Spec.StatefulSetName, replica)
-	tempPVC, err := createTempPVC(ctx, r.Client, vr, vol, originalPVC, replica)
+	tempPVCName := getTempPVCName(vol.Name, vr.Spec.StatefulSetName, replica)
+	tempPVC, err := createTempPVC(ctx, r.Client, vr, vol, originalPVC, replica, tempPVCNode)
 	if err != nil {
-		return r.setFailed(ctx, vr, fmt.Sprintf("failed to create temp PVC: %v", err))
+		return nil, false, fmt.Errorf("failed to create temp PVC: %w", err)
+	}
+
+	// A temp PVC from an aborted or retried attempt may still be going away
+	if tempPVC.DeletionTimestamp != nil {
+		return nil, false, nil
 	}
 
 	// Check if we need to wait for temp PVC to be bound
@@ -245,127 +713,109 @@ func (r *VolumeResizeReconciler) handleSyncing(ctx context.Context, vr *storagev
 	}
 
 	if waitForBinding && tempPVC.Status.Phase != corev1.ClaimBound {
-		log.Info("Waiting for temp PVC to be bound", "pvc", tempPVCName)
-		return ctrl.Result{RequeueAfter: time.Second * 5}, nil
+		return nil, false, nil
+	}
+
+	// Immediate binding ignores the selected node on some provisioners, fail fast instead of a Pending migrator
+	if err := validateTempPVTopology(ctx, r.Client, tempPVC, tempPVCNode); err != nil {
+		return nil, false, err
 	}
 
 	// Set Retain on old PV
 	oldPVName := originalPVC.Spec.VolumeName
 	if err := setRetainOnPV(ctx, r.Client, oldPVName); err != nil {
-		return r.setFailed(ctx, vr, fmt.Sprintf("failed to set retain on PV: %v", err))
+		return nil, false, fmt.Errorf("failed to set retain on PV: %w", err)
+	}
+	if err := labelPVForMigration(ctx, r.Client, oldPVName, vr.Name); err != nil {
+		return nil, false, err
 	}
 
 	// Update volume status
-	r.updateVolumeStatus(vr, volName, replica, VolumeStatusSyncing, "Preparing migration")
-	vr.Status.VolumeStatuses = updateVolumeStatusInList(vr.Status.VolumeStatuses, volName, replica, func(vs *storagev1alpha1.VolumeStatus) {
+	r.updateVolumeStatus(vr, vol.Name, replica, VolumeStatusSyncing, "Preparing migration")
+	vr.Status.VolumeStatuses = updateVolumeStatusInList(vr.Status.VolumeStatuses, vol.Name, replica, func(vs *storagev1alpha1.VolumeStatus) {
 		vs.OldPVCName = originalPVCName
 		vs.NewPVCName = tempPVCName
 		vs.OldPVName = oldPVName
 	})
 
-	// Delete STS with orphan if not already deleted
-	if vr.Annotations == nil || vr.Annotations[AnnotationSTSDeleted] != "true" {
-		sts := &appsv1.StatefulSet{}
-		err := r.Get(ctx, types.NamespacedName{Namespace: vr.Namespace, Name: vr.Spec.StatefulSetName}, sts)
-		if err != nil && !apierrors.IsNotFound(err) {
-			return ctrl.Result{}, err
-		}
-		if err == nil {
-			// FIRST: Backup STS spec to ConfigMap BEFORE any changes
-			if err := backupSTSToConfigMap(ctx, r.Client, vr, sts); err != nil {
-				return r.setFailed(ctx, vr, fmt.Sprintf("failed to backup STS to ConfigMap: %v", err))
-			}
-			log.Info("StatefulSet spec backed up to ConfigMap", "configmap", getSTSBackupConfigMapName(vr.Name))
-
-			// Now safe to delete STS with orphan policy
-			if _, err := deleteSTSOrphan(ctx, r.Client, sts); err != nil {
-				return r.setFailed(ctx, vr, fmt.Sprintf("failed to delete STS: %v", err))
-			}
-
-			// Mark STS as deleted and record backup ConfigMap name
-			if vr.Annotations == nil {
-				vr.Annotations = make(map[string]string)
-			}
-			vr.Annotations[AnnotationSTSDeleted] = "true"
-			vr.Annotations[AnnotationSTSBackup] = getSTSBackupConfigMapName(vr.Name)
-			if err := r.Update(ctx, vr); err != nil {
-				return ctrl.Result{}, err
-			}
+	return &volumeMigration{
+		vol:             vol,
+		replica:         replica,
+		originalPVC:     originalPVC,
+		originalPVCName: originalPVCName,
+		tempPVCName:     tempPVCName,
+		placement:       migrationPlacement(oldPV, tolerations),
+	}, true, nil
+}
 
-			// Update status with backup ConfigMap name
-			vr.Status.BackupConfigMapName = getSTSBackupConfigMapName(vr.Name)
-			if err := r.Status().Update(ctx, vr); err != nil {
-				return ctrl.Result{}, err
-			}
-		}
-	}
+// ensureSTSDeleted backs up the StatefulSet and deletes it with the orphan policy, once per batch
+func (r *VolumeResizeReconciler) ensureSTSDeleted(ctx context.Context, vr *storagev1alpha1.VolumeResize) error {
+	log := logf.FromContext(ctx)
 
-	// Delete target pod
-	podName := getPodName(vr.Spec.StatefulSetName, replica)
-	if err := deletePod(ctx, r.Client, vr.Namespace, vr.Spec.StatefulSetName, replica); err != nil {
-		log.Error(err, "Failed to delete pod", "pod", podName)
+	if vr.Annotations[AnnotationSTSDeleted] == "true" {
+		return nil
 	}
 
-	// Wait for pod termination
-	if err := waitForPodTermination(ctx, r.Client, vr.Namespace, podName, time.Minute*2); err != nil {
-		log.Info("Waiting for pod termination", "pod", podName)
-		return ctrl.Result{RequeueAfter: time.Second * 5}, nil
+	sts := &appsv1.StatefulSet{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: vr.Namespace, Name: vr.Spec.StatefulSetName}, sts)
+	if apierrors.IsNotFound(err) {
+		return nil
 	}
-
-	// Create migrator pod
-	migratorPod, err := createMigratorPod(ctx, r.Client, vr, vol, replica, originalPVCName, tempPVCName)
 	if err != nil {
-		return r.setFailed(ctx, vr, fmt.Sprintf("failed to create migrator pod: %v", err))
+		return fmt.Errorf("failed to get STS: %w", err)
 	}
 
-	// Wait for migration to complete
-	if err := waitForMigrationComplete(ctx, r.Client, migratorPod.Name, vr.Namespace, time.Hour); err != nil {
-		// Check if still running
-		pod := &corev1.Pod{}
-		if getErr := r.Get(ctx, types.NamespacedName{Namespace: vr.Namespace, Name: migratorPod.Name}, pod); getErr == nil {
-			if pod.Status.Phase == corev1.PodRunning || pod.Status.Phase == corev1.PodPending {
-				log.Info("Migration in progress", "pod", migratorPod.Name)
-				return ctrl.Result{RequeueAfter: time.Second * 10}, nil
-			}
-			if pod.Status.Phase == corev1.PodFailed {
-				return r.setFailed(ctx, vr, "migration pod failed")
-			}
-		}
+	// Changes made since the backup would be reverted when the StatefulSet is recreated from it
+	if err := r.reconcileDrift(ctx, vr, sts); err != nil {
+		return err
 	}
 
-	// Cleanup migrator pod
-	if err := cleanupMigratorPod(ctx, r.Client, migratorPod.Name, vr.Namespace); err != nil {
-		log.Error(err, "Failed to cleanup migrator pod")
+	// Keep the owner from recreating the StatefulSet while it is gone
+	if err := r.pauseParent(ctx, vr, sts); err != nil {
+		return fmt.Errorf("failed to pause StatefulSet owner: %w", err)
+	}
+	if err := r.suspendGitOps(ctx, vr, sts); err != nil {
+		return fmt.Errorf("failed to suspend GitOps sync: %w", err)
 	}
 
-	// Update volume status to synced
-	r.updateVolumeStatus(vr, volName, replica, VolumeStatusSynced, "Migration complete")
+	// Backup STS spec to ConfigMap BEFORE any changes
+	if err := backupSTSToConfigMap(ctx, r.Client, vr, sts); err != nil {
+		return fmt.Errorf("failed to backup STS to ConfigMap: %w", err)
+	}
+	log.Info("StatefulSet spec backed up to ConfigMap", "configmap", getSTSBackupConfigMapName(vr.Name))
 
-	// IMMEDIATELY replace PVC for this replica (don't wait for all replicas)
-	log.Info("Replacing PVC for replica", "replica", replica, "volume", volName)
-	if err := replacePVC(ctx, r.Client, vr, vol, replica); err != nil {
-		return r.setFailed(ctx, vr, fmt.Sprintf("failed to replace PVC: %v", err))
+	// Keep the PVCs from being garbage-collected with the StatefulSet, the backup holds the original policy
+	if err := retainPVCsOnDelete(ctx, r.Client, sts); err != nil {
+		return err
 	}
-	r.updateVolumeStatus(vr, volName, replica, VolumeStatusCompleted, "PVC replaced")
 
-	// IMPORTANT: Persist the status NOW before recreating STS
-	// This ensures we don't re-process this replica if reconcile is interrupted
-	if err := r.Status().Update(ctx, vr); err != nil {
-		return ctrl.Result{}, err
+	// Now safe to delete STS with orphan policy
+	if _, err := deleteSTSOrphan(ctx, r.Client, sts); err != nil {
+		return fmt.Errorf("failed to delete STS: %w", err)
 	}
 
-	// Recreate STS to bring this replica back online
-	// (other replicas are still running with old PVCs - that's fine)
-	stsSpec, err := getSTSFromBackupConfigMap(ctx, r.Client, vr.Namespace, vr.Name)
-	if err != nil {
-		return r.setFailed(ctx, vr, fmt.Sprintf("failed to get STS from backup: %v", err))
+	// Mark STS as deleted and record backup ConfigMap name
+	if vr.Annotations == nil {
+		vr.Annotations = make(map[string]string)
+	}
+	vr.Annotations[AnnotationSTSDeleted] = "true"
+	vr.Annotations[AnnotationSTSBackup] = getSTSBackupConfigMapName(vr.Name)
+	if err := r.Update(ctx, vr); err != nil {
+		return err
 	}
 
-	// Update volumeClaimTemplates with new sizes
-	for i := range stsSpec.Spec.VolumeClaimTemplates {
-		vct := &stsSpec.Spec.VolumeClaimTemplates[i]
+	// Update status with backup ConfigMap name
+	vr.Status.BackupConfigMapName = getSTSBackupConfigMapName(vr.Name)
+	return r.Status().Update(ctx, vr)
+}
+
+// applyMigratedTemplates updates the volumeClaimTemplates with the new sizes, once at least one replica of the volume was migrated
+// and no replica still has the old size
+func applyMigratedTemplates(vr *storagev1alpha1.VolumeResize, sts *appsv1.StatefulSet, oldSize map[string][]int32) {
+	for i := range sts.Spec.VolumeClaimTemplates {
+		vct := &sts.Spec.VolumeClaimTemplates[i]
 		for _, v := range vr.Spec.Volumes {
-			if vct.Name == v.Name {
+			if vct.Name == v.Name && hasCompletedVolume(vr.Status.VolumeStatuses, v.Name) && len(oldSize[v.Name]) == 0 {
 				vct.Spec.Resources.Requests[corev1.ResourceStorage] = v.NewSize
 				if v.StorageClass != nil {
 					vct.Spec.StorageClassName = v.StorageClass
@@ -373,58 +823,83 @@ func (r *VolumeResizeReconciler) handleSyncing(ctx context.Context, vr *storagev
 			}
 		}
 	}
+}
+
+// restoreSTS recreates the StatefulSet from its backup with the new volumeClaimTemplate sizes
+func (r *VolumeResizeReconciler) restoreSTS(ctx context.Context, vr *storagev1alpha1.VolumeResize) error {
+	log := logf.FromContext(ctx)
+
+	if vr.Annotations[AnnotationSTSDeleted] != "true" {
+		return nil
+	}
+
+	stsSpec, err := getSTSFromBackupConfigMap(ctx, r.Client, vr.Namespace, vr.Name)
+	if err != nil {
+		return fmt.Errorf("failed to get STS from backup: %w", err)
+	}
+
+	// A subset migration keeps the old template size while replicas left out still have it
+	oldSize, err := r.oldSizeReplicas(ctx, vr, stsSpec)
+	if err != nil {
+		return err
+	}
+	applyMigratedTemplates(vr, stsSpec, oldSize)
 
 	if err := recreateSTS(ctx, r.Client, stsSpec); err != nil {
 		if !apierrors.IsAlreadyExists(err) {
-			return r.setFailed(ctx, vr, fmt.Sprintf("failed to recreate STS: %v", err))
+			return fmt.Errorf("failed to recreate STS: %w", err)
 		}
 	}
-	log.Info("StatefulSet recreated, waiting for pod to come back", "replica", replica)
+	if err := restorePVCOwnerRefs(ctx, r.Client, stsSpec); err != nil {
+		return err
+	}
+	log.Info("StatefulSet recreated, waiting for pods to come back", "replicas", vr.Status.InFlightReplicas)
 
-	// Clear the STS deleted annotation so next replica can delete it again
+	// Clear the STS deleted annotation so the next batch can delete it again
 	delete(vr.Annotations, AnnotationSTSDeleted)
-	if err := r.Update(ctx, vr); err != nil {
-		return ctrl.Result{}, err
-	}
+	return r.Update(ctx, vr)
+}
 
-	// Wait for the pod to come back online before proceeding
-	podName = getPodName(vr.Spec.StatefulSetName, replica)
-	pod := &corev1.Pod{}
-	if err := r.Get(ctx, types.NamespacedName{Namespace: vr.Namespace, Name: podName}, pod); err != nil {
-		if apierrors.IsNotFound(err) {
-			log.Info("Waiting for pod to be recreated", "pod", podName)
-			return ctrl.Result{RequeueAfter: time.Second * 5}, nil
+// copyVolume starts or checks the copy of a volume, either in a single migrator pod or streamed
+// between a sender and a receiver. The pods are cleaned up once the copy succeeded.
+func (r *VolumeResizeReconciler) copyVolume(ctx context.Context, vr *storagev1alpha1.VolumeResize, m *volumeMigration) (transferState, error) {
+	log := logf.FromContext(ctx)
+
+	if vr.Spec.TransferMode == TransferModeNetwork {
+		if err := startNetworkTransfer(ctx, r.Client, vr, m.vol.Name, m.replica, m.originalPVCName, m.tempPVCName, m.placement); err != nil {
+			return "", fmt.Errorf("failed to start network transfer: %w", err)
 		}
-		return ctrl.Result{}, err
-	}
-	if pod.Status.Phase != corev1.PodRunning {
-		log.Info("Waiting for pod to be running", "pod", podName, "phase", pod.Status.Phase)
-		return ctrl.Result{RequeueAfter: time.Second * 5}, nil
+
+		state, err := getNetworkTransferState(ctx, r.Client, vr.Namespace, vr.Name, m.vol.Name, m.replica)
+		if err != nil || state != transferSucceeded {
+			return state, err
+		}
+
+		if err := cleanupNetworkTransfer(ctx, r.Client, vr.Namespace, vr.Name, m.vol.Name, m.replica); err != nil {
+			log.Error(err, "Failed to cleanup network transfer")
+		}
+		return transferSucceeded, nil
 	}
 
-	log.Info("Replica migration complete, pod is back online", "replica", replica)
+	// Create migrator pod
+	migratorPod, err := createMigratorPod(ctx, r.Client, vr, m.vol, m.replica, m.originalPVCName, m.tempPVCName, migratorPodOptions{
+		BlockMode: isBlockPVC(m.originalPVC),
+		Placement: m.placement,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create migrator pod: %w", err)
+	}
 
-	// Advance to next volume/replica
-	nextReplica, nextVol, done := r.getNextVolumeReplica(vr, replica, volName)
-	if done {
-		// All replicas done - go to Completed (skip Replacing phase)
-		vr.Status.Phase = PhaseCompleted
-		now := metav1.Now()
-		vr.Status.CompletionTime = &now
-		vr.Status.Message = MessageMigrationCompleted
-		vr.Status.CurrentReplica = nil
-		vr.Status.CurrentVolume = ""
-		log.Info(MessageMigrationCompleted)
-	} else {
-		vr.Status.CurrentReplica = &nextReplica
-		vr.Status.CurrentVolume = nextVol
-		vr.Status.Message = fmt.Sprintf("Migrating replica %d volume %s", nextReplica, nextVol)
+	state, err := getMigratorPodState(ctx, r.Client, migratorPod.Name, vr.Namespace)
+	if err != nil || state != transferSucceeded {
+		return state, err
 	}
 
-	if err := r.Status().Update(ctx, vr); err != nil {
-		return ctrl.Result{}, err
+	// Cleanup migrator pod
+	if err := cleanupMigratorPod(ctx, r.Client, migratorPod.Name, vr.Namespace); err != nil {
+		log.Error(err, "Failed to cleanup migrator pod")
 	}
-	return ctrl.Result{Requeue: true}, nil
+	return transferSucceeded, nil
 }
 
 // handleReplacing is kept for backwards compatibility but the new flow
@@ -450,6 +925,46 @@ func (r *VolumeResizeReconciler) handleDeletion(ctx context.Context, vr *storage
 	log := logf.FromContext(ctx)
 	log.Info("Handling deletion")
 
+	// Put the workload back before releasing the finalizer, unless a forced removal was requested
+	if vr.Annotations[AnnotationForceDelete] == "true" {
+		log.Info("Force delete requested, skipping StatefulSet and PVC restore")
+		if err := r.resumeParent(ctx, vr); err != nil {
+			log.Error(err, "Failed to resume StatefulSet owner")
+		}
+		if err := r.resumeGitOps(ctx, vr); err != nil {
+			log.Error(err, "Failed to resume GitOps sync")
+		}
+	} else {
+		for _, vs := range vr.Status.VolumeStatuses {
+			if err := cleanupMigratorPod(ctx, r.Client, getMigratorPodName(vr.Name, vs.VolumeName, vs.Replica), vr.Namespace); err != nil {
+				return ctrl.Result{}, err
+			}
+			if err := cleanupNetworkTransfer(ctx, r.Client, vr.Namespace, vr.Name, vs.VolumeName, vs.Replica); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		if err := r.rebindMissingPVCs(ctx, vr); err != nil {
+			log.Error(err, "Failed to rebind PVCs, retrying")
+			return ctrl.Result{}, err
+		}
+		if err := r.restoreSTS(ctx, vr); err != nil {
+			log.Error(err, "Failed to restore StatefulSet, retrying")
+			return ctrl.Result{}, err
+		}
+		if err := r.resumeParent(ctx, vr); err != nil {
+			log.Error(err, "Failed to resume StatefulSet owner, retrying")
+			return ctrl.Result{}, err
+		}
+		if err := r.resumeGitOps(ctx, vr); err != nil {
+			log.Error(err, "Failed to resume GitOps sync, retrying")
+			return ctrl.Result{}, err
+		}
+		if err := r.deleteSTSBackup(ctx, vr); err != nil {
+			log.Error(err, "Failed to delete StatefulSet backup, retrying")
+			return ctrl.Result{}, err
+		}
+	}
+
 	// List and delete temp PVCs
 	pvcList := &corev1.PersistentVolumeClaimList{}
 	if err := r.List(ctx, pvcList, client.InNamespace(vr.Namespace), client.MatchingLabels{
@@ -474,6 +989,39 @@ func (r *VolumeResizeReconciler) handleDeletion(ctx context.Context, vr *storage
 		}
 	}
 
+	// List and delete network transfer Services and Secrets
+	svcList := &corev1.ServiceList{}
+	if err := r.List(ctx, svcList, client.InNamespace(vr.Namespace), client.MatchingLabels{
+		LabelMigrationName: vr.Name,
+	}); err == nil {
+		for _, svc := range svcList.Items {
+			if err := r.Delete(ctx, &svc); err != nil && !apierrors.IsNotFound(err) {
+				log.Error(err, "Failed to delete transfer service", "service", svc.Name)
+			}
+		}
+	}
+
+	secretList := &corev1.SecretList{}
+	if err := r.List(ctx, secretList, client.InNamespace(vr.Namespace), client.MatchingLabels{
+		LabelMigrationName: vr.Name,
+	}); err == nil {
+		for _, secret := range secretList.Items {
+			if err := r.Delete(ctx, &secret); err != nil && !apierrors.IsNotFound(err) {
+				log.Error(err, "Failed to delete transfer secret", "secret", secret.Name)
+			}
+		}
+	}
+
+	// Delete the Jobs run by the health gates
+	if err := r.cleanupHealthGateJobs(ctx, vr); err != nil {
+		log.Error(err, "Failed to delete health gate jobs")
+	}
+
+	// Delete the Jobs run by the hooks
+	if err := r.deleteMigrationJobs(ctx, vr, LabelHook); err != nil {
+		log.Error(err, "Failed to delete hook jobs")
+	}
+
 	// Remove finalizer
 	controllerutil.RemoveFinalizer(vr, FinalizerName)
 	if err := r.Update(ctx, vr); err != nil {
@@ -522,36 +1070,12 @@ func updateVolumeStatusInList(statuses []storagev1alpha1.VolumeStatus, volName s
 	return statuses
 }
 
-func (r *VolumeResizeReconciler) getNextVolumeReplica(vr *storagev1alpha1.VolumeResize, currentReplica int32, currentVol string) (int32, string, bool) {
-	// Find current volume index
-	volIdx := 0
-	for i, v := range vr.Spec.Volumes {
-		if v.Name == currentVol {
-			volIdx = i
-			break
-		}
-	}
-
-	// Try next volume for same replica
-	if volIdx+1 < len(vr.Spec.Volumes) {
-		return currentReplica, vr.Spec.Volumes[volIdx+1].Name, false
-	}
-
-	// Try next replica with first volume
-	// Get total replicas from volume statuses
-	maxReplica := int32(0)
-	for _, vs := range vr.Status.VolumeStatuses {
-		if vs.Replica > maxReplica {
-			maxReplica = vs.Replica
-		}
+// recordEvent emits an event on the VolumeResize when a recorder is configured
+func (r *VolumeResizeReconciler) recordEvent(vr *storagev1alpha1.VolumeResize, eventtype, reason, action, note string, args ...any) {
+	if r.Recorder == nil {
+		return
 	}
-
-	if currentReplica < maxReplica {
-		return currentReplica + 1, vr.Spec.Volumes[0].Name, false
-	}
-
-	// All done
-	return 0, "", true
+	r.Recorder.Eventf(vr, nil, eventtype, reason, action, note, args...)
 }
 
 // SetupWithManager sets up the controller with the Manager.
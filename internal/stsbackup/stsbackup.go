@@ -0,0 +1,343 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package stsbackup stores StatefulSet backups as numbered revisions in ConfigMaps.
+//
+// A backup is a head ConfigMap holding the index of its revisions. Each revision is the StatefulSet,
+// stripped of its server-managed fields, serialized to JSON, gzipped and split into chunk ConfigMaps
+// so large StatefulSets stay under the object size limit. Revisions carry the sha256 of their JSON,
+// checked on read. Head ConfigMaps written before revisions existed hold the JSON under LegacyKey and
+// are still readable.
+package stsbackup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// LegacyKey holds the raw StatefulSet JSON in head ConfigMaps written before revisions existed
+	LegacyKey = "statefulset.json"
+	// IndexKey holds the JSON list of revisions in the head ConfigMap
+	IndexKey = "revisions.json"
+	// ChunkKey holds the compressed data in a chunk ConfigMap
+	ChunkKey = "chunk"
+
+	// AnnotationRevision is the latest revision, set on the head ConfigMap
+	AnnotationRevision = "storage.maurice.fr/backup-revision"
+	// LabelChunkOf is set on chunk ConfigMaps to the name of their head ConfigMap
+	LabelChunkOf = "storage.maurice.fr/backup-chunk-of"
+
+	// MaxRevisions is the number of revisions kept, older ones are pruned
+	MaxRevisions = 5
+	// chunkSize leaves room for the metadata within the 1 MiB ConfigMap limit
+	chunkSize = 768 * 1024
+)
+
+// Revision describes one stored version of the StatefulSet
+type Revision struct {
+	Revision  int         `json:"revision"`
+	SHA256    string      `json:"sha256"`
+	Size      int         `json:"size"`
+	Chunks    int         `json:"chunks"`
+	CreatedAt metav1.Time `json:"createdAt"`
+}
+
+// Clean returns the parts of a StatefulSet needed to recreate it, without status and server-managed fields
+func Clean(sts *appsv1.StatefulSet) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "StatefulSet"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            sts.Name,
+			Namespace:       sts.Namespace,
+			Labels:          sts.Labels,
+			Annotations:     sts.Annotations,
+			OwnerReferences: sts.OwnerReferences,
+		},
+		Spec: *sts.Spec.DeepCopy(),
+	}
+}
+
+// IsChunk reports whether a ConfigMap is a chunk rather than a head
+func IsChunk(cm *corev1.ConfigMap) bool {
+	_, ok := cm.Labels[LabelChunkOf]
+	return ok
+}
+
+// Revisions returns the revisions of a head ConfigMap, oldest first. A legacy head has a single revision.
+func Revisions(head *corev1.ConfigMap) ([]Revision, error) {
+	raw, ok := head.Data[IndexKey]
+	if !ok {
+		if _, legacy := head.Data[LegacyKey]; legacy {
+			return []Revision{{Revision: legacyRevision(head), Size: len(head.Data[LegacyKey]), CreatedAt: head.CreationTimestamp}}, nil
+		}
+		return nil, fmt.Errorf("backup ConfigMap %s has neither %s nor %s", head.Name, IndexKey, LegacyKey)
+	}
+	revisions := []Revision{}
+	if err := json.Unmarshal([]byte(raw), &revisions); err != nil {
+		return nil, fmt.Errorf("failed to read the revisions of backup ConfigMap %s: %w", head.Name, err)
+	}
+	return revisions, nil
+}
+
+// Read returns the latest revision of the StatefulSet stored under a head ConfigMap
+func Read(ctx context.Context, c client.Reader, head *corev1.ConfigMap) (*appsv1.StatefulSet, error) {
+	return ReadRevision(ctx, c, head, 0)
+}
+
+// ReadRevision returns a revision of the StatefulSet stored under a head ConfigMap, 0 being the latest
+func ReadRevision(ctx context.Context, c client.Reader, head *corev1.ConfigMap, revision int) (*appsv1.StatefulSet, error) {
+	if _, ok := head.Data[IndexKey]; !ok {
+		if revision != 0 && revision != legacyRevision(head) {
+			return nil, fmt.Errorf("backup ConfigMap %s has no revision %d", head.Name, revision)
+		}
+		return decode([]byte(head.Data[LegacyKey]))
+	}
+
+	revisions, err := Revisions(head)
+	if err != nil {
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		return nil, fmt.Errorf("backup ConfigMap %s has no revisions", head.Name)
+	}
+	rev := revisions[len(revisions)-1]
+	if revision != 0 {
+		found := false
+		for _, r := range revisions {
+			if r.Revision == revision {
+				rev, found = r, true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("backup ConfigMap %s has no revision %d", head.Name, revision)
+		}
+	}
+
+	compressed := []byte{}
+	for i := range rev.Chunks {
+		cm := &corev1.ConfigMap{}
+		name := chunkName(head.Name, rev.Revision, i)
+		if err := c.Get(ctx, types.NamespacedName{Namespace: head.Namespace, Name: name}, cm); err != nil {
+			return nil, fmt.Errorf("failed to get backup chunk %s: %w", name, err)
+		}
+		compressed = append(compressed, cm.BinaryData[ChunkKey]...)
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress revision %d of backup %s: %w", rev.Revision, head.Name, err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress revision %d of backup %s: %w", rev.Revision, head.Name, err)
+	}
+	if sum := checksum(data); sum != rev.SHA256 {
+		return nil, fmt.Errorf("revision %d of backup %s is corrupted: sha256 %s, expected %s", rev.Revision, head.Name, sum, rev.SHA256)
+	}
+	return decode(data)
+}
+
+// Write stores the StatefulSet as a new revision under the head ConfigMap, creating it with the given
+// metadata if needed, and returns the revision number. Chunks carry the labels of the head. They are
+// written before the index, so a revision is never listed before it is complete.
+func Write(ctx context.Context, c client.Client, head metav1.ObjectMeta, sts *appsv1.StatefulSet) (int, error) {
+	labels := head.Labels
+	cm := &corev1.ConfigMap{}
+	err := c.Get(ctx, types.NamespacedName{Namespace: head.Namespace, Name: head.Name}, cm)
+	exists := err == nil
+	if apierrors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{ObjectMeta: head, Data: map[string]string{IndexKey: "[]"}}
+	} else if err != nil {
+		return 0, fmt.Errorf("failed to get backup ConfigMap: %w", err)
+	}
+
+	revisions := []Revision{}
+	if _, ok := cm.Data[LegacyKey]; ok {
+		// Keep the legacy backup as the first revision
+		legacy, err := decode([]byte(cm.Data[LegacyKey]))
+		if err != nil {
+			return 0, err
+		}
+		rev, err := writeRevision(ctx, c, cm, legacyRevision(cm), labels, legacy)
+		if err != nil {
+			return 0, err
+		}
+		revisions = append(revisions, rev)
+		delete(cm.Data, LegacyKey)
+	} else if revisions, err = Revisions(cm); err != nil {
+		return 0, err
+	}
+
+	next := 1
+	if len(revisions) > 0 {
+		next = revisions[len(revisions)-1].Revision + 1
+	}
+	rev, err := writeRevision(ctx, c, cm, next, labels, sts)
+	if err != nil {
+		return 0, err
+	}
+	revisions = append(revisions, rev)
+
+	pruned := []Revision{}
+	if len(revisions) > MaxRevisions {
+		pruned = revisions[:len(revisions)-MaxRevisions]
+		revisions = revisions[len(revisions)-MaxRevisions:]
+	}
+
+	index, err := json.Marshal(revisions)
+	if err != nil {
+		return 0, err
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[IndexKey] = string(index)
+	if cm.Annotations == nil {
+		cm.Annotations = map[string]string{}
+	}
+	cm.Annotations[AnnotationRevision] = strconv.Itoa(rev.Revision)
+	if exists {
+		err = c.Update(ctx, cm)
+	} else {
+		err = c.Create(ctx, cm)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to write backup ConfigMap: %w", err)
+	}
+
+	for _, old := range pruned {
+		for i := range old.Chunks {
+			chunk := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: chunkName(cm.Name, old.Revision, i), Namespace: cm.Namespace}}
+			if err := c.Delete(ctx, chunk); err != nil && !apierrors.IsNotFound(err) {
+				return 0, fmt.Errorf("failed to prune backup chunk %s: %w", chunk.Name, err)
+			}
+		}
+	}
+	return rev.Revision, nil
+}
+
+// Delete removes a head ConfigMap and the chunks of all its revisions, including the leftovers of
+// interrupted writes
+func Delete(ctx context.Context, c client.Client, head *corev1.ConfigMap) error {
+	chunks := &corev1.ConfigMapList{}
+	if err := c.List(ctx, chunks, client.InNamespace(head.Namespace), client.MatchingLabels{LabelChunkOf: head.Name}); err != nil {
+		return fmt.Errorf("failed to list the chunks of backup ConfigMap %s: %w", head.Name, err)
+	}
+	for i := range chunks.Items {
+		if err := c.Delete(ctx, &chunks.Items[i]); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete backup chunk %s: %w", chunks.Items[i].Name, err)
+		}
+	}
+	if err := c.Delete(ctx, head); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete backup ConfigMap %s: %w", head.Name, err)
+	}
+	return nil
+}
+
+// writeRevision stores the chunks of one revision, overwriting the leftovers of an interrupted write
+func writeRevision(ctx context.Context, c client.Client, head *corev1.ConfigMap, revision int, labels map[string]string, sts *appsv1.StatefulSet) (Revision, error) {
+	data, err := json.Marshal(Clean(sts))
+	if err != nil {
+		return Revision{}, fmt.Errorf("failed to serialize StatefulSet: %w", err)
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return Revision{}, err
+	}
+	if err := zw.Close(); err != nil {
+		return Revision{}, err
+	}
+	compressed := buf.Bytes()
+
+	chunks := 0
+	for offset := 0; offset < len(compressed) || chunks == 0; offset += chunkSize {
+		end := min(offset+chunkSize, len(compressed))
+		chunkLabels := map[string]string{LabelChunkOf: head.Name}
+		for k, v := range labels {
+			chunkLabels[k] = v
+		}
+		chunk := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      chunkName(head.Name, revision, chunks),
+				Namespace: head.Namespace,
+				Labels:    chunkLabels,
+			},
+			BinaryData: map[string][]byte{ChunkKey: compressed[offset:end]},
+		}
+		err := c.Create(ctx, chunk)
+		if apierrors.IsAlreadyExists(err) {
+			existing := &corev1.ConfigMap{}
+			if err = c.Get(ctx, client.ObjectKeyFromObject(chunk), existing); err == nil {
+				existing.BinaryData = chunk.BinaryData
+				err = c.Update(ctx, existing)
+			}
+		}
+		if err != nil {
+			return Revision{}, fmt.Errorf("failed to write backup chunk %s: %w", chunk.Name, err)
+		}
+		chunks++
+	}
+
+	return Revision{
+		Revision:  revision,
+		SHA256:    checksum(data),
+		Size:      len(data),
+		Chunks:    chunks,
+		CreatedAt: metav1.Now(),
+	}, nil
+}
+
+func chunkName(head string, revision, index int) string {
+	return fmt.Sprintf("%s-r%d-%d", head, revision, index)
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// legacyRevision is the revision of a legacy head, which only had the annotation once drift was merged into it
+func legacyRevision(head *corev1.ConfigMap) int {
+	if v, err := strconv.Atoi(head.Annotations[AnnotationRevision]); err == nil && v > 0 {
+		return v
+	}
+	return 1
+}
+
+func decode(data []byte) (*appsv1.StatefulSet, error) {
+	sts := &appsv1.StatefulSet{}
+	if err := json.Unmarshal(data, sts); err != nil {
+		return nil, fmt.Errorf("failed to deserialize StatefulSet: %w", err)
+	}
+	return sts, nil
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stsbackup
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var testHead = metav1.ObjectMeta{Name: "test-resize-sts-backup", Namespace: "default", Labels: map[string]string{"app": "test"}}
+
+func newTestClient(t *testing.T, objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func testSTS(image string) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "test-sts",
+			Namespace:       "default",
+			UID:             "1234",
+			ResourceVersion: "42",
+			ManagedFields:   []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: image}}}},
+		},
+		Status: appsv1.StatefulSetStatus{Replicas: 3},
+	}
+}
+
+func getHead(t *testing.T, c client.Client) *corev1.ConfigMap {
+	cm := &corev1.ConfigMap{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: testHead.Namespace, Name: testHead.Name}, cm))
+	return cm
+}
+
+func TestWriteAndRead(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	rev, err := Write(ctx, c, testHead, testSTS("app:1.0"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, rev)
+	rev, err = Write(ctx, c, testHead, testSTS("app:1.1"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, rev)
+
+	head := getHead(t, c)
+	assert.Equal(t, "2", head.Annotations[AnnotationRevision])
+
+	sts, err := Read(ctx, c, head)
+	require.NoError(t, err)
+	assert.Equal(t, "app:1.1", sts.Spec.Template.Spec.Containers[0].Image)
+	assert.Empty(t, sts.UID)
+	assert.Empty(t, sts.ResourceVersion)
+	assert.Empty(t, sts.ManagedFields)
+	assert.Zero(t, sts.Status.Replicas)
+
+	sts, err = ReadRevision(ctx, c, head, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "app:1.0", sts.Spec.Template.Spec.Containers[0].Image)
+
+	_, err = ReadRevision(ctx, c, head, 3)
+	assert.Error(t, err)
+
+	chunks := &corev1.ConfigMapList{}
+	require.NoError(t, c.List(ctx, chunks, client.MatchingLabels{LabelChunkOf: testHead.Name}))
+	require.Len(t, chunks.Items, 2)
+	assert.Equal(t, "test", chunks.Items[0].Labels["app"])
+}
+
+func TestWriteChunksLargeStatefulSet(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	// Random data does not compress, so it needs several chunks
+	random := make([]byte, chunkSize)
+	_, err := rand.Read(random)
+	require.NoError(t, err)
+	sts := testSTS("app:1.0")
+	sts.Spec.Template.Annotations = map[string]string{"blob": hex.EncodeToString(random)}
+
+	_, err = Write(ctx, c, testHead, sts)
+	require.NoError(t, err)
+	revisions, err := Revisions(getHead(t, c))
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	assert.Greater(t, revisions[0].Chunks, 1)
+
+	got, err := Read(ctx, c, getHead(t, c))
+	require.NoError(t, err)
+	assert.Equal(t, sts.Spec.Template.Annotations, got.Spec.Template.Annotations)
+}
+
+func TestReadDetectsCorruption(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+	_, err := Write(ctx, c, testHead, testSTS("app:1.0"))
+	require.NoError(t, err)
+
+	head := getHead(t, c)
+	revisions, err := Revisions(head)
+	require.NoError(t, err)
+	revisions[0].SHA256 = "0000"
+	index, err := json.Marshal(revisions)
+	require.NoError(t, err)
+	head.Data[IndexKey] = string(index)
+
+	_, err = Read(ctx, c, head)
+	assert.ErrorContains(t, err, "corrupted")
+}
+
+func TestWritePrunesOldRevisions(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+	for i := range MaxRevisions + 2 {
+		_, err := Write(ctx, c, testHead, testSTS(fmt.Sprintf("app:1.%d", i)))
+		require.NoError(t, err)
+	}
+
+	revisions, err := Revisions(getHead(t, c))
+	require.NoError(t, err)
+	require.Len(t, revisions, MaxRevisions)
+	assert.Equal(t, 3, revisions[0].Revision)
+
+	chunks := &corev1.ConfigMapList{}
+	require.NoError(t, c.List(ctx, chunks, client.MatchingLabels{LabelChunkOf: testHead.Name}))
+	assert.Len(t, chunks.Items, MaxRevisions)
+}
+
+func TestDelete(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+	for i := range 2 {
+		_, err := Write(ctx, c, testHead, testSTS(fmt.Sprintf("app:1.%d", i)))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, Delete(ctx, c, getHead(t, c)))
+	cms := &corev1.ConfigMapList{}
+	require.NoError(t, c.List(ctx, cms))
+	assert.Empty(t, cms.Items)
+}
+
+func TestLegacyBackup(t *testing.T) {
+	raw, err := json.Marshal(testSTS("app:1.0"))
+	require.NoError(t, err)
+	legacy := &corev1.ConfigMap{ObjectMeta: *testHead.DeepCopy(), Data: map[string]string{LegacyKey: string(raw)}}
+	c := newTestClient(t, legacy)
+	ctx := context.Background()
+
+	sts, err := Read(ctx, c, getHead(t, c))
+	require.NoError(t, err)
+	assert.Equal(t, "app:1.0", sts.Spec.Template.Spec.Containers[0].Image)
+
+	// Writing converts the legacy backup into the first revision
+	rev, err := Write(ctx, c, testHead, testSTS("app:1.1"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, rev)
+
+	head := getHead(t, c)
+	assert.NotContains(t, head.Data, LegacyKey)
+	sts, err = ReadRevision(ctx, c, head, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "app:1.0", sts.Spec.Template.Spec.Containers[0].Image)
+	assert.Empty(t, sts.UID)
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+	"github.com/thomas-maurice/migcontroller/internal/controller"
+)
+
+// StatefulSetLockPath is served by the lock webhook, see config/webhook/statefulset_lock.yaml
+const StatefulSetLockPath = "/validate-apps-v1-statefulset-lock"
+
+// log is for logging in this package.
+var statefulsetlog = logf.Log.WithName("statefulset-lock")
+
+// SetupStatefulSetLockWebhookWithManager registers the StatefulSet lock webhook in the manager.
+// exemptUsers are let through, they must include the service account of the controller.
+func SetupStatefulSetLockWebhookWithManager(mgr ctrl.Manager, exemptUsers []string) error {
+	mgr.GetWebhookServer().Register(StatefulSetLockPath, &webhook.Admission{Handler: &StatefulSetLock{
+		Client:      mgr.GetClient(),
+		Decoder:     admission.NewDecoder(mgr.GetScheme()),
+		ExemptUsers: exemptUsers,
+	}})
+	return nil
+}
+
+// StatefulSetLock denies changes to the spec of a StatefulSet while a VolumeResize migrates it. The controller
+// recreates the StatefulSet from the backup taken when the migration started, which would silently revert them.
+type StatefulSetLock struct {
+	Client      client.Reader
+	Decoder     admission.Decoder
+	ExemptUsers []string
+}
+
+// Handle checks updates of statefulsets and statefulsets/scale
+func (l *StatefulSetLock) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Update || slices.Contains(l.ExemptUsers, req.UserInfo.Username) {
+		return admission.Allowed("")
+	}
+
+	var changed bool
+	var annotations map[string]string
+	switch req.SubResource {
+	case "scale":
+		oldScale, newScale := &autoscalingv1.Scale{}, &autoscalingv1.Scale{}
+		if err := l.Decoder.DecodeRaw(req.OldObject, oldScale); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if err := l.Decoder.Decode(req, newScale); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		changed = oldScale.Spec.Replicas != newScale.Spec.Replicas
+
+		// The Scale object carries no annotations, the override is read from the StatefulSet itself
+		sts := &appsv1.StatefulSet{}
+		if err := l.Client.Get(ctx, types.NamespacedName{Namespace: req.Namespace, Name: req.Name}, sts); err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		annotations = sts.Annotations
+	case "":
+		oldSTS, newSTS := &appsv1.StatefulSet{}, &appsv1.StatefulSet{}
+		if err := l.Decoder.DecodeRaw(req.OldObject, oldSTS); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if err := l.Decoder.Decode(req, newSTS); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		// Metadata changes go through: the garbage collector removes the orphan finalizer when the controller
+		// deletes the StatefulSet, and labels or annotations are not worth blocking
+		changed = newSTS.DeletionTimestamp.IsZero() && !equality.Semantic.DeepEqual(oldSTS.Spec, newSTS.Spec)
+		annotations = newSTS.Annotations
+	default:
+		return admission.Allowed("")
+	}
+
+	if !changed || annotations[controller.AnnotationUnlockStatefulSet] == "true" {
+		return admission.Allowed("")
+	}
+
+	vr, err := l.lockingMigration(ctx, req.Namespace, req.Name)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if vr == nil {
+		return admission.Allowed("")
+	}
+
+	statefulsetlog.Info("Denied StatefulSet change during migration", "namespace", req.Namespace, "statefulset", req.Name,
+		"volumeresize", vr.Name, "user", req.UserInfo.Username)
+	return admission.Denied(fmt.Sprintf(
+		"StatefulSet %s is locked by VolumeResize %s (phase %s): it is recreated from a backup taken when the migration started, "+
+			"so this change would be reverted. Wait for the migration to finish, or set the annotation %s=true to override",
+		req.Name, vr.Name, vr.Status.Phase, controller.AnnotationUnlockStatefulSet))
+}
+
+// lockingMigration returns the VolumeResize currently migrating the StatefulSet, if any
+func (l *StatefulSetLock) lockingMigration(ctx context.Context, namespace, name string) (*storagev1alpha1.VolumeResize, error) {
+	vrList := &storagev1alpha1.VolumeResizeList{}
+	if err := l.Client.List(ctx, vrList, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list VolumeResizes: %w", err)
+	}
+	for i := range vrList.Items {
+		vr := &vrList.Items[i]
+		if vr.Spec.StatefulSetName != name {
+			continue
+		}
+		switch vr.Status.Phase {
+		case "", controller.PhasePending, controller.PhaseCompleted, controller.PhaseAborted:
+			// Not started yet, or the StatefulSet was handed back
+			continue
+		}
+		return vr, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+	"github.com/thomas-maurice/migcontroller/internal/controller"
+)
+
+const controllerUser = "system:serviceaccount:volume-resize-operator-system:controller-manager"
+
+func newTestLock(t *testing.T, objs ...client.Object) *StatefulSetLock {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, storagev1alpha1.AddToScheme(scheme))
+
+	return &StatefulSetLock{
+		Client:      fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		Decoder:     admission.NewDecoder(scheme),
+		ExemptUsers: []string{controllerUser},
+	}
+}
+
+func lockTestSTS(replicas int32) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sts", Namespace: "default"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+	}
+}
+
+func lockTestVR(phase string) *storagev1alpha1.VolumeResize {
+	return &storagev1alpha1.VolumeResize{
+		ObjectMeta: metav1.ObjectMeta{Name: "resize", Namespace: "default"},
+		Spec:       storagev1alpha1.VolumeResizeSpec{StatefulSetName: "test-sts"},
+		Status:     storagev1alpha1.VolumeResizeStatus{Phase: phase},
+	}
+}
+
+func updateRequest(t *testing.T, user, subResource string, oldObj, newObj runtime.Object) admission.Request {
+	oldRaw, err := json.Marshal(oldObj)
+	require.NoError(t, err)
+	newRaw, err := json.Marshal(newObj)
+	require.NoError(t, err)
+	return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation:   admissionv1.Update,
+		Namespace:   "default",
+		Name:        "test-sts",
+		SubResource: subResource,
+		UserInfo:    authenticationv1.UserInfo{Username: user},
+		OldObject:   runtime.RawExtension{Raw: oldRaw},
+		Object:      runtime.RawExtension{Raw: newRaw},
+	}}
+}
+
+func TestStatefulSetLockDeniesSpecChanges(t *testing.T) {
+	l := newTestLock(t, lockTestVR(controller.PhaseSyncing))
+	ctx := context.Background()
+
+	resp := l.Handle(ctx, updateRequest(t, "alice", "", lockTestSTS(3), lockTestSTS(5)))
+	assert.False(t, resp.Allowed)
+	assert.Contains(t, resp.Result.Message, "locked by VolumeResize resize")
+
+	// The controller and metadata-only changes go through
+	resp = l.Handle(ctx, updateRequest(t, controllerUser, "", lockTestSTS(3), lockTestSTS(5)))
+	assert.True(t, resp.Allowed)
+	labelled := lockTestSTS(3)
+	labelled.Labels = map[string]string{"team": "storage"}
+	resp = l.Handle(ctx, updateRequest(t, "alice", "", lockTestSTS(3), labelled))
+	assert.True(t, resp.Allowed)
+
+	// The override annotation unlocks it
+	unlocked := lockTestSTS(5)
+	unlocked.Annotations = map[string]string{controller.AnnotationUnlockStatefulSet: "true"}
+	resp = l.Handle(ctx, updateRequest(t, "alice", "", lockTestSTS(3), unlocked))
+	assert.True(t, resp.Allowed)
+}
+
+func TestStatefulSetLockDeniesScale(t *testing.T) {
+	scale := func(replicas int32) *autoscalingv1.Scale {
+		return &autoscalingv1.Scale{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-sts", Namespace: "default"},
+			Spec:       autoscalingv1.ScaleSpec{Replicas: replicas},
+		}
+	}
+
+	l := newTestLock(t, lockTestVR(controller.PhaseSyncing), lockTestSTS(3))
+	resp := l.Handle(context.Background(), updateRequest(t, "alice", "scale", scale(3), scale(1)))
+	assert.False(t, resp.Allowed)
+
+	sts := lockTestSTS(3)
+	sts.Annotations = map[string]string{controller.AnnotationUnlockStatefulSet: "true"}
+	l = newTestLock(t, lockTestVR(controller.PhaseSyncing), sts)
+	resp = l.Handle(context.Background(), updateRequest(t, "alice", "scale", scale(3), scale(1)))
+	assert.True(t, resp.Allowed)
+}
+
+func TestStatefulSetLockIgnoresInactiveMigrations(t *testing.T) {
+	for _, phase := range []string{"", controller.PhasePending, controller.PhaseCompleted, controller.PhaseAborted} {
+		l := newTestLock(t, lockTestVR(phase))
+		resp := l.Handle(context.Background(), updateRequest(t, "alice", "", lockTestSTS(3), lockTestSTS(5)))
+		assert.True(t, resp.Allowed, phase)
+	}
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+	"github.com/thomas-maurice/migcontroller/internal/controller"
+)
+
+// log is for logging in this package.
+var volumeresizelog = logf.Log.WithName("volumeresize-resource")
+
+// SetupVolumeResizeWebhookWithManager registers the webhook for VolumeResize in the manager.
+func SetupVolumeResizeWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &storagev1alpha1.VolumeResize{}).
+		WithValidator(&VolumeResizeCustomValidator{Client: mgr.GetClient()}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-storage-maurice-fr-v1alpha1-volumeresize,mutating=false,failurePolicy=fail,sideEffects=None,groups=storage.maurice.fr,resources=volumeresizes,verbs=create;update,versions=v1alpha1,name=vvolumeresize-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// VolumeResizeCustomValidator rejects VolumeResizes the controller would fail on, before the finalizer is added.
+// Checks that depend on the cluster, like sizes against the current PVCs or PodDisruptionBudgets, stay in the
+// controller since they can change between admission and validation.
+type VolumeResizeCustomValidator struct {
+	Client client.Reader
+}
+
+// ValidateCreate checks the spec, that the StatefulSet and its volumeClaimTemplates exist
+// and that no other migration of the same StatefulSet is running
+func (v *VolumeResizeCustomValidator) ValidateCreate(ctx context.Context, vr *storagev1alpha1.VolumeResize) (admission.Warnings, error) {
+	volumeresizelog.Info("Validation for VolumeResize upon creation", "name", vr.GetName())
+
+	allErrs := validateSpec(vr)
+	allErrs = append(allErrs, v.validateTarget(ctx, vr)...)
+	return nil, toInvalid(vr, allErrs)
+}
+
+// ValidateUpdate refuses spec changes once validation has started, except for the pause, abort and retry
+// controls, the schedule, the approval mode, the health gates and the hooks
+func (v *VolumeResizeCustomValidator) ValidateUpdate(ctx context.Context, oldVR, newVR *storagev1alpha1.VolumeResize) (admission.Warnings, error) {
+	volumeresizelog.Info("Validation for VolumeResize upon update", "name", newVR.GetName())
+
+	// Metadata only updates, such as the finalizer, must go through even once the StatefulSet is deleted
+	if equality.Semantic.DeepEqual(oldVR.Spec, newVR.Spec) {
+		return nil, nil
+	}
+
+	if migrationStarted(oldVR) {
+		if !equality.Semantic.DeepEqual(withoutControls(oldVR.Spec), withoutControls(newVR.Spec)) {
+			return nil, toInvalid(newVR, field.ErrorList{field.Forbidden(field.NewPath("spec"),
+				fmt.Sprintf("only paused, abort, retryGeneration, schedule, approval, healthGates and hooks may change once the migration is %s",
+					oldVR.Status.Phase))})
+		}
+		allErrs := controller.ValidateSchedule(newVR.Spec.Schedule, field.NewPath("spec", "schedule"))
+		allErrs = append(allErrs, controller.ValidateHealthGates(newVR.Spec.HealthGates, field.NewPath("spec", "healthGates"))...)
+		allErrs = append(allErrs, controller.ValidateHooks(newVR.Spec.Hooks, field.NewPath("spec", "hooks"))...)
+		return nil, toInvalid(newVR, allErrs)
+	}
+
+	allErrs := validateSpec(newVR)
+	allErrs = append(allErrs, v.validateTarget(ctx, newVR)...)
+	return nil, toInvalid(newVR, allErrs)
+}
+
+// ValidateDelete does nothing, the finalizer takes care of putting the StatefulSet back
+func (v *VolumeResizeCustomValidator) ValidateDelete(_ context.Context, _ *storagev1alpha1.VolumeResize) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// migrationStarted reports whether the controller has picked the VolumeResize up past Pending
+func migrationStarted(vr *storagev1alpha1.VolumeResize) bool {
+	return vr.Status.Phase != "" && vr.Status.Phase != controller.PhasePending
+}
+
+// withoutControls returns the spec with the fields meant to be changed during a migration cleared
+func withoutControls(spec storagev1alpha1.VolumeResizeSpec) storagev1alpha1.VolumeResizeSpec {
+	spec.Paused = false
+	spec.Abort = false
+	spec.RetryGeneration = 0
+	spec.Schedule = nil
+	spec.Approval = ""
+	spec.HealthGates = nil
+	spec.Hooks = nil
+	return spec
+}
+
+// validateSpec checks the spec on its own: volume names are unique, sizes are positive, the canary goes with
+// a rolling migration, the schedule parses, every health gate and hook has one action, and the order is complete
+func validateSpec(vr *storagev1alpha1.VolumeResize) field.ErrorList {
+	var allErrs field.ErrorList
+	volumesPath := field.NewPath("spec", "volumes")
+
+	seen := map[string]bool{}
+	for i, vol := range vr.Spec.Volumes {
+		path := volumesPath.Index(i)
+		if seen[vol.Name] {
+			allErrs = append(allErrs, field.Duplicate(path.Child("name"), vol.Name))
+		}
+		seen[vol.Name] = true
+
+		if vol.NewSize.Sign() <= 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("newSize"), vol.NewSize.String(), "must be greater than zero"))
+		}
+		if vol.Block != nil && vol.Block.Length != nil && vol.Block.Length.Sign() <= 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("block", "length"), vol.Block.Length.String(), "must be greater than zero"))
+		}
+	}
+	if vr.Spec.Canary != nil && vr.Spec.Strategy != nil && vr.Spec.Strategy.Type == controller.StrategyTypeOffline {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "canary"),
+			fmt.Sprintf("not supported by the %s strategy", controller.StrategyTypeOffline)))
+	}
+	allErrs = append(allErrs, controller.ValidateSchedule(vr.Spec.Schedule, field.NewPath("spec", "schedule"))...)
+	allErrs = append(allErrs, controller.ValidateHealthGates(vr.Spec.HealthGates, field.NewPath("spec", "healthGates"))...)
+	allErrs = append(allErrs, controller.ValidateHooks(vr.Spec.Hooks, field.NewPath("spec", "hooks"))...)
+	allErrs = append(allErrs, controller.ValidateOrder(vr.Spec.Order, field.NewPath("spec", "order"))...)
+	allErrs = append(allErrs, controller.ValidateReplicas(vr.Spec.Replicas, field.NewPath("spec", "replicas"))...)
+	return append(allErrs, validateSubset(vr)...)
+}
+
+// validateSubset checks the canary and the explicit order only name replicas of spec.replicas
+func validateSubset(vr *storagev1alpha1.VolumeResize) field.ErrorList {
+	if len(vr.Spec.Replicas) == 0 {
+		return nil
+	}
+	ordinals, err := controller.ParseReplicas(vr.Spec.Replicas)
+	if err != nil {
+		// Already reported by ValidateReplicas
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	if vr.Spec.Canary != nil && vr.Spec.Canary.Replica != nil && !slices.Contains(ordinals, *vr.Spec.Canary.Replica) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "canary", "replica"), *vr.Spec.Canary.Replica,
+			"must be one of spec.replicas"))
+	}
+	if vr.Spec.Order != nil && vr.Spec.Order.Type == controller.OrderExplicit {
+		for i, replica := range vr.Spec.Order.Replicas {
+			if !slices.Contains(ordinals, replica) {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "order", "replicas").Index(i), replica,
+					"must be one of spec.replicas"))
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateTarget checks the StatefulSet and its volumeClaimTemplates exist, and that it is not being migrated already
+func (v *VolumeResizeCustomValidator) validateTarget(ctx context.Context, vr *storagev1alpha1.VolumeResize) field.ErrorList {
+	var allErrs field.ErrorList
+	stsPath := field.NewPath("spec", "statefulSetName")
+
+	sts := &appsv1.StatefulSet{}
+	err := v.Client.Get(ctx, types.NamespacedName{Namespace: vr.Namespace, Name: vr.Spec.StatefulSetName}, sts)
+	if apierrors.IsNotFound(err) {
+		return append(allErrs, field.NotFound(stsPath, vr.Spec.StatefulSetName))
+	}
+	if err != nil {
+		return append(allErrs, field.InternalError(stsPath, err))
+	}
+
+	templates := map[string]bool{}
+	for _, vct := range sts.Spec.VolumeClaimTemplates {
+		templates[vct.Name] = true
+	}
+	for i, vol := range vr.Spec.Volumes {
+		if !templates[vol.Name] {
+			allErrs = append(allErrs, field.NotFound(field.NewPath("spec", "volumes").Index(i).Child("name"), vol.Name))
+		}
+	}
+
+	replicas := int32(1)
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+	if vr.Spec.Canary != nil && vr.Spec.Canary.Replica != nil && *vr.Spec.Canary.Replica >= replicas {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "canary", "replica"), *vr.Spec.Canary.Replica,
+			fmt.Sprintf("StatefulSet %s has %d replicas", sts.Name, replicas)))
+	}
+	if vr.Spec.Order != nil && vr.Spec.Order.Type == controller.OrderExplicit {
+		for i, replica := range vr.Spec.Order.Replicas {
+			if replica >= replicas {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "order", "replicas").Index(i), replica,
+					fmt.Sprintf("StatefulSet %s has %d replicas", sts.Name, replicas)))
+			}
+		}
+	}
+	if ordinals, err := controller.ParseReplicas(vr.Spec.Replicas); err == nil {
+		for _, replica := range ordinals {
+			if replica >= replicas {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "replicas"), vr.Spec.Replicas,
+					fmt.Sprintf("replica %d does not exist, StatefulSet %s has %d replicas", replica, sts.Name, replicas)))
+				break
+			}
+		}
+	}
+
+	vrList := &storagev1alpha1.VolumeResizeList{}
+	if err := v.Client.List(ctx, vrList, client.InNamespace(vr.Namespace)); err != nil {
+		return append(allErrs, field.InternalError(stsPath, err))
+	}
+	for _, other := range vrList.Items {
+		if other.Name == vr.Name || other.Spec.StatefulSetName != vr.Spec.StatefulSetName {
+			continue
+		}
+		// A Failed migration still holds the StatefulSet until it is retried or deleted
+		if other.Status.Phase == controller.PhaseCompleted || other.Status.Phase == controller.PhaseAborted {
+			continue
+		}
+		allErrs = append(allErrs, field.Forbidden(stsPath,
+			fmt.Sprintf("StatefulSet %s is already being migrated by VolumeResize %s", vr.Spec.StatefulSetName, other.Name)))
+	}
+	return allErrs
+}
+
+func toInvalid(vr *storagev1alpha1.VolumeResize, allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(storagev1alpha1.GroupVersion.WithKind("VolumeResize").GroupKind(), vr.Name, allErrs)
+}
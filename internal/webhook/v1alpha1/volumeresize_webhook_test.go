@@ -0,0 +1,241 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
+	"github.com/thomas-maurice/migcontroller/internal/controller"
+)
+
+func newTestValidator(t *testing.T, objs ...client.Object) *VolumeResizeCustomValidator {
+	scheme := runtime.NewScheme()
+	require.NoError(t, storagev1alpha1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sts", Namespace: "default"},
+		Spec: appsv1.StatefulSetSpec{
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+				{ObjectMeta: metav1.ObjectMeta{Name: "data"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "logs"}},
+			},
+		},
+	}
+	objs = append(objs, sts)
+	return &VolumeResizeCustomValidator{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+	}
+}
+
+func testVR(name string, volumes ...storagev1alpha1.VolumeResizeTarget) *storagev1alpha1.VolumeResize {
+	if len(volumes) == 0 {
+		volumes = []storagev1alpha1.VolumeResizeTarget{{Name: "data", NewSize: resource.MustParse("500Mi")}}
+	}
+	return &storagev1alpha1.VolumeResize{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: storagev1alpha1.VolumeResizeSpec{
+			StatefulSetName: "test-sts",
+			Volumes:         volumes,
+		},
+	}
+}
+
+func withSchedule(vr *storagev1alpha1.VolumeResize, start string) *storagev1alpha1.VolumeResize {
+	vr.Spec.Schedule = &storagev1alpha1.ScheduleSpec{
+		Windows: []storagev1alpha1.MaintenanceWindow{{Start: start, Duration: metav1.Duration{Duration: 4 * time.Hour}}},
+	}
+	return vr
+}
+
+func withCanary(vr *storagev1alpha1.VolumeResize, replica int32, strategyType string) *storagev1alpha1.VolumeResize {
+	vr.Spec.Canary = &storagev1alpha1.CanarySpec{Replica: &replica}
+	vr.Spec.Strategy = &storagev1alpha1.MigrationStrategy{Type: strategyType}
+	return vr
+}
+
+func withHealthGate(vr *storagev1alpha1.VolumeResize, gate storagev1alpha1.HealthGate) *storagev1alpha1.VolumeResize {
+	vr.Spec.HealthGates = append(vr.Spec.HealthGates, gate)
+	return vr
+}
+
+func withHooks(vr *storagev1alpha1.VolumeResize, hooks *storagev1alpha1.MigrationHooks) *storagev1alpha1.VolumeResize {
+	vr.Spec.Hooks = hooks
+	return vr
+}
+
+func withOrder(vr *storagev1alpha1.VolumeResize, orderType string, replicas ...int32) *storagev1alpha1.VolumeResize {
+	vr.Spec.Order = &storagev1alpha1.ReplicaOrder{Type: orderType, Replicas: replicas}
+	return vr
+}
+
+func withReplicas(vr *storagev1alpha1.VolumeResize, replicas ...string) *storagev1alpha1.VolumeResize {
+	vr.Spec.Replicas = replicas
+	return vr
+}
+
+func TestValidateCreate(t *testing.T) {
+	v := newTestValidator(t)
+	ctx := context.Background()
+
+	_, err := v.ValidateCreate(ctx, testVR("resize"))
+	require.NoError(t, err)
+	_, err = v.ValidateCreate(ctx, withCanary(testVR("resize"), 0, controller.StrategyTypeRolling))
+	require.NoError(t, err)
+	_, err = v.ValidateCreate(ctx, withOrder(testVR("resize"), controller.OrderExplicit, 0))
+	require.NoError(t, err)
+	_, err = v.ValidateCreate(ctx, withReplicas(testVR("resize"), "0-0"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		vr       *storagev1alpha1.VolumeResize
+		expected string
+	}{
+		{"duplicate volume", testVR("resize",
+			storagev1alpha1.VolumeResizeTarget{Name: "data", NewSize: resource.MustParse("500Mi")},
+			storagev1alpha1.VolumeResizeTarget{Name: "data", NewSize: resource.MustParse("400Mi")},
+		), "Duplicate value"},
+		{"zero size", testVR("resize",
+			storagev1alpha1.VolumeResizeTarget{Name: "data", NewSize: resource.MustParse("0")},
+		), "must be greater than zero"},
+		{"unknown template", testVR("resize",
+			storagev1alpha1.VolumeResizeTarget{Name: "cache", NewSize: resource.MustParse("500Mi")},
+		), "spec.volumes[0].name: Not found"},
+		{"invalid window", withSchedule(testVR("resize"), "0 25 * * *"), "spec.schedule.windows[0].start"},
+		{"offline canary", withCanary(testVR("resize"), 0, controller.StrategyTypeOffline), "spec.canary: Forbidden"},
+		{"unknown canary", withCanary(testVR("resize"), 1, controller.StrategyTypeRolling), "spec.canary.replica: Invalid value"},
+		{"health gate with two checks", withHealthGate(testVR("resize"), storagev1alpha1.HealthGate{
+			Name:    "ready",
+			HTTPGet: &storagev1alpha1.HTTPGetHealthGate{Port: intstr.FromInt32(8080)},
+			Exec:    &storagev1alpha1.ExecHealthGate{Command: []string{"true"}},
+		}), "spec.healthGates[0]: Invalid value"},
+		{"exec hook after the copy", withHooks(testVR("resize"), &storagev1alpha1.MigrationHooks{
+			PostCopy: []storagev1alpha1.ReplicaHook{{Name: "checkpoint", Exec: &storagev1alpha1.ExecHealthGate{Command: []string{"true"}}}},
+		}), "spec.hooks.postCopy[0].exec: Forbidden"},
+		{"unknown ordered replica", withOrder(testVR("resize"), controller.OrderExplicit, 0, 1), "spec.order.replicas[1]: Invalid value"},
+		{"leader last without leader", withOrder(testVR("resize"), controller.OrderLeaderLast), "spec.order.leader: Required value"},
+		{"reversed replica range", withReplicas(testVR("resize"), "3-1"), "spec.replicas[0]: Invalid value"},
+		{"replica selected twice", withReplicas(testVR("resize"), "0", "0-1"), "replica 0 is already selected"},
+		{"unknown replica", withReplicas(testVR("resize"), "0-1"), "replica 1 does not exist"},
+		{"canary left out", withReplicas(withCanary(testVR("resize"), 0, controller.StrategyTypeRolling), "1"),
+			"spec.canary.replica: Invalid value: 0: must be one of spec.replicas"},
+	}
+	for _, tt := range tests {
+		_, err := v.ValidateCreate(ctx, tt.vr)
+		require.Error(t, err, tt.name)
+		assert.Contains(t, err.Error(), tt.expected, tt.name)
+	}
+
+	missing := testVR("resize")
+	missing.Spec.StatefulSetName = "nonexistent"
+	_, err = v.ValidateCreate(ctx, missing)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "spec.statefulSetName: Not found")
+}
+
+func TestValidateCreateConcurrentMigration(t *testing.T) {
+	running := testVR("running")
+	running.Status.Phase = controller.PhaseSyncing
+	done := testVR("done")
+	done.Status.Phase = controller.PhaseCompleted
+
+	v := newTestValidator(t, done)
+	_, err := v.ValidateCreate(context.Background(), testVR("resize"))
+	require.NoError(t, err)
+
+	v = newTestValidator(t, running, done)
+	_, err = v.ValidateCreate(context.Background(), testVR("resize"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already being migrated by VolumeResize running")
+}
+
+func TestValidateUpdate(t *testing.T) {
+	v := newTestValidator(t)
+	ctx := context.Background()
+
+	oldVR := testVR("resize")
+	oldVR.Status.Phase = controller.PhaseSyncing
+
+	// The controls may change mid-migration
+	newVR := oldVR.DeepCopy()
+	newVR.Spec.Paused = true
+	newVR.Spec.RetryGeneration = 2
+	newVR.Spec.Approval = controller.ApprovalPerReplica
+	_, err := v.ValidateUpdate(ctx, oldVR, newVR)
+	require.NoError(t, err)
+
+	// The schedule may be moved mid-migration, as long as it parses
+	newVR = withSchedule(oldVR.DeepCopy(), "0 1 * * *")
+	_, err = v.ValidateUpdate(ctx, oldVR, newVR)
+	require.NoError(t, err)
+	newVR = withSchedule(oldVR.DeepCopy(), "0 1 * *")
+	_, err = v.ValidateUpdate(ctx, oldVR, newVR)
+	require.Error(t, err)
+
+	// So may the health gates
+	newVR = withHealthGate(oldVR.DeepCopy(), storagev1alpha1.HealthGate{
+		Name: "quorum",
+		Job:  &storagev1alpha1.JobHealthGate{Image: "cassandra-check"},
+	})
+	_, err = v.ValidateUpdate(ctx, oldVR, newVR)
+	require.NoError(t, err)
+	newVR = withHealthGate(oldVR.DeepCopy(), storagev1alpha1.HealthGate{Name: "quorum"})
+	_, err = v.ValidateUpdate(ctx, oldVR, newVR)
+	require.Error(t, err)
+
+	// And the hooks
+	newVR = withHooks(oldVR.DeepCopy(), &storagev1alpha1.MigrationHooks{
+		PreStop: []storagev1alpha1.ReplicaHook{{Name: "flush", Exec: &storagev1alpha1.ExecHealthGate{Command: []string{"flush"}}}},
+	})
+	_, err = v.ValidateUpdate(ctx, oldVR, newVR)
+	require.NoError(t, err)
+
+	newVR = oldVR.DeepCopy()
+	newVR.Spec.Volumes[0].NewSize = resource.MustParse("400Mi")
+	_, err = v.ValidateUpdate(ctx, oldVR, newVR)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "once the migration is Syncing")
+
+	// Finalizer updates go through even when the StatefulSet is gone
+	oldVR.Spec.StatefulSetName = "deleted-sts"
+	newVR = oldVR.DeepCopy()
+	newVR.Finalizers = nil
+	_, err = v.ValidateUpdate(ctx, oldVR, newVR)
+	require.NoError(t, err)
+
+	// Before validation starts the spec can still be fixed
+	pending := testVR("resize")
+	fixed := pending.DeepCopy()
+	fixed.Spec.Volumes[0].Name = "logs"
+	_, err = v.ValidateUpdate(ctx, pending, fixed)
+	require.NoError(t, err)
+}
@@ -23,7 +23,9 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	storagev1alpha1 "github.com/thomas-maurice/migcontroller/api/v1alpha1"
@@ -57,18 +59,9 @@ func (s *IntegrationTestSuite) TestValidationFailureVolumeNotFound() {
 	s.waitForSTSReady("test-sts-vol", time.Minute*2)
 
 	// Create VolumeResize targeting volume "nonexistent"
-	s.createVolumeResize("resize-vol", "test-sts-vol", []storagev1alpha1.VolumeResizeTarget{
+	s.expectRejectedOrFailed("resize-vol", "test-sts-vol", []storagev1alpha1.VolumeResizeTarget{
 		{Name: "nonexistent", NewSize: resource.MustParse("500Mi")},
-	})
-
-	// Wait for Failed phase
-	s.waitForVolumeResizePhase("resize-vol", "Failed", time.Minute*2)
-
-	// Verify message mentions volume not found
-	vr := &storagev1alpha1.VolumeResize{}
-	err := s.client.Get(s.ctx, types.NamespacedName{Namespace: s.namespace, Name: "resize-vol"}, vr)
-	require.NoError(s.T(), err)
-	s.Contains(vr.Status.Message, "not found")
+	}, "not found", "Not found")
 }
 
 // TestValidationFailurePDBBlocking tests PDB enforcement
@@ -101,16 +94,29 @@ func (s *IntegrationTestSuite) TestValidationFailurePDBBlocking() {
 // TestValidationFailureStatefulSetNotFound tests StatefulSet validation
 func (s *IntegrationTestSuite) TestValidationFailureStatefulSetNotFound() {
 	// Create VolumeResize targeting non-existent StatefulSet
-	s.createVolumeResize("resize-noexist", "nonexistent-sts", []storagev1alpha1.VolumeResizeTarget{
+	s.expectRejectedOrFailed("resize-noexist", "nonexistent-sts", []storagev1alpha1.VolumeResizeTarget{
 		{Name: "data", NewSize: resource.MustParse("500Mi")},
-	})
-
-	// Wait for Failed phase
-	s.waitForVolumeResizePhase("resize-noexist", "Failed", time.Minute*2)
+	}, "not found", "Not found")
+}
 
-	// Verify message mentions StatefulSet not found
-	vr := &storagev1alpha1.VolumeResize{}
-	err := s.client.Get(s.ctx, types.NamespacedName{Namespace: s.namespace, Name: "resize-noexist"}, vr)
+// expectRejectedOrFailed checks a VolumeResize is refused by the admission webhook, or by the controller
+// when the operator runs without webhooks
+func (s *IntegrationTestSuite) expectRejectedOrFailed(name, stsName string, volumes []storagev1alpha1.VolumeResizeTarget, statusMessage, admissionMessage string) {
+	vr := &storagev1alpha1.VolumeResize{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: s.namespace},
+		Spec: storagev1alpha1.VolumeResizeSpec{
+			StatefulSetName: stsName,
+			Volumes:         volumes,
+		},
+	}
+	if err := s.client.Create(s.ctx, vr); err != nil {
+		s.True(apierrors.IsInvalid(err), "unexpected error: %v", err)
+		s.Contains(err.Error(), admissionMessage)
+		return
+	}
+
+	s.waitForVolumeResizePhase(name, "Failed", time.Minute*2)
+	err := s.client.Get(s.ctx, types.NamespacedName{Namespace: s.namespace, Name: name}, vr)
 	require.NoError(s.T(), err)
-	s.Contains(vr.Status.Message, "not found")
+	s.Contains(vr.Status.Message, statusMessage)
 }